@@ -1,30 +1,219 @@
 package graphql
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"time"
 
-	"github.com/ng-vu/graphql-go/internal/debug"
 	"github.com/ng-vu/graphql-go/internal/execution"
 	"github.com/ng-vu/graphql-go/internal/language"
 	"github.com/ng-vu/graphql-go/internal/types"
 	"github.com/ng-vu/graphql-go/internal/validation"
+	"github.com/ng-vu/graphql-go/internal/validation/rules"
 	"github.com/ng-vu/graphql-go/ql"
 )
 
-var LOG = debug.New("graphql")
-
 type RequestOpts struct {
 	RootValue      interface{}
 	VariableValues map[string]interface{}
 	OperationName  string
+
+	// Context is given to SchemaOpts.AllowIntrospection, to the middlewares
+	// and to the directive resolve functions. Defaults to
+	// context.Background().
+	Context context.Context
+
+	// Middlewares wrap the resolution of every field of this request, inside
+	// the middlewares of the schema.
+	Middlewares []ql.Middleware
+
+	// Limits overrides the limits of the schema for this request. Only the
+	// non-zero values are used, so a request can raise or lower a limit of the
+	// schema but not remove it, and cannot turn IgnoreIntrospectionDepth off.
+	Limits Limits
+
+	// Tracing enables SchemaOpts.Tracing for this request.
+	Tracing bool
+
+	// Tracer overrides the tracer of the schema for this request.
+	Tracer Tracer
+
+	// Logger overrides the logger of the schema for this request.
+	Logger ql.Logger
+}
+
+type SchemaOpts struct {
+	// Limits applies to every request made against the schema.
+	Limits Limits
+
+	// Cost configures how the cost of a request is computed for the MaxCost
+	// limit.
+	Cost CostOpts
+
+	// DisableIntrospection rejects every request selecting the __schema or
+	// __type fields. The __typename field is still allowed.
+	DisableIntrospection bool
+
+	// AllowIntrospection, when set, is called with the context of each request
+	// and the request may only select __schema or __type if it returns true.
+	AllowIntrospection func(ctx context.Context) bool
+
+	// Directives are supported by the schema in addition to @include and
+	// @skip. They are checked by validation and listed by introspection.
+	Directives []ql.Directive
+
+	// Middlewares wrap the resolution of every field, the first one being the
+	// outermost. They are called before the directive resolve functions.
+	Middlewares []ql.Middleware
+
+	// Tracing reports the time spent parsing, validating and in each resolver
+	// in the "tracing" extension of the result, in the Apollo tracing format.
+	Tracing bool
+
+	// Tracer is given the spans of every request made against the schema.
+	Tracer Tracer
+
+	// Logger receives the logs of the requests made against the schema, such
+	// as the errors recovered while executing them. Nothing is logged by
+	// default.
+	Logger ql.Logger
+
+	// Metrics receives the number of operations executed and of validation
+	// failures, and the latency and errors of the resolvers.
+	Metrics Metrics
+
+	// SlowQuery reports the requests taking longer than its threshold.
+	SlowQuery SlowQueryOpts
+}
+
+func (opts SchemaOpts) allowIntrospection(ctx context.Context) bool {
+	if opts.DisableIntrospection {
+		return false
+	}
+	return opts.AllowIntrospection == nil || opts.AllowIntrospection(ctx)
+}
+
+/**
+ * The cost of a field is its ql.Field.Cost, or DefaultCost if not set, plus
+ * the cost of its sub selections multiplied by the number of items requested
+ * from it, as given by the first of its ListSizeArguments.
+ */
+type CostOpts struct {
+	// DefaultCost defaults to 1. Set it to ql.FreeCost to only count the
+	// fields which define their own cost.
+	DefaultCost int
+
+	// ListSizeArguments defaults to "first", "last" and "limit".
+	ListSizeArguments []string
+}
+
+/**
+ * Limits restricts the shape of the requests accepted by NewRequest, which
+ * reports a validation error for a request exceeding any of them. A zero
+ * value means no limit.
+ */
+type Limits struct {
+	// MaxTokens and MaxNesting restrict the request document while it is
+	// parsed, before validation. MaxNesting applies to selection sets, list
+	// and object values, and list types.
+	MaxTokens  int
+	MaxNesting int
+
+	// MaxDepth is the maximum nesting of fields in an operation, following
+	// fragment spreads. Top level fields are at depth 1.
+	MaxDepth int
+
+	// IgnoreIntrospectionDepth excludes the introspection fields, such as
+	// __schema and __type, from the depth limit.
+	IgnoreIntrospectionDepth bool
+
+	// MaxCost is the maximum cost of an operation, computed before executing
+	// it. When set, the cost is also reported in the "cost" extension of the
+	// result.
+	MaxCost int
+
+	// MaxAliases and MaxFields are the maximum numbers of aliases and of
+	// selected fields in an operation. The selections of a fragment are
+	// counted each time it is spread.
+	MaxAliases int
+	MaxFields  int
+
+	// MaxDirectives is the maximum number of times a directive may be applied
+	// to the same operation, field, fragment or fragment spread.
+	MaxDirectives int
+
+	// MaxRootFields is the maximum number of fields selected on the root type
+	// of an operation.
+	MaxRootFields int
+}
+
+/**
+ * Returns the limits with the non-zero values of other taking precedence. A
+ * zero value in other keeps the limit of l, and IgnoreIntrospectionDepth is
+ * kept when set in either of them.
+ */
+func (l Limits) merge(other Limits) Limits {
+	if other.MaxTokens != 0 {
+		l.MaxTokens = other.MaxTokens
+	}
+	if other.MaxNesting != 0 {
+		l.MaxNesting = other.MaxNesting
+	}
+	if other.MaxDepth != 0 {
+		l.MaxDepth = other.MaxDepth
+	}
+	if other.IgnoreIntrospectionDepth {
+		l.IgnoreIntrospectionDepth = true
+	}
+	if other.MaxCost != 0 {
+		l.MaxCost = other.MaxCost
+	}
+	if other.MaxAliases != 0 {
+		l.MaxAliases = other.MaxAliases
+	}
+	if other.MaxFields != 0 {
+		l.MaxFields = other.MaxFields
+	}
+	if other.MaxDirectives != 0 {
+		l.MaxDirectives = other.MaxDirectives
+	}
+	if other.MaxRootFields != 0 {
+		l.MaxRootFields = other.MaxRootFields
+	}
+	return l
+}
+
+func (l Limits) validationRules() []validation.RuleCreator {
+	var result []validation.RuleCreator
+	if l.MaxDepth > 0 {
+		result = append(result, rules.MaxDepth(l.MaxDepth, l.IgnoreIntrospectionDepth))
+	}
+	if l.MaxAliases > 0 {
+		result = append(result, rules.MaxAliases(l.MaxAliases))
+	}
+	if l.MaxFields > 0 {
+		result = append(result, rules.MaxFields(l.MaxFields))
+	}
+	if l.MaxDirectives > 0 {
+		result = append(result, rules.MaxDirectives(l.MaxDirectives))
+	}
+	if l.MaxRootFields > 0 {
+		result = append(result, rules.MaxRootFields(l.MaxRootFields))
+	}
+	return result
 }
 
 type Schema struct {
 	schema types.QLSchema
+	opts   SchemaOpts
 }
 
 func NewSchema(query ql.Object, mutations ...ql.Object) (Schema, error) {
+	return NewSchemaWithOpts(SchemaOpts{}, query, mutations...)
+}
+
+func NewSchemaWithOpts(opts SchemaOpts, query ql.Object, mutations ...ql.Object) (Schema, error) {
 	if len(mutations) > 1 {
 		panic("graphql: must provide only one mutation object")
 	}
@@ -33,14 +222,67 @@ func NewSchema(query ql.Object, mutations ...ql.Object) (Schema, error) {
 		mutation = new(ql.Object)
 		*mutation = mutations[0]
 	}
-	schema := types.NewQLSchema(query, mutation)
-	return Schema{schema}, nil
+	schema := types.NewQLSchema(query, mutation, opts.Directives...)
+	return Schema{schema, opts}, nil
+}
+
+/**
+ * Returns a view of the schema for the given audiences. It only contains the
+ * types, fields, arguments, input fields and enum values which are untagged
+ * or whose Visibility lists one of the audiences, and the types still
+ * reachable from the root types. Requests made against the view are
+ * validated, executed and introspected as if the hidden elements did not
+ * exist.
+ *
+ * Building a view is about as expensive as building the schema, so views
+ * should be built once per audience and reused.
+ */
+func (s Schema) ForAudience(audiences ...string) (result Schema, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			if e, ok := e.(error); ok {
+				err = e
+				return
+			}
+			panic(e)
+		}
+	}()
+
+	audienceSet := make(map[string]struct{}, len(audiences))
+	for _, audience := range audiences {
+		audienceSet[audience] = struct{}{}
+	}
+	schema := s.schema.Filter(func(tags []string) bool {
+		for _, tag := range tags {
+			if _, ok := audienceSet[tag]; ok {
+				return true
+			}
+		}
+		return false
+	})
+	return Schema{schema, s.opts}, nil
 }
 
 type Request struct {
 	schema      types.QLSchema
+	schemaOpts  SchemaOpts
 	documentAST *language.Document
+	plan        *execution.Plan
 	opts        RequestOpts
+	limits      Limits
+	cost        int
+	tracing     *_Tracing
+	tracer      Tracer
+	logger      ql.Logger
+
+	// The time spent in NewRequest, for the slow query log.
+	prepareDuration time.Duration
+}
+
+type Result struct {
+	Data       interface{}
+	Errors     []error
+	Extensions map[string]interface{}
 }
 
 func NewRequest(schema Schema, request string, opts ...RequestOpts) (*Request, Errors) {
@@ -50,15 +292,57 @@ func NewRequest(schema Schema, request string, opts ...RequestOpts) (*Request, E
 	} else if len(opts) == 1 {
 		_opts = opts[0]
 	}
+	if _opts.Context == nil {
+		_opts.Context = context.Background()
+	}
+
+	startTime := time.Now()
+	var tracing *_Tracing
+	if schema.opts.Tracing || _opts.Tracing {
+		tracing = newTracing()
+	}
+	tracer := _opts.Tracer
+	if tracer == nil {
+		tracer = schema.opts.Tracer
+	}
+	logger := _opts.Logger
+	if logger == nil {
+		logger = schema.opts.Logger
+	}
 
+	limits := schema.opts.Limits.merge(_opts.Limits)
 	source := language.NewSource(request, "GraphQL request")
-	documentAST, err := language.Parse(source)
+	_, span := startSpan(tracer, _opts.Context, "graphql.parse", map[string]interface{}{
+		"graphql.document": request,
+	})
+	endParsing := tracing.startParsing()
+	documentAST, err := language.Parse(source, language.ParseOptions{
+		MaxTokens: limits.MaxTokens,
+		MaxDepth:  limits.MaxNesting,
+	})
+	endParsing()
 	if err != nil {
+		span.SetAttributes(map[string]interface{}{"graphql.error": err.Error()})
+		span.End()
 		return nil, _Errors{[]error{err}}
 	}
+	span.End()
 
-	validationErrors := validation.Validate(schema.schema, documentAST, nil)
+	validationRules := append(rules.Rules[:len(rules.Rules):len(rules.Rules)],
+		limits.validationRules()...)
+	if !schema.opts.allowIntrospection(_opts.Context) {
+		validationRules = append(validationRules, rules.NoIntrospection)
+	}
+	_, span = startSpan(tracer, _opts.Context, "graphql.validate", nil)
+	endValidation := tracing.startValidation()
+	validationErrors := validation.Validate(schema.schema, documentAST, validationRules)
+	endValidation()
+	span.SetAttributes(map[string]interface{}{"graphql.errors": len(validationErrors)})
+	span.End()
 	if validationErrors != nil {
+		if schema.opts.Metrics != nil {
+			schema.opts.Metrics.IncCounter(MetricValidationFailures, nil)
+		}
 		errs := make([]error, len(validationErrors))
 		for i, e := range validationErrors {
 			errs[i] = e
@@ -66,20 +350,144 @@ func NewRequest(schema Schema, request string, opts ...RequestOpts) (*Request, E
 		return nil, _Errors{errs}
 	}
 
-	return &Request{schema.schema, documentAST, _opts}, nil
+	plan, err := execution.Prepare(schema.schema, documentAST, _opts.OperationName)
+	if err != nil {
+		return nil, _Errors{[]error{err}}
+	}
+
+	req := &Request{
+		schema:      schema.schema,
+		schemaOpts:  schema.opts,
+		documentAST: documentAST,
+		plan:        plan,
+		opts:        _opts,
+		limits:      limits,
+		tracing:     tracing,
+		tracer:      tracer,
+		logger:      logger,
+	}
+	if limits.MaxCost > 0 {
+		req.cost = plan.Cost(_opts.VariableValues, execution.CostOptions{
+			DefaultCost:       schema.opts.Cost.DefaultCost,
+			ListSizeArguments: schema.opts.Cost.ListSizeArguments,
+		})
+		if req.cost > limits.MaxCost {
+			err := language.NewQLError(
+				fmt.Sprintf(`Query cost %v exceeds the maximum cost of %v.`, req.cost, limits.MaxCost),
+				[]language.INode{plan.Operation})
+			return nil, _Errors{[]error{err}}
+		}
+	}
+	req.prepareDuration = time.Since(startTime)
+	return req, nil
 }
 
 func (r *Request) Print() string {
 	return language.Print(r.documentAST)
 }
 
-func (r *Request) Run(value interface{}) error {
+/**
+ * Executes the request, returning the data as nested maps along with the
+ * errors which occurred during execution.
+ */
+func (r *Request) Execute() Result {
+	startTime := time.Now()
+	operationName := ""
+	if r.plan.Operation.Name != nil {
+		operationName = r.plan.Operation.Name.Value
+	}
+	ctx, span := startSpan(r.tracer, r.opts.Context, "graphql.execute", map[string]interface{}{
+		"graphql.operation.type": string(r.plan.Operation.Operation),
+		"graphql.operation.name": operationName,
+	})
+	defer span.End()
+
+	if metrics := r.schemaOpts.Metrics; metrics != nil {
+		metrics.IncCounter(MetricOperations, map[string]string{
+			"operation": string(r.plan.Operation.Operation),
+		})
+	}
+
+	var tracing *_ExecutionTracing
+	if r.tracing != nil {
+		tracing = r.tracing.execution()
+	}
+	var slowResolvers *_SlowResolvers
+	if r.schemaOpts.SlowQuery.Threshold > 0 {
+		slowResolvers = &_SlowResolvers{max: r.schemaOpts.SlowQuery.MaxResolvers}
+		if slowResolvers.max <= 0 {
+			slowResolvers.max = 5
+		}
+	}
 	opts := execution.Options{
 		RootValue:      r.opts.RootValue,
 		VariableValues: r.opts.VariableValues,
-		OperationName:  r.opts.OperationName,
+		Context:        ctx,
+		Middlewares:    r.middlewares(tracing, slowResolvers),
+		Logger:         r.logger,
+	}
+	result := execution.ExecutePlan(r.plan, opts)
+	if slowResolvers != nil {
+		duration := r.prepareDuration + time.Since(startTime)
+		if duration > r.schemaOpts.SlowQuery.Threshold {
+			r.logSlowQuery(r.opts.Context, duration, slowResolvers)
+		}
+	}
+	span.SetAttributes(map[string]interface{}{"graphql.errors": len(result.Errors)})
+
+	extensions := result.Extensions
+	if tracing != nil {
+		if extensions == nil {
+			extensions = make(map[string]interface{})
+		}
+		extensions["tracing"] = tracing.toMap()
+	}
+	if r.limits.MaxCost > 0 {
+		if extensions == nil {
+			extensions = make(map[string]interface{})
+		}
+		extensions["cost"] = map[string]interface{}{
+			"requested": r.cost,
+			"maximum":   r.limits.MaxCost,
+		}
 	}
-	result := execution.Execute(r.schema, r.documentAST, opts)
+	return Result{
+		Data:       result.Data,
+		Errors:     result.Errors,
+		Extensions: extensions,
+	}
+}
+
+/**
+ * Returns the middlewares of the schema and of the request, wrapped by the
+ * one of the tracer and wrapping the ones of the metrics, the slow query log
+ * and the Apollo tracing.
+ */
+func (r *Request) middlewares(tracing *_ExecutionTracing, slowResolvers *_SlowResolvers) []ql.Middleware {
+	var result []ql.Middleware
+	if r.tracer != nil {
+		result = append(result, tracerMiddleware(r.tracer))
+	}
+	result = append(result, r.schemaOpts.Middlewares...)
+	result = append(result, r.opts.Middlewares...)
+	if r.schemaOpts.Metrics != nil {
+		result = append(result, metricsMiddleware(r.schemaOpts.Metrics))
+	}
+	if slowResolvers != nil {
+		result = append(result, slowResolvers.middleware)
+	}
+	if tracing != nil {
+		result = append(result, tracing.middleware)
+	}
+	return result
+}
+
+/**
+ * Executes the request and stores the result in value, which must be a
+ * pointer to a struct whose fields are tagged with the response names.
+ */
+func (r *Request) Run(value interface{}) error {
+	result := r.Execute()
 
 	if len(result.Errors) > 0 {
 		errors := make([]error, len(result.Errors))
@@ -113,12 +521,18 @@ func (r *Request) Run(value interface{}) error {
 		if elemField.Type().AssignableTo(tField.Type) {
 			vField.Set(elemField)
 		} else {
-			LOG.Println("Cannot assign value to field", tField.Name, resultField)
+			r.log(ql.LogWarn, "Cannot assign value to field", "field", tField.Name, "value", resultField)
 		}
 	}
 	return nil
 }
 
+func (r *Request) log(level ql.LogLevel, msg string, keyvals ...interface{}) {
+	if r.logger != nil {
+		r.logger.Log(r.opts.Context, level, msg, keyvals...)
+	}
+}
+
 func Run(schema Schema, request string, opts *RequestOpts, v interface{}) error {
 	req, err := NewRequest(schema, request, *opts)
 	if err != nil {
@@ -0,0 +1,702 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+func mustBuildSchema(T *testing.T, opts SchemaOpts, sources ...string) Schema {
+	schema, err := BuildSchemaWithOpts(opts, SDL{Sources: sources})
+	if err != nil {
+		T.Fatal(err)
+	}
+	return schema
+}
+
+func expectRequestError(T *testing.T, errs Errors, message string) {
+	if errs == nil {
+		T.Errorf("Expect error %q but the request is valid", message)
+		return
+	}
+	for _, err := range errs.AllErrors() {
+		if strings.Contains(err.Error(), message) {
+			return
+		}
+	}
+	T.Errorf("Expect error %q but got: %v", message, errs)
+}
+
+func TestNewRequest_MaxCostFromSDL(T *testing.T) {
+	schema := mustBuildSchema(T, SchemaOpts{Limits: Limits{MaxCost: 20}}, `
+		type Query {
+			users(first: Int): [User] @cost(value: 2)
+			version: String @cost(value: 0)
+		}
+		type User {
+			name: String
+			avatar: String @cost(value: 5)
+		}
+	`)
+
+	req, errs := NewRequest(schema, `{ version users(first: 3) { name avatar } }`)
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	result := req.Execute()
+	cost := result.Extensions["cost"]
+	expected := map[string]interface{}{"requested": 2 + 3*(1+5), "maximum": 20}
+	if !reflect.DeepEqual(cost, expected) {
+		T.Errorf("Expect cost extension %v but got %v", expected, cost)
+	}
+
+	_, errs = NewRequest(schema, `{ users(first: 4) { name avatar } }`)
+	expectRequestError(T, errs, `Query cost 26 exceeds the maximum cost of 20.`)
+}
+
+func TestNewRequest_MaxCostFromDirectives(T *testing.T) {
+	cost := func(value int) []ql.AppliedDirective {
+		return []ql.AppliedDirective{{Name: "cost", Args: map[string]interface{}{"value": value}}}
+	}
+	schema, err := NewSchemaWithOpts(SchemaOpts{Limits: Limits{MaxCost: 10}}, ql.Object{
+		Name: "Query",
+		Fields: ql.FieldMap{
+			"expensive": {Type: ql.String, Directives: cost(6)},
+			"version":   {Type: ql.String, Directives: cost(0)},
+		},
+	})
+	if err != nil {
+		T.Fatal(err)
+	}
+
+	req, errs := NewRequest(schema, `{ expensive version }`)
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	result := req.Execute()
+	expected := map[string]interface{}{"requested": 6, "maximum": 10}
+	if actual := result.Extensions["cost"]; !reflect.DeepEqual(actual, expected) {
+		T.Errorf("Expect cost extension %v but got %v", expected, actual)
+	}
+
+	_, errs = NewRequest(schema, `{ expensive other: expensive }`)
+	expectRequestError(T, errs, `Query cost 12 exceeds the maximum cost of 10.`)
+}
+
+func TestNewRequest_SpecValidation(T *testing.T) {
+	schema := mustBuildSchema(T, SchemaOpts{}, `
+		type Query {
+			user(id: ID): User
+		}
+		type User {
+			name: String
+		}
+	`)
+	if _, errs := NewRequest(schema, `query Q($id: ID) { user(id: $id) { name } }`); errs != nil {
+		T.Errorf("Expect the request to be valid but got: %v", errs)
+	}
+
+	tests := []struct {
+		request string
+		message string
+	}{
+		{`{ nope }`, `Cannot query field "nope" on "Query".`},
+		{`{ user(name: "a") { name } }`, `Unknown argument "name" on field "user" of type "Query".`},
+		{`{ user(id: $id) { name } }`, `Variable "$id" is not defined.`},
+		{`{ user }`, `Field "user" of type "User" must have a sub selection.`},
+	}
+	for _, test := range tests {
+		_, errs := NewRequest(schema, test.request)
+		expectRequestError(T, errs, test.message)
+	}
+}
+
+func TestLimits_Merge(T *testing.T) {
+	schemaLimits := Limits{MaxDepth: 5, MaxFields: 100, IgnoreIntrospectionDepth: true}
+	tests := []struct {
+		request  Limits
+		expected Limits
+	}{
+		// A zero value keeps the limit of the schema, so a request cannot
+		// remove one nor stop ignoring the depth of introspection
+		{Limits{}, schemaLimits},
+
+		// but a request can raise or lower a limit, or add one
+		{
+			Limits{MaxDepth: 10, MaxFields: 20, MaxAliases: 3},
+			Limits{MaxDepth: 10, MaxFields: 20, MaxAliases: 3, IgnoreIntrospectionDepth: true},
+		},
+	}
+	for _, test := range tests {
+		if actual := schemaLimits.merge(test.request); !reflect.DeepEqual(actual, test.expected) {
+			T.Errorf("Expect %+v but got %+v", test.expected, actual)
+		}
+	}
+}
+
+func TestNewRequest_DisableIntrospection(T *testing.T) {
+	schema := mustBuildSchema(T, SchemaOpts{DisableIntrospection: true},
+		`type Query { version: String }`)
+
+	_, errs := NewRequest(schema, `{ __schema { queryType { name } } }`)
+	expectRequestError(T, errs, `GraphQL introspection is not allowed, but the query contained the field "__schema".`)
+	_, errs = NewRequest(schema, `{ __type(name: "Query") { name } }`)
+	expectRequestError(T, errs, `GraphQL introspection is not allowed, but the query contained the field "__type".`)
+
+	if _, errs = NewRequest(schema, `{ __typename version }`); errs != nil {
+		T.Errorf("Expect __typename to be allowed but got: %v", errs)
+	}
+}
+
+type _testContextKey struct{}
+
+func TestNewRequest_AllowIntrospection(T *testing.T) {
+	schema := mustBuildSchema(T, SchemaOpts{
+		AllowIntrospection: func(ctx context.Context) bool {
+			return ctx.Value(_testContextKey{}) == "admin"
+		},
+	}, `type Query { version: String }`)
+	request := `{ __schema { queryType { name } } }`
+
+	_, errs := NewRequest(schema, request)
+	expectRequestError(T, errs, `GraphQL introspection is not allowed, but the query contained the field "__schema".`)
+
+	ctx := context.WithValue(context.Background(), _testContextKey{}, "admin")
+	if _, errs = NewRequest(schema, request, RequestOpts{Context: ctx}); errs != nil {
+		T.Errorf("Expect introspection to be allowed but got: %v", errs)
+	}
+}
+
+var audienceRoleConfig = ql.Enum{
+	Name: "Role",
+	Values: ql.EnumValueMap{
+		"USER":  {Value: "user"},
+		"ADMIN": {Value: "admin", Visibility: []string{"admin"}},
+	},
+}
+
+var audienceQueryConfig = ql.Object{
+	Name: "Query",
+	Fields: ql.FieldMap{
+		"version": {Type: ql.String},
+		"secret":  {Type: ql.String, Visibility: []string{"admin"}},
+		"internal": {Type: ql.Object{
+			Name:       "Internal",
+			Fields:     ql.FieldMap{"stats": {Type: ql.String}},
+			Visibility: []string{"admin", "ops"},
+		}},
+		"search": {
+			Type: ql.String,
+			Args: ql.ArgumentMap{
+				"term":  {Type: ql.String},
+				"debug": {Type: ql.Boolean, Visibility: []string{"admin"}},
+			},
+			Resolve: func(args struct {
+				Term  string
+				Debug bool
+			}) string {
+				return fmt.Sprintf("%v %v", args.Term, args.Debug)
+			},
+		},
+		"role": {
+			Type: audienceRoleConfig,
+			Args: ql.ArgumentMap{"role": {Type: audienceRoleConfig}},
+			Resolve: func(args struct{ Role string }) string {
+				return args.Role
+			},
+		},
+	},
+}
+
+var audienceRootValue = map[string]interface{}{
+	"version":  "1",
+	"secret":   "s",
+	"internal": map[string]interface{}{"stats": "ok"},
+}
+
+func executeRequest(T *testing.T, schema Schema, request string) Result {
+	req, errs := NewRequest(schema, request, RequestOpts{RootValue: audienceRootValue})
+	if errs != nil {
+		T.Fatalf("Expect %v to be valid but got: %v", request, errs)
+	}
+	return req.Execute()
+}
+
+func expectResultData(T *testing.T, result Result, expected interface{}) {
+	if len(result.Errors) != 0 {
+		T.Errorf("Expect no errors but got: %v", result.Errors)
+		return
+	}
+	if !reflect.DeepEqual(result.Data, expected) {
+		T.Errorf("Expect data:\n%#v\nbut got:\n%#v", expected, result.Data)
+	}
+}
+
+func TestSchema_ForAudienceHidesFromExecution(T *testing.T) {
+	schema, err := NewSchema(audienceQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+	public, err := schema.ForAudience()
+	if err != nil {
+		T.Fatal(err)
+	}
+
+	hidden := []struct {
+		request string
+		message string
+	}{
+		{`{ secret }`, `Cannot query field "secret" on "Query".`},
+		{`{ internal { stats } }`, `Cannot query field "internal" on "Query".`},
+		{`{ search(term: "a", debug: true) }`, `Unknown argument "debug" on field "search" of type "Query".`},
+		{`{ role(role: ADMIN) }`, `Argument "role" expected type "Role" but got: ADMIN.`},
+		{`{ __type(name: "Internal") { fields { name } } ...F } fragment F on Internal { stats }`, `Unknown type "Internal".`},
+	}
+	for _, test := range hidden {
+		_, errs := NewRequest(public, test.request)
+		expectRequestError(T, errs, test.message)
+
+		// The full schema and the views of a listed audience still accept it
+		admin, _ := schema.ForAudience("admin")
+		if _, errs := NewRequest(admin, test.request); errs != nil {
+			T.Errorf("Expect %v to be valid for admin but got: %v", test.request, errs)
+		}
+		if _, errs := NewRequest(schema, test.request); errs != nil {
+			T.Errorf("Expect %v to be valid but got: %v", test.request, errs)
+		}
+	}
+
+	result := executeRequest(T, public, `{ version search(term: "a") role(role: USER) }`)
+	expectResultData(T, result, map[string]interface{}{
+		"version": "1",
+		"search":  "a false",
+		"role":    "USER",
+	})
+
+	ops, _ := schema.ForAudience("ops", "unknown")
+	result = executeRequest(T, ops, `{ internal { stats } }`)
+	expectResultData(T, result, map[string]interface{}{
+		"internal": map[string]interface{}{"stats": "ok"},
+	})
+	_, errs := NewRequest(ops, `{ secret }`)
+	expectRequestError(T, errs, `Cannot query field "secret" on "Query".`)
+}
+
+func TestSchema_ForAudienceHidesFromIntrospection(T *testing.T) {
+	schema, err := NewSchema(audienceQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+	public, err := schema.ForAudience()
+	if err != nil {
+		T.Fatal(err)
+	}
+	request := `{
+		query: __type(name: "Query") { fields { name args { name } } }
+		internal: __type(name: "Internal") { name }
+		role: __type(name: "Role") { enumValues { name } }
+		__schema { types { name } }
+	}`
+
+	result := executeRequest(T, public, request)
+	data, _ := result.Data.(map[string]interface{})
+	if len(result.Errors) != 0 || data == nil {
+		T.Fatalf("Expect introspection to succeed but got: %v", result.Errors)
+	}
+	fields := map[string][]string{}
+	for _, field := range data["query"].(map[string]interface{})["fields"].([]interface{}) {
+		field := field.(map[string]interface{})
+		var args []string
+		for _, arg := range field["args"].([]interface{}) {
+			args = append(args, arg.(map[string]interface{})["name"].(string))
+		}
+		fields[field["name"].(string)] = args
+	}
+	expectedFields := map[string][]string{
+		"version": nil,
+		"search":  {"term"},
+		"role":    {"role"},
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		T.Errorf("Expect fields %v but got %v", expectedFields, fields)
+	}
+	if data["internal"] != nil {
+		T.Errorf("Expect type Internal to be hidden but got %v", data["internal"])
+	}
+	expectedValues := []interface{}{map[string]interface{}{"name": "USER"}}
+	if values := data["role"].(map[string]interface{})["enumValues"]; !reflect.DeepEqual(values, expectedValues) {
+		T.Errorf("Expect enum values %v but got %v", expectedValues, values)
+	}
+	for _, typ := range data["__schema"].(map[string]interface{})["types"].([]interface{}) {
+		if name := typ.(map[string]interface{})["name"]; name == "Internal" {
+			T.Errorf("Expect type Internal not to be listed")
+		}
+	}
+
+	admin, _ := schema.ForAudience("admin")
+	result = executeRequest(T, admin, request)
+	data, _ = result.Data.(map[string]interface{})
+	if len(result.Errors) != 0 || data == nil {
+		T.Fatalf("Expect introspection to succeed but got: %v", result.Errors)
+	}
+	if fields := data["query"].(map[string]interface{})["fields"].([]interface{}); len(fields) != 5 {
+		T.Errorf("Expect the 5 fields to be visible to admin but got %v", fields)
+	}
+	if data["internal"] == nil {
+		T.Errorf("Expect type Internal to be visible to admin")
+	}
+	if values := data["role"].(map[string]interface{})["enumValues"].([]interface{}); len(values) != 2 {
+		T.Errorf("Expect the 2 enum values to be visible to admin but got %v", values)
+	}
+}
+
+var middlewareUserConfig ql.Object
+
+var middlewareQueryConfig ql.Object
+
+func init() {
+	middlewareUserConfig = ql.Object{
+		Name: "User",
+		FieldsFunc: func() ql.FieldMap {
+			return ql.FieldMap{
+				"id":      {Type: ql.NonNull{OfType: ql.ID}},
+				"name":    {Type: ql.String},
+				"friends": {Type: ql.List{OfType: middlewareUserConfig}},
+			}
+		},
+	}
+	middlewareQueryConfig = ql.Object{
+		Name: "Query",
+		Fields: ql.FieldMap{
+			"user": {
+				Type: middlewareUserConfig,
+				Args: ql.ArgumentMap{
+					"id":    {Type: ql.ID},
+					"limit": {Type: ql.Int, DefaultValue: 10},
+				},
+			},
+			"failing": {
+				Type: ql.String,
+				Resolve: func(args struct{}) string {
+					panic("resolver failed")
+				},
+			},
+		},
+	}
+}
+
+var middlewareRootValue = map[string]interface{}{
+	"user": map[string]interface{}{
+		"name": "alice",
+		"friends": []interface{}{
+			map[string]interface{}{"name": "bob"},
+		},
+	},
+	"failing": "unused",
+}
+
+/**
+ * Records the calls to the middlewares, and to the directive resolve
+ * functions, in order.
+ */
+type _CallRecorder struct {
+	m     sync.Mutex
+	calls []string
+}
+
+func (r *_CallRecorder) record(call string) {
+	r.m.Lock()
+	r.calls = append(r.calls, call)
+	r.m.Unlock()
+}
+
+func (r *_CallRecorder) middleware(name string) ql.Middleware {
+	return func(ctx context.Context, info ql.ResolveInfo, next func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+		r.record(name + " " + info.FieldName)
+		return next(ctx)
+	}
+}
+
+func TestMiddlewares_Order(T *testing.T) {
+	recorder := &_CallRecorder{}
+	schema, err := NewSchemaWithOpts(SchemaOpts{
+		Middlewares: []ql.Middleware{recorder.middleware("schema1"), recorder.middleware("schema2")},
+		Directives: []ql.Directive{{
+			Name:      "record",
+			Locations: []ql.DirectiveLocation{ql.LocationField},
+			Resolve: func(ctx context.Context, args map[string]interface{}, next func() (interface{}, error)) (interface{}, error) {
+				recorder.record("directive")
+				return next()
+			},
+		}},
+	}, middlewareQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+	req, errs := NewRequest(schema, `{ user { name @record } }`, RequestOpts{
+		RootValue:   middlewareRootValue,
+		Middlewares: []ql.Middleware{recorder.middleware("request")},
+	})
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	expectResultData(T, req.Execute(), map[string]interface{}{
+		"user": map[string]interface{}{"name": "alice"},
+	})
+
+	// The schema middlewares wrap the request ones, which wrap the directives
+	expected := []string{
+		"schema1 user", "schema2 user", "request user",
+		"schema1 name", "schema2 name", "request name", "directive",
+	}
+	if !reflect.DeepEqual(recorder.calls, expected) {
+		T.Errorf("Expect calls %v but got %v", expected, recorder.calls)
+	}
+}
+
+func TestMiddlewares_ResolveInfo(T *testing.T) {
+	var m sync.Mutex
+	infos := make(map[string]ql.ResolveInfo)
+	schema, err := NewSchemaWithOpts(SchemaOpts{
+		Middlewares: []ql.Middleware{
+			func(ctx context.Context, info ql.ResolveInfo, next func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+				m.Lock()
+				infos[fmt.Sprint(info.Path)] = info
+				m.Unlock()
+				return next(ctx)
+			},
+		},
+	}, middlewareQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+	req, errs := NewRequest(schema, `{ user(id: "1") { friends { first: name } } }`,
+		RequestOpts{RootValue: middlewareRootValue})
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	expectResultData(T, req.Execute(), map[string]interface{}{
+		"user": map[string]interface{}{
+			"friends": []interface{}{map[string]interface{}{"first": "bob"}},
+		},
+	})
+
+	user := middlewareRootValue["user"].(map[string]interface{})
+	expected := map[string]ql.ResolveInfo{
+		"[user]": {
+			FieldName:  "user",
+			Path:       []interface{}{"user"},
+			Args:       map[string]interface{}{"id": "1", "limit": 10},
+			ParentType: "Query",
+			ReturnType: "User",
+			Source:     middlewareRootValue,
+		},
+		"[user friends]": {
+			FieldName:  "friends",
+			Path:       []interface{}{"user", "friends"},
+			ParentType: "User",
+			ReturnType: "[User]",
+			Source:     user,
+		},
+		"[user friends 0 first]": {
+			FieldName:  "name",
+			Path:       []interface{}{"user", "friends", 0, "first"},
+			ParentType: "User",
+			ReturnType: "String",
+			Source:     user["friends"].([]interface{})[0],
+		},
+	}
+	if len(infos) != len(expected) {
+		T.Errorf("Expect middleware to be called for %v fields but got %v", len(expected), len(infos))
+	}
+	for path, expectedInfo := range expected {
+		info := infos[path]
+		if len(info.Args) == 0 && len(expectedInfo.Args) == 0 {
+			info.Args = expectedInfo.Args
+		}
+		if !reflect.DeepEqual(info, expectedInfo) {
+			T.Errorf("Expect info for %v:\n%#v\nbut got:\n%#v", path, expectedInfo, info)
+		}
+	}
+}
+
+type _middlewareContextKey struct{}
+
+func TestMiddlewares_WrapResolution(T *testing.T) {
+	schema, err := NewSchemaWithOpts(SchemaOpts{
+		Directives: []ql.Directive{{
+			Name:      "fromContext",
+			Locations: []ql.DirectiveLocation{ql.LocationField},
+			Resolve: func(ctx context.Context, args map[string]interface{}, next func() (interface{}, error)) (interface{}, error) {
+				return ctx.Value(_middlewareContextKey{}), nil
+			},
+		}},
+	}, middlewareQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+	middleware := func(ctx context.Context, info ql.ResolveInfo, next func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+		switch info.FieldName {
+		case "user":
+			// The value of the field can be replaced without calling next
+			return map[string]interface{}{"name": "replaced"}, nil
+		case "failing":
+			value, err := next(ctx)
+			if err == nil || err.Error() != "resolver failed" {
+				T.Errorf("Expect the panic of the resolver as error but got %v", err)
+			}
+			return value, errors.New("middleware failed")
+		}
+		// The context given to next reaches the directives
+		return next(context.WithValue(ctx, _middlewareContextKey{}, "from middleware"))
+	}
+	req, errs := NewRequest(schema, `{ user { name nickname: name @fromContext } failing }`, RequestOpts{
+		RootValue:   middlewareRootValue,
+		Middlewares: []ql.Middleware{middleware},
+	})
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	result := req.Execute()
+	expected := map[string]interface{}{
+		"user": map[string]interface{}{"name": "replaced", "nickname": "from middleware"},
+	}
+	if !reflect.DeepEqual(result.Data, expected) {
+		T.Errorf("Expect data:\n%#v\nbut got:\n%#v", expected, result.Data)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Error() != "middleware failed" {
+		T.Errorf("Expect the error of the middleware but got: %v", result.Errors)
+	}
+}
+
+func TestMiddlewares_ErrorOnNonNullField(T *testing.T) {
+	deny := func(ctx context.Context, info ql.ResolveInfo, next func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+		if info.FieldName == "id" {
+			return nil, errors.New("denied")
+		}
+		return next(ctx)
+	}
+	schema, err := NewSchemaWithOpts(SchemaOpts{
+		Middlewares: []ql.Middleware{deny},
+	}, middlewareQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+	req, errs := NewRequest(schema, `{ user { id name } }`, RequestOpts{RootValue: middlewareRootValue})
+	if errs != nil {
+		T.Fatal(errs)
+	}
+
+	// The error makes the nullable parent field null
+	result := req.Execute()
+	expected := map[string]interface{}{}
+	if !reflect.DeepEqual(result.Data, expected) {
+		T.Errorf("Expect data:\n%#v\nbut got:\n%#v", expected, result.Data)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Error() != "denied" {
+		T.Errorf("Expect the error of the middleware but got: %v", result.Errors)
+	}
+}
+
+type _LogEntry struct {
+	ctx     context.Context
+	level   ql.LogLevel
+	msg     string
+	keyvals []interface{}
+}
+
+type _LogRecorder struct {
+	m       sync.Mutex
+	entries []_LogEntry
+}
+
+func (r *_LogRecorder) Log(ctx context.Context, level ql.LogLevel, msg string, keyvals ...interface{}) {
+	r.m.Lock()
+	r.entries = append(r.entries, _LogEntry{ctx, level, msg, keyvals})
+	r.m.Unlock()
+}
+
+var loggerQueryConfig = ql.Object{
+	Name: "Query",
+	Fields: ql.FieldMap{
+		"tags":    {Type: ql.List{OfType: ql.String}},
+		"version": {Type: ql.String},
+	},
+}
+
+func TestLogger(T *testing.T) {
+	schemaLogger, requestLogger := &_LogRecorder{}, &_LogRecorder{}
+	schema, err := NewSchemaWithOpts(SchemaOpts{Logger: schemaLogger}, loggerQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+	rootValue := map[string]interface{}{
+		"tags":    "not a list",
+		"version": "1",
+	}
+
+	ctx := context.WithValue(context.Background(), _testContextKey{}, "request")
+	req, errs := NewRequest(schema, `{ tags }`, RequestOpts{RootValue: rootValue, Context: ctx})
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	if result := req.Execute(); len(result.Errors) != 1 {
+		T.Errorf("Expect 1 error but got %v", result.Errors)
+	}
+	if len(schemaLogger.entries) != 1 {
+		T.Fatalf("Expect 1 log but got %v", schemaLogger.entries)
+	}
+	entry := schemaLogger.entries[0]
+	if entry.ctx.Value(_testContextKey{}) != "request" {
+		T.Errorf("Expect the context of the request to be given to the logger")
+	}
+	if entry.level != ql.LogDebug || entry.msg != "Recovered error" ||
+		len(entry.keyvals) != 4 || entry.keyvals[0] != "path" || entry.keyvals[2] != "error" ||
+		!reflect.DeepEqual(entry.keyvals[1], []interface{}{"tags"}) {
+		T.Errorf("Expect the recovered error to be logged with its path but got %#v", entry)
+	}
+
+	// The logger of the request replaces the one of the schema
+	req, errs = NewRequest(schema, `{ version }`, RequestOpts{RootValue: rootValue, Logger: requestLogger})
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	var value struct {
+		Version int `graphql:"version"`
+	}
+	if err := req.Run(&value); err != nil {
+		T.Fatal(err)
+	}
+	if len(schemaLogger.entries) != 1 || len(requestLogger.entries) != 1 {
+		T.Fatalf("Expect only the request logger to be used but got %v and %v",
+			schemaLogger.entries, requestLogger.entries)
+	}
+	entry = requestLogger.entries[0]
+	if entry.level != ql.LogWarn || entry.msg != "Cannot assign value to field" ||
+		len(entry.keyvals) != 4 || entry.keyvals[0] != "field" || entry.keyvals[1] != "Version" {
+		T.Errorf("Expect the unassignable field to be logged but got %#v", entry)
+	}
+}
+
+func TestLogger_SilentByDefault(T *testing.T) {
+	schema, err := NewSchema(loggerQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+	req, errs := NewRequest(schema, `{ tags }`, RequestOpts{
+		RootValue: map[string]interface{}{"tags": "not a list"},
+	})
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	// The recovered errors are still reported without a logger
+	if result := req.Execute(); len(result.Errors) != 1 {
+		T.Errorf("Expect 1 error but got %v", result.Errors)
+	}
+}
@@ -0,0 +1,186 @@
+package execution
+
+import (
+	"reflect"
+	"strconv"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+)
+
+/**
+ * The names of the arguments giving the number of items returned by a field
+ * when CostOptions.ListSizeArguments is not set.
+ */
+var DefaultListSizeArguments = []string{"first", "last", "limit"}
+
+// The cost is saturated at this value instead of overflowing.
+const maxCost = int(^uint(0) >> 2)
+
+/**
+ * Configures the cost analysis. The zero value uses the defaults.
+ */
+type CostOptions struct {
+	// DefaultCost is the cost of the fields which do not define their own.
+	// Defaults to 1, and ql.FreeCost means 0.
+	DefaultCost int
+
+	// ListSizeArguments are the names of the arguments giving the number of
+	// items returned by a field, such as "first" or "limit". Defaults to
+	// DefaultListSizeArguments.
+	ListSizeArguments []string
+}
+
+type _CostAnalyzer struct {
+	variableValues    map[string]interface{}
+	defaultCost       int
+	listSizeArguments []string
+}
+
+/**
+ * Computes the cost of executing the plan with the given raw variable values,
+ * without executing it.
+ *
+ * The cost of a field is its own cost plus the cost of its sub selections
+ * multiplied by its list size, which is the value of the first list size
+ * argument given to the field, or 1. The cost of a selection set is the sum of
+ * the costs of its fields, or the highest of these sums when it may be
+ * executed against several concrete types. Fields which may be excluded by
+ * @skip or @include are always counted.
+ */
+func (p *Plan) Cost(variableValues map[string]interface{}, opts CostOptions) int {
+	c := &_CostAnalyzer{
+		variableValues:    variableValues,
+		defaultCost:       opts.DefaultCost,
+		listSizeArguments: opts.ListSizeArguments,
+	}
+	switch {
+	case c.defaultCost == 0:
+		c.defaultCost = 1
+	case c.defaultCost < 0:
+		c.defaultCost = 0
+	}
+	if c.listSizeArguments == nil {
+		c.listSizeArguments = DefaultListSizeArguments
+	}
+	return c.selectionCost(p.root)
+}
+
+func (c *_CostAnalyzer) selectionCost(plan *_SelectionPlan) int {
+	cost := 0
+	for _, fieldPlan := range plan.fields {
+		cost = addCost(cost, c.fieldCost(fieldPlan))
+	}
+	return cost
+}
+
+func (c *_CostAnalyzer) fieldCost(fieldPlan *_FieldPlan) int {
+	cost := fieldPlan.fieldDef.Cost
+	switch {
+	case cost == 0:
+		cost = c.defaultCost
+	case cost < 0:
+		cost = 0
+	}
+
+	subCost := 0
+	for _, subPlan := range fieldPlan.subPlans {
+		if typeCost := c.selectionCost(subPlan); typeCost > subCost {
+			subCost = typeCost
+		}
+	}
+	return addCost(cost, mulCost(c.listSize(fieldPlan), subCost))
+}
+
+func (c *_CostAnalyzer) listSize(fieldPlan *_FieldPlan) int {
+	argASTs := fieldPlan.fieldASTs[0].Arguments
+	for _, name := range c.listSizeArguments {
+		for _, argAST := range argASTs {
+			if argAST.Name.Value != name {
+				continue
+			}
+			if size, ok := c.intValue(argAST.Value); ok {
+				return size
+			}
+		}
+		for _, argDef := range fieldPlan.fieldDef.Args {
+			if argDef.Name != name || argDef.DefaultValue == nil {
+				continue
+			}
+			if size, ok := toInt(argDef.DefaultValue); ok {
+				return size
+			}
+		}
+	}
+	return 1
+}
+
+func (c *_CostAnalyzer) intValue(valueAST lang.IValue) (int, bool) {
+	switch valueAST := valueAST.(type) {
+	case *lang.IntValue:
+		size, err := strconv.Atoi(valueAST.Value)
+		if err != nil {
+			return maxCost, true
+		}
+		return clampSize(size), true
+	case *lang.Variable:
+		value, ok := c.variableValues[valueAST.Name.Value]
+		if !ok || value == nil {
+			return 0, false
+		}
+		return toInt(value)
+	}
+	return 0, false
+}
+
+func toInt(value interface{}) (int, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := v.Int()
+		if n > int64(maxCost) {
+			return maxCost, true
+		}
+		return clampSize(int(n)), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n := v.Uint()
+		if n > uint64(maxCost) {
+			return maxCost, true
+		}
+		return int(n), true
+	case reflect.Float32, reflect.Float64:
+		n := v.Float()
+		if n > float64(maxCost) {
+			return maxCost, true
+		}
+		return clampSize(int(n)), true
+	case reflect.String:
+		// json.Number and numbers sent as strings
+		n, err := strconv.Atoi(v.String())
+		if err != nil {
+			return 0, false
+		}
+		return clampSize(n), true
+	}
+	return 0, false
+}
+
+func clampSize(size int) int {
+	if size < 0 {
+		return 0
+	}
+	return size
+}
+
+func addCost(a, b int) int {
+	if a > maxCost-b {
+		return maxCost
+	}
+	return a + b
+}
+
+func mulCost(a, b int) int {
+	if a != 0 && b > maxCost/a {
+		return maxCost
+	}
+	return a * b
+}
@@ -0,0 +1,194 @@
+package execution
+
+import (
+	"fmt"
+	"testing"
+
+	typs "github.com/ng-vu/graphql-go/internal/types"
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+var costNodeConfig = ql.Interface{
+	Name: "Node",
+	Fields: ql.FieldMap{
+		"id": {Type: ql.ID},
+	},
+}
+
+var costUserConfig, costPostConfig ql.Object
+
+var costSchema typs.QLSchema
+
+func init() {
+	costUserConfig = ql.Object{
+		Name:       "User",
+		Interfaces: ql.Interfaces{costNodeConfig},
+		FieldsFunc: func() ql.FieldMap {
+			return ql.FieldMap{
+				"id":   {Type: ql.ID},
+				"name": {Type: ql.String},
+				"friends": {
+					Type: ql.List{OfType: costUserConfig},
+					Args: ql.ArgumentMap{"first": {Type: ql.Int}},
+				},
+				"avatar": {Type: ql.String, Cost: 4},
+			}
+		},
+		IsTypeOf: func(v interface{}, info interface{}) bool { return false },
+	}
+	costPostConfig = ql.Object{
+		Name:       "Post",
+		Interfaces: ql.Interfaces{costNodeConfig},
+		Fields: ql.FieldMap{
+			"id":    {Type: ql.ID},
+			"title": {Type: ql.String},
+		},
+		IsTypeOf: func(v interface{}, info interface{}) bool { return false },
+	}
+	costSchema = typs.NewQLSchema(ql.Object{
+		Name: "Query",
+		Fields: ql.FieldMap{
+			"users": {
+				Type: ql.List{OfType: costUserConfig},
+				Args: ql.ArgumentMap{
+					"first": {Type: ql.Int},
+					"limit": {Type: ql.Int, DefaultValue: 10},
+				},
+			},
+			"node":      {Type: costNodeConfig},
+			"post":      {Type: costPostConfig},
+			"expensive": {Type: ql.String, Cost: 5},
+			"free":      {Type: ql.String, Cost: ql.FreeCost},
+			"directive": {
+				Type:       ql.String,
+				Directives: []ql.AppliedDirective{{Name: "cost", Args: map[string]interface{}{"value": 3}}},
+			},
+			"freeDirective": {
+				Type:       ql.String,
+				Directives: []ql.AppliedDirective{{Name: "cost", Args: map[string]interface{}{"value": 0}}},
+			},
+		},
+	}, nil)
+}
+
+func TestPlan_Cost(T *testing.T) {
+	tests := []struct {
+		request  string
+		expected int
+	}{
+		{`{ expensive }`, 5},
+		{`{ free }`, 0},
+		{`{ expensive free post { title } }`, 5 + 0 + (1 + 1)},
+
+		// The cost may be given by the cost directive
+		{`{ directive freeDirective }`, 3 + 0},
+
+		// The list size is given by the first list size argument
+		{`{ users(first: 3) { name } }`, 1 + 3*1},
+		{`{ users(first: 3, limit: 100) { name avatar } }`, 1 + 3*(1+4)},
+		{`{ users(first: 0) { name } }`, 1},
+		{`{ users(first: -5) { name } }`, 1},
+
+		// or the default value of a list size argument
+		{`{ users { name } }`, 1 + 10*1},
+
+		// The list sizes of nested fields multiply
+		{`{ users(first: 2) { friends(first: 3) { name } } }`, 1 + 2*(1+3*1)},
+		{`{ users(first: 2) { friends { name } } }`, 1 + 2*(1+1)},
+
+		// Fragments are counted where they are spread
+		{`{ users(first: 2) { ...F } } fragment F on User { name friends(first: 3) { ...G } } fragment G on User { avatar }`,
+			1 + 2*(1+(1+3*4))},
+		{`{ users(first: 2) { ... on User { avatar } } }`, 1 + 2*4},
+
+		// Fields merged from several selections are counted once
+		{`{ users(first: 2) { name ...F } } fragment F on User { name }`, 1 + 2*1},
+
+		// The most expensive concrete type is counted for abstract types
+		{`{ node { id ... on User { avatar friends(first: 2) { name } } ... on Post { title } } }`,
+			1 + (1 + 4 + (1 + 2*1))},
+
+		// Fields which may be skipped are counted
+		{`{ expensive @skip(if: true) }`, 5},
+	}
+	for _, test := range tests {
+		plan := prepare(T, costSchema, test.request)
+		if cost := plan.Cost(nil, CostOptions{}); cost != test.expected {
+			T.Errorf("Expect cost of %v to be %v but got %v", test.request, test.expected, cost)
+		}
+	}
+}
+
+func TestPlan_CostWithVariables(T *testing.T) {
+	request := `query Q($n: Int) { users(first: $n) { name } }`
+	plan := prepare(T, costSchema, request)
+
+	// The variables are read from the raw values given to the request
+	tests := []struct {
+		variables map[string]interface{}
+		expected  int
+	}{
+		{map[string]interface{}{"n": 5}, 1 + 5},
+		{map[string]interface{}{"n": float64(7)}, 1 + 7},
+		{map[string]interface{}{"n": "4"}, 1 + 4},
+		{map[string]interface{}{}, 1 + 10},
+		{nil, 1 + 10},
+	}
+	for _, test := range tests {
+		if cost := plan.Cost(test.variables, CostOptions{}); cost != test.expected {
+			T.Errorf("Expect cost with %v to be %v but got %v", test.variables, test.expected, cost)
+		}
+	}
+}
+
+func TestPlan_CostWithOptions(T *testing.T) {
+	request := `{ expensive free users(count: 2) { name avatar } }`
+	plan := prepare(T, costSchema, request)
+
+	tests := []struct {
+		opts     CostOptions
+		expected int
+	}{
+		// The default value of limit is used by default
+		{CostOptions{}, 5 + 0 + (1 + 10*(1+4))},
+		{CostOptions{DefaultCost: 2}, 5 + 0 + (2 + 10*(2+4))},
+		{CostOptions{DefaultCost: ql.FreeCost}, 5 + 0 + (0 + 10*(0+4))},
+		{CostOptions{ListSizeArguments: []string{"count"}}, 5 + 0 + (1 + 2*(1+4))},
+	}
+	for _, test := range tests {
+		if cost := plan.Cost(nil, test.opts); cost != test.expected {
+			T.Errorf("Expect cost with %+v to be %v but got %v", test.opts, test.expected, cost)
+		}
+	}
+}
+
+func TestPlan_CostSaturates(T *testing.T) {
+	request := `{ users(first: 999999999999999999999) { friends(first: 1000000000) { friends(first: 1000000000) { name } } } }`
+	plan := prepare(T, costSchema, request)
+	if cost := plan.Cost(nil, CostOptions{}); cost != maxCost {
+		T.Errorf("Expect cost to saturate at %v but got %v", maxCost, cost)
+	}
+}
+
+func TestPlan_CostDirectiveMustBeValid(T *testing.T) {
+	tests := []interface{}{-1, "1", nil}
+	for _, value := range tests {
+		func() {
+			defer func() {
+				expected := `The cost of Query.a must be given as @cost(value:) with a non-negative Int.`
+				if err := recover(); err == nil || fmt.Sprint(err) != expected {
+					T.Errorf("Expect error %q for value %#v but got: %v", expected, value, err)
+				}
+			}()
+			typs.NewQLSchema(ql.Object{
+				Name: "Query",
+				Fields: ql.FieldMap{
+					"a": {
+						Type:       ql.String,
+						Directives: []ql.AppliedDirective{{Name: "cost", Args: map[string]interface{}{"value": value}}},
+					},
+				},
+			}, nil)
+		}()
+	}
+}
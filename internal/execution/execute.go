@@ -1,18 +1,18 @@
 package execution
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 
-	debug "github.com/ng-vu/graphql-go/internal/debug"
 	lang "github.com/ng-vu/graphql-go/internal/language"
 	typs "github.com/ng-vu/graphql-go/internal/types"
+	"github.com/ng-vu/graphql-go/ql"
 )
 
-var LOG = debug.New("graphql/execution")
-
 type _Context struct {
 	Schema         typs.QLSchema
 	Fragments      map[string]*lang.FragmentDefinition
@@ -20,224 +20,218 @@ type _Context struct {
 	Operation      *lang.OperationDefinition
 	VariableValues map[string]interface{}
 	Errors         []error
+
+	ctx         context.Context
+	plan        *Plan
+	middlewares []ql.Middleware
+	logger      ql.Logger
+	m           sync.Mutex
 }
 
 type Result struct {
-	Data   interface{}
-	Errors []error
+	Data       interface{}
+	Errors     []error
+	Extensions map[string]interface{}
 }
 
 type Options struct {
 	RootValue      interface{}
 	VariableValues map[string]interface{}
 	OperationName  string
+
+	// Context is given to the middlewares and the directive resolve
+	// functions. Defaults to context.Background().
+	Context context.Context
+
+	// Middlewares wrap the resolution of every field, the first one being the
+	// outermost.
+	Middlewares []ql.Middleware
+
+	// Logger receives the errors recovered while executing. Nothing is logged
+	// when it is nil.
+	Logger ql.Logger
 }
 
 func Execute(schema typs.QLSchema, documentAST *lang.Document, opts Options) Result {
-	context := newContext(schema, documentAST,
-		opts.RootValue, opts.VariableValues, opts.OperationName)
-	return context.executeOperation()
+	plan, err := Prepare(schema, documentAST, opts.OperationName)
+	if err != nil {
+		return Result{Errors: []error{err}}
+	}
+	return ExecutePlan(plan, opts)
+}
+
+/**
+ * Executes a prepared plan. The OperationName option is ignored, as the
+ * operation has already been selected when preparing the plan.
+ */
+func ExecutePlan(plan *Plan, opts Options) (result Result) {
+	defer func() {
+		if e := recover(); e != nil {
+			if e, ok := e.(error); ok {
+				result = Result{Errors: []error{e}}
+				return
+			}
+			panic(e)
+		}
+	}()
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c := newContext(ctx, plan, opts.RootValue, opts.VariableValues)
+	c.middlewares = opts.Middlewares
+	c.logger = opts.Logger
+	return c.executeOperation()
 }
 
 func newContext(
-	schema typs.QLSchema,
-	documentAST *lang.Document,
+	ctx context.Context,
+	plan *Plan,
 	rootValue interface{},
 	rawVariableValues map[string]interface{},
-	operationName string,
 ) *_Context {
 
-	operations := make(map[string]*lang.OperationDefinition)
-	fragments := make(map[string]*lang.FragmentDefinition)
-
-	for _, statement := range documentAST.Definitions {
-		switch statement := statement.(type) {
-		case *lang.OperationDefinition:
-			name := ""
-			if statement.Name != nil {
-				name = statement.Name.Value
-			}
-			operations[name] = statement
-		case *lang.FragmentDefinition:
-			fragments[statement.Name.Value] = statement
-		default:
-			panic(lang.NewQLError(
-				fmt.Sprintf(`Cannot execute a request containing a %v.`, statement.Kind()),
-				[]lang.INode{statement}))
-		}
-	}
-	if operationName != "" && len(operations) != 1 {
-		panic(lang.NewQLError(`Must provide operation name if query contains multiple operations.`, nil))
-	}
-	if operationName == "" {
-		for name := range operations {
-			operationName = name
-		}
-	}
-	operation, ok := operations[operationName]
-	if !ok {
-		panic(lang.NewQLError(
-			fmt.Sprintf(`Unknown operation named "%v".`, operationName), nil))
-	}
-	variableValues := GetVariableValues(schema, operation.VariableDefinitions, rawVariableValues)
+	operation := plan.Operation
+	variableValues := GetVariableValues(plan.Schema, operation.VariableDefinitions, rawVariableValues)
 	return &_Context{
-		Schema:         schema,
-		Fragments:      fragments,
+		Schema:         plan.Schema,
+		Fragments:      plan.Fragments,
 		RootValue:      rootValue,
 		Operation:      operation,
 		VariableValues: variableValues,
 		Errors:         nil,
+		ctx:            ctx,
+		plan:           plan,
 	}
 }
 
 func (c *_Context) executeOperation() Result {
-	operation := c.Operation
-	typ := getOperationRootType(c.Schema, operation)
-	fields := c.collectFields(typ, operation.SelectionSet,
-		map[string][]*lang.Field{}, map[string]struct{}{})
-
-	if operation.Operation == lang.OperationMutation {
-		return c.executeFieldsSerially(typ, c.RootValue, fields)
+	var data map[string]interface{}
+	if c.Operation.Operation == lang.OperationMutation {
+		data = c.executeFieldsSerially(c.plan.root, c.RootValue, nil)
+	} else {
+		data = c.executeFields(c.plan.root, c.RootValue, nil)
 	}
-	return c.executeFields(typ, c.RootValue, fields)
+	return Result{Data: data, Errors: c.Errors}
 }
 
 func (c *_Context) executeFieldsSerially(
-	parentType *typs.QLObject,
+	plan *_SelectionPlan,
 	sourceValue interface{},
-	fields map[string][]*lang.Field) Result {
+	path *_Path,
+) map[string]interface{} {
 
 	results := make(map[string]interface{})
-	for responseName, fieldASTs := range fields {
-		result := c.resolveField(parentType, sourceValue, fieldASTs)
+	for _, fieldPlan := range plan.fields {
+		if !c.shouldIncludeField(fieldPlan) {
+			continue
+		}
+		result := c.resolveField(plan.parentType, sourceValue, fieldPlan,
+			path.with(fieldPlan.responseName))
 		if result != nil {
-			results[responseName] = result
+			results[fieldPlan.responseName] = result
 		}
 	}
-	return Result{results, nil}
+	return results
 }
 
 func (c *_Context) executeFields(
-	parentType *typs.QLObject,
+	plan *_SelectionPlan,
 	sourceValue interface{},
-	fields map[string][]*lang.Field) Result {
+	path *_Path,
+) map[string]interface{} {
 
 	var m sync.Mutex
 	var wg sync.WaitGroup
 	results := make(map[string]interface{})
-	for responseName, fieldASTs := range fields {
+
+	// The error of a non-null field which could not be resolved is recovered
+	// in its goroutine, then raised again here once every field is resolved,
+	// so that the parent field resolves to null. The first one in the order of
+	// the fields is raised.
+	errs := make([]interface{}, len(plan.fields))
+	for i, fieldPlan := range plan.fields {
+		if !c.shouldIncludeField(fieldPlan) {
+			continue
+		}
 		wg.Add(1)
-		go func() {
+		go func(i int, fieldPlan *_FieldPlan) {
 			defer wg.Done()
-			result := c.resolveField(parentType, sourceValue, fieldASTs)
+			defer func() {
+				errs[i] = recover()
+			}()
+			result := c.resolveField(plan.parentType, sourceValue, fieldPlan,
+				path.with(fieldPlan.responseName))
 			if result != nil {
 				m.Lock()
-				results[responseName] = result
+				results[fieldPlan.responseName] = result
 				m.Unlock()
 			}
-		}()
+		}(i, fieldPlan)
 	}
 	wg.Wait()
-	return Result{results, nil}
+	for _, err := range errs {
+		if err != nil {
+			panic(err)
+		}
+	}
+	return results
 }
 
-func (c *_Context) collectFields(
-	runtimeType *typs.QLObject,
-	selectionSet *lang.SelectionSet,
-	fields map[string][]*lang.Field,
-	visitedFragmentNames map[string]struct{},
-) map[string][]*lang.Field {
-
-	for _, selection := range selectionSet.Selections {
-		switch selection := selection.(type) {
-		case *lang.Field:
-			if !c.shouldIncludeNode(selection.Directives) {
-				continue
-			}
-			name := getFieldEntryKey(selection)
-			fields[name] = append(fields[name], selection)
+func (c *_Context) log(level ql.LogLevel, msg string, keyvals ...interface{}) {
+	if c.logger != nil {
+		c.logger.Log(c.ctx, level, msg, keyvals...)
+	}
+}
 
-		case *lang.InlineFragment:
-			if !c.shouldIncludeNode(selection.Directives) ||
-				!c.doesFragmentConditionMatch(selection, runtimeType) {
-				continue
-			}
-			c.collectFields(runtimeType, selection.SelectionSet, fields, visitedFragmentNames)
+func (c *_Context) addError(err error) {
+	c.m.Lock()
+	c.Errors = append(c.Errors, err)
+	c.m.Unlock()
+}
 
-		case *lang.FragmentSpread:
-			fragName := selection.Name.Value
-			_, visited := visitedFragmentNames[fragName]
-			if visited || !c.shouldIncludeNode(selection.Directives) {
-				continue
-			}
-			visitedFragmentNames[fragName] = struct{}{}
-			fragment, ok := c.Fragments[fragName]
-			if !ok || c.shouldIncludeNode(fragment.Directives) ||
-				c.doesFragmentConditionMatch(fragment, runtimeType) {
-				continue
-			}
-			c.collectFields(runtimeType, fragment.SelectionSet, fields, visitedFragmentNames)
+/**
+ * A field is included when any of the paths it was collected through has all
+ * of its @skip and @include directives passing.
+ */
+func (c *_Context) shouldIncludeField(fieldPlan *_FieldPlan) bool {
+	for _, directives := range fieldPlan.conditions {
+		if c.shouldIncludeNode(directives) {
+			return true
 		}
 	}
-	return fields
+	return false
 }
 
 func (c *_Context) shouldIncludeNode(directives []*lang.Directive) bool {
+	for _, directive := range directives {
+		switch directive.Name.Value {
+		case typs.QLSkipDirective.Name:
+			argValues := GetArgumentValues(typs.QLSkipDirective.Args, directive.Arguments, c.VariableValues)
+			if skipIf, _ := argValues["if"].(bool); skipIf {
+				return false
+			}
+		case typs.QLIncludeDirective.Name:
+			argValues := GetArgumentValues(typs.QLIncludeDirective.Args, directive.Arguments, c.VariableValues)
+			if includeIf, _ := argValues["if"].(bool); !includeIf {
+				return false
+			}
+		}
+	}
 	return true
-	// skipName := typs.QLSkipDirective.Name
-	// var skipAST *lang.Directive
-	// for _, directive := range directives {
-	// 	if directive.Name.Value == skipName {
-	// 		skipAST = directive
-	// 		break
-	// 	}
-	// }
-	// if skipAST != nil {
-	// 	argValues := GetArgumentValues(typs.QLSkipDirective.Args, skipAST.Arguments, c.VariableValues)
-	// 	skipIf := argValues["if"]
-	// 	return skipIf != nil // TODO(qv): Check trust value
-	// }
-
-	// var includeAST *lang.Directive
-	// for _, directive := range directives {
-	// 	if directive.Name.Value == typs.QLIncludeDirective.Name {
-	// 		includeAST = directive
-	// 		break
-	// 	}
-	// }
-	// if includeAST != nil {
-	// 	argValues := GetArgumentValues(typs.QLIncludeDirective.Args, includeAST.Arguments, c.VariableValues)
-	// 	includeIf := argValues["if"]
-	// 	return includeIf != nil // TODO(qv): Check trust value
-	// }
-	// return false
-}
-
-func (c *_Context) doesFragmentConditionMatch(fragment lang.ITypeCondition, typ *typs.QLObject) bool {
-	return true
-	// 	conditionalType := util.TypeFromAST(c.Schema, fragment.GetTypeCondition())
-	// 	if conditionalType == typ {
-	// 		return true
-	// 	}
-	// 	// TODO(qv): Check isAbstractType
-	// 	if conditionalType, ok := conditionalType.(typs.QLAbstractType); ok {
-	// 		return conditionalType.IsPossibleType(typ)
-	// 	}
-	// 	return false
 }
 
 func (c *_Context) resolveField(
 	parentType *typs.QLObject,
 	source interface{},
-	fieldASTs []*lang.Field,
+	fieldPlan *_FieldPlan,
+	path *_Path,
 ) interface{} {
 
-	fieldAST := fieldASTs[0]
-	fieldName := fieldAST.Name.Value
-	fieldDef := getFieldDef(c.Schema, parentType, fieldName)
-	if fieldDef == nil {
-		return nil
-	}
+	fieldASTs := fieldPlan.fieldASTs
+	fieldName := fieldPlan.fieldName
+	fieldDef := fieldPlan.fieldDef
 
 	returnType := fieldDef.Type
 	resolveFn := fieldDef.Resolve
@@ -245,7 +239,12 @@ func (c *_Context) resolveField(
 		resolveFn = defaultResolveFn
 	}
 
-	args := GetArgumentValues(fieldDef.Args, fieldAST.Arguments, c.VariableValues)
+	var args map[string]interface{}
+	if fieldPlan.constArgs {
+		args = fieldPlan.copyArgs()
+	} else {
+		args = GetArgumentValues(fieldDef.Args, fieldASTs[0].Arguments, c.VariableValues)
+	}
 	info := typs.QLResolveInfo{
 		FieldName:      fieldName,
 		FieldASTs:      fieldASTs,
@@ -278,28 +277,149 @@ func (c *_Context) resolveField(
 		if _, ok := returnType.(*typs.QLNonNull); ok {
 			panic(reportedError)
 		}
-		c.Errors = append(c.Errors, reportedError)
+		c.addError(reportedError)
 	}()
 
-	result := resolveFn(source, args, info)
-	fields := make([]*lang.Field, len(fieldASTs))
-	for i, ast := range fieldASTs {
-		fields[i] = ast
+	resolve := func(ctx context.Context) (interface{}, error) {
+		return c.resolveWithDirectives(ctx, fieldPlan, resolveFn, source, args, info)
+	}
+	if len(c.middlewares) > 0 {
+		resolve = c.wrapMiddlewares(resolve, ql.ResolveInfo{
+			FieldName:  fieldName,
+			Path:       path.toSlice(),
+			Args:       args,
+			ParentType: parentType.Name,
+			ReturnType: fmt.Sprint(returnType),
+			Source:     source,
+		})
+	}
+	result, err := resolve(c.ctx)
+	if err != nil {
+		panic(err)
+	}
+	return c.completeValueCatchingError(returnType, fieldPlan, info, path, result)
+}
+
+/**
+ * Wraps resolve with the middlewares, the first one being the outermost.
+ */
+func (c *_Context) wrapMiddlewares(
+	resolve func(ctx context.Context) (interface{}, error),
+	info ql.ResolveInfo,
+) func(ctx context.Context) (interface{}, error) {
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		middleware, next := c.middlewares[i], resolve
+		resolve = func(ctx context.Context) (interface{}, error) {
+			return middleware(ctx, info, next)
+		}
+	}
+	return resolve
+}
+
+/**
+ * Calls the resolve function wrapped by the directives which have their own
+ * resolve function: first the ones applied to the field definition, then the
+ * ones applied to the field in the query, each one wrapping the previous ones.
+ */
+func (c *_Context) resolveWithDirectives(
+	ctx context.Context,
+	fieldPlan *_FieldPlan,
+	resolveFn typs.QLFieldResolveFunc,
+	source interface{},
+	args map[string]interface{},
+	info typs.QLResolveInfo,
+) (interface{}, error) {
+
+	next := func() (result interface{}, err error) {
+		// A panic of the resolver is given to the directives and the
+		// middlewares as an error.
+		defer func() {
+			if e := recover(); e != nil {
+				if e, ok := e.(error); ok {
+					err = e
+					return
+				}
+				err = errors.New(fmt.Sprint(e))
+			}
+		}()
+		return resolveFn(source, args, info), nil
+	}
+	for _, applied := range fieldPlan.fieldDef.Directives {
+		if applied.Directive.Resolve != nil {
+			next = wrapResolve(ctx, applied.Directive.Resolve, applied.Args, next)
+		}
+	}
+	applied, err := c.queryDirectives(fieldPlan)
+	if err != nil {
+		return nil, err
+	}
+	for _, applied := range applied {
+		next = wrapResolve(ctx, applied.Directive.Resolve, applied.Args, next)
+	}
+	return next()
+}
+
+/**
+ * Returns the directives with a resolve function applied to the field in the
+ * query, in the order they first appear. The field may be selected several
+ * times, in which case the directives of every selection which is included
+ * are collected, and each directive is applied once. A directive applied with
+ * different arguments to the selections of the same field is an error, as the
+ * field only has one value.
+ */
+func (c *_Context) queryDirectives(fieldPlan *_FieldPlan) ([]*typs.QLAppliedDirective, error) {
+	var result []*typs.QLAppliedDirective
+	for i, fieldAST := range fieldPlan.fieldASTs {
+		if len(fieldPlan.fieldASTs) > 1 && !c.shouldIncludeNode(fieldPlan.conditions[i]) {
+			continue
+		}
+	directives:
+		for _, directiveAST := range fieldAST.Directives {
+			directive := c.Schema.GetDirective(directiveAST.Name.Value)
+			if directive == nil || directive.Resolve == nil {
+				continue
+			}
+			args := GetArgumentValues(directive.Args, directiveAST.Arguments, c.VariableValues)
+			for _, applied := range result {
+				if applied.Directive != directive {
+					continue
+				}
+				if !reflect.DeepEqual(applied.Args, args) {
+					return nil, fmt.Errorf(
+						`Directive "%v" is applied to field "%v" with different arguments.`,
+						directive.Name, fieldPlan.responseName)
+				}
+				continue directives
+			}
+			result = append(result, &typs.QLAppliedDirective{Directive: directive, Args: args})
+		}
+	}
+	return result, nil
+}
+
+func wrapResolve(
+	ctx context.Context,
+	resolve ql.DirectiveResolveFunc,
+	args map[string]interface{},
+	next func() (interface{}, error),
+) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		return resolve(ctx, args, next)
 	}
-	return c.completeValueCatchingError(returnType, fieldASTs, info, result)
 }
 
 func (c *_Context) completeValueCatchingError(
 	returnType typs.QLType,
-	fieldASTs []*lang.Field,
+	fieldPlan *_FieldPlan,
 	info typs.QLResolveInfo,
+	path *_Path,
 	result interface{},
 ) interface{} {
 
 	// If the field type is non-nullable, then it is resolved without any
 	// protection from errors.
 	if _, ok := returnType.(*typs.QLNonNull); ok {
-		return c.completeValue(returnType, fieldASTs, info, result)
+		return c.completeValue(returnType, fieldPlan, info, path, result)
 	}
 
 	// Otherwise, error protection is applied, logging the error and resolving
@@ -307,31 +427,32 @@ func (c *_Context) completeValueCatchingError(
 	defer func() {
 		err := recover()
 		if err != nil {
-			LOG.Println("Catched error:", err)
+			c.log(ql.LogDebug, "Recovered error", "path", path.toSlice(), "error", err)
 			if err, ok := err.(error); ok {
-				c.Errors = append(c.Errors, err)
+				c.addError(err)
 				return
 			}
-			c.Errors = append(c.Errors, errors.New(fmt.Sprint(err)))
+			c.addError(errors.New(fmt.Sprint(err)))
 		}
 	}()
 
-	completed := c.completeValue(returnType, fieldASTs, info, result)
+	completed := c.completeValue(returnType, fieldPlan, info, path, result)
 	return completed
 }
 
 func (c *_Context) completeValue(
 	returnType typs.QLType,
-	fieldASTs []*lang.Field,
+	fieldPlan *_FieldPlan,
 	info typs.QLResolveInfo,
+	path *_Path,
 	result interface{},
 ) interface{} {
 
 	if returnType, ok := returnType.(*typs.QLNonNull); ok {
-		completed := c.completeValue(returnType.OfType, fieldASTs, info, result)
+		completed := c.completeValue(returnType.OfType, fieldPlan, info, path, result)
 		if completed == nil {
-			nodes := make([]lang.INode, len(fieldASTs))
-			for i, node := range fieldASTs {
+			nodes := make([]lang.INode, len(fieldPlan.fieldASTs))
+			for i, node := range fieldPlan.fieldASTs {
 				nodes[i] = node
 			}
 			panic(lang.NewQLError(
@@ -344,7 +465,7 @@ func (c *_Context) completeValue(
 	}
 
 	v := reflect.ValueOf(result)
-	if result == nil {
+	if result == nil || v.Kind() == reflect.Ptr && v.IsNil() {
 		return nil
 	}
 
@@ -354,18 +475,16 @@ func (c *_Context) completeValue(
 	case *typs.QLList:
 		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
 			itemType := returnType.OfType
-			list := make([]interface{}, t.NumField())
-			for i, n := 0, t.NumField(); i < n; i++ {
-				list[i] = c.completeValueCatchingError(itemType, fieldASTs, info, v.Field(i).Interface())
+			list := make([]interface{}, v.Len())
+			for i := range list {
+				list[i] = c.completeValueCatchingError(itemType, fieldPlan, info, path.with(i),
+					v.Index(i).Interface())
 			}
 			return list
 		}
 		panic("User Error: expected array of slice, but did not find one.")
 
 	case *typs.QLScalar:
-		if returnType.Serialize == nil {
-			panic("Missing serialize method on type")
-		}
 		serializedResult := returnType.Serialize(result)
 		if serializedResult == nil {
 			return nil
@@ -376,16 +495,11 @@ func (c *_Context) completeValue(
 		return returnType.Serialize(result)
 
 	case *typs.QLObject:
-		runtimeType := returnType
-		subFieldASTs := map[string][]*lang.Field{}
-		visitedFragmentNames := map[string]struct{}{}
-		for _, fieldAST := range fieldASTs {
-			selectionSet := fieldAST.SelectionSet
-			if selectionSet != nil {
-				subFieldASTs = c.collectFields(runtimeType, selectionSet, subFieldASTs, visitedFragmentNames)
-			}
+		subPlan := fieldPlan.subPlans[returnType]
+		if subPlan == nil {
+			return map[string]interface{}{}
 		}
-		return c.executeFields(runtimeType, result, subFieldASTs)
+		return c.executeFields(subPlan, result, path)
 
 	case typs.QLAbstractType:
 		panic("not implemented")
@@ -395,6 +509,35 @@ func (c *_Context) completeValue(
 	}
 }
 
+/**
+ * The path of a value in the response, linked from the value to the root so
+ * that the paths of sibling fields share their parent.
+ */
+type _Path struct {
+	prev *_Path
+	key  interface{}
+}
+
+func (p *_Path) with(key interface{}) *_Path {
+	return &_Path{prev: p, key: key}
+}
+
+/**
+ * Returns the response names and list indexes of the path, from the root.
+ */
+func (p *_Path) toSlice() []interface{} {
+	n := 0
+	for q := p; q != nil; q = q.prev {
+		n++
+	}
+	result := make([]interface{}, n)
+	for q := p; q != nil; q = q.prev {
+		n--
+		result[n] = q.key
+	}
+	return result
+}
+
 func getOperationRootType(
 	schema typs.QLSchema,
 	operation *lang.OperationDefinition,
@@ -426,9 +569,8 @@ func getFieldEntryKey(node *lang.Field) string {
 
 /**
  * If a resolve function is not given, then a default resolve behavior is used
- * which takes the property of the source object of the same name as the field
- * and returns it as the result, or if it's a function, returns the result
- * of calling that function.
+ * which takes the entry of a map source, or the field of a struct source, of
+ * the same name as the field and returns it as the result.
  */
 func defaultResolveFn(
 	source interface{},
@@ -436,14 +578,30 @@ func defaultResolveFn(
 	info typs.QLResolveInfo,
 ) interface{} {
 	v := reflect.Indirect(reflect.ValueOf(source))
-	if v.Kind() != reflect.Struct {
-		return nil
-	}
-	field := v.FieldByName(info.FieldName)
-	if !field.IsValid() {
-		return nil
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		value := v.MapIndex(reflect.ValueOf(info.FieldName).Convert(v.Type().Key()))
+		if !value.IsValid() {
+			return nil
+		}
+		return value.Interface()
+
+	case reflect.Struct:
+		field := v.FieldByName(info.FieldName)
+		if !field.IsValid() {
+			// Go fields are exported, so "name" is resolved from Name.
+			name := info.FieldName
+			field = v.FieldByName(strings.ToUpper(name[:1]) + name[1:])
+		}
+		if !field.IsValid() {
+			return nil
+		}
+		return field.Interface()
 	}
-	return field.Interface()
+	return nil
 }
 
 func getFieldDef(
@@ -451,16 +609,8 @@ func getFieldDef(
 	parentType *typs.QLObject,
 	fieldName string,
 ) *typs.QLFieldDefinition {
-	if fieldName == typs.SchemaMetaFieldDef.Name &&
-		schema.GetQueryType() == parentType {
-		return typs.SchemaMetaFieldDef
-	}
-	if fieldName == typs.TypeMetaFieldDef.Name &&
-		schema.GetQueryType() == parentType {
-		return typs.TypeMetaFieldDef
-	}
-	if fieldName == typs.TypeNameMetaFieldDef.Name {
-		return typs.TypeNameMetaFieldDef
+	if fieldDef := schema.GetMetaFieldDef(parentType, fieldName); fieldDef != nil {
+		return fieldDef
 	}
 	return parentType.GetFields()[fieldName]
 }
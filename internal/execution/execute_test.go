@@ -0,0 +1,383 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	typs "github.com/ng-vu/graphql-go/internal/types"
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+var testQueryConfig ql.Object
+
+func init() {
+	testQueryConfig = ql.Object{
+		Name: "Query",
+		FieldsFunc: func() ql.FieldMap {
+			return ql.FieldMap{
+				"a":      {Type: ql.String},
+				"b":      {Type: ql.String},
+				"nested": {Type: testQueryConfig},
+			}
+		},
+	}
+}
+
+var testRootValue = map[string]interface{}{
+	"a": "A",
+	"b": "B",
+	"nested": map[string]interface{}{
+		"a": "nested A",
+		"b": "nested B",
+	},
+}
+
+func prepare(T *testing.T, schema typs.QLSchema, request string) *Plan {
+	documentAST, err := lang.Parse(lang.NewSource(request, ""))
+	if err != nil {
+		T.Fatal(err)
+	}
+	plan, err := Prepare(schema, documentAST, "")
+	if err != nil {
+		T.Fatal(err)
+	}
+	return plan
+}
+
+func expectData(T *testing.T, request string, result Result, expected interface{}) {
+	if len(result.Errors) != 0 {
+		T.Errorf("Expect no errors for %v but got: %v", request, result.Errors)
+		return
+	}
+	if !reflect.DeepEqual(result.Data, expected) {
+		T.Errorf("Expect data for %v:\n%#v\nbut got:\n%#v", request, expected, result.Data)
+	}
+}
+
+func TestExecute_FragmentSpreadsWithDirectives(T *testing.T) {
+	schema := typs.NewQLSchema(testQueryConfig, nil)
+	tests := []struct {
+		request  string
+		expected map[string]interface{}
+	}{
+		{
+			`{ ...F @skip(if: true) ...F } fragment F on Query { a }`,
+			map[string]interface{}{"a": "A"},
+		},
+		{
+			`{ ...F ...F @skip(if: true) } fragment F on Query { a }`,
+			map[string]interface{}{"a": "A"},
+		},
+		{
+			`{ ...F @include(if: false) ...F @include(if: true) } fragment F on Query { a }`,
+			map[string]interface{}{"a": "A"},
+		},
+		{
+			`{ ...F @skip(if: true) ...F @include(if: false) b } fragment F on Query { a }`,
+			map[string]interface{}{"b": "B"},
+		},
+		{
+			`{ ...F ...F } fragment F on Query { a }`,
+			map[string]interface{}{"a": "A"},
+		},
+		{
+			`{ nested { ...F @skip(if: true) } ...F } fragment F on Query { a }`,
+			map[string]interface{}{"nested": map[string]interface{}{}, "a": "A"},
+		},
+		{
+			`{ ... on Query @skip(if: true) { ...F } ...F } fragment F on Query { a }`,
+			map[string]interface{}{"a": "A"},
+		},
+		{
+			`{ ...F } fragment F on Query @skip(if: true) { a }`,
+			map[string]interface{}{},
+		},
+	}
+	for _, test := range tests {
+		plan := prepare(T, schema, test.request)
+		result := ExecutePlan(plan, Options{RootValue: testRootValue})
+		expectData(T, test.request, result, test.expected)
+	}
+}
+
+func TestExecute_FragmentSpreadsWithVariableDirectives(T *testing.T) {
+	schema := typs.NewQLSchema(testQueryConfig, nil)
+	request := `query Q($skip: Boolean!) {
+		...F @skip(if: $skip)
+		...G @include(if: $skip)
+	}
+	fragment F on Query { a }
+	fragment G on Query { b ...F }`
+
+	// The same plan is executed with different variables
+	plan := prepare(T, schema, request)
+	result := ExecutePlan(plan, Options{
+		RootValue:      testRootValue,
+		VariableValues: map[string]interface{}{"skip": true},
+	})
+	expectData(T, request, result, map[string]interface{}{"a": "A", "b": "B"})
+
+	result = ExecutePlan(plan, Options{
+		RootValue:      testRootValue,
+		VariableValues: map[string]interface{}{"skip": false},
+	})
+	expectData(T, request, result, map[string]interface{}{"a": "A"})
+}
+
+func TestExecute_FieldsWithDirectives(T *testing.T) {
+	schema := typs.NewQLSchema(testQueryConfig, nil)
+	request := `{ a @skip(if: true) b @include(if: false) nested { a @include(if: true) } }`
+	plan := prepare(T, schema, request)
+	result := ExecutePlan(plan, Options{RootValue: testRootValue})
+	expectData(T, request, result, map[string]interface{}{
+		"nested": map[string]interface{}{"a": "nested A"},
+	})
+
+	request = `{ a @skip(if: true) ... on Query { a } }`
+	plan = prepare(T, schema, request)
+	result = ExecutePlan(plan, Options{RootValue: testRootValue})
+	expectData(T, request, result, map[string]interface{}{"a": "A"})
+}
+
+func TestExecute_FragmentCyclesTerminate(T *testing.T) {
+	// Validation rejects fragment cycles, but planning must not loop on a
+	// document which has not been validated.
+	schema := typs.NewQLSchema(testQueryConfig, nil)
+	request := `{ ...F } fragment F on Query { a ...F @skip(if: false) ...G } fragment G on Query { ...F }`
+	plan := prepare(T, schema, request)
+	result := ExecutePlan(plan, Options{RootValue: testRootValue})
+	expectData(T, request, result, map[string]interface{}{"a": "A"})
+}
+
+func TestExecute_SerializesScalars(T *testing.T) {
+	schema := typs.NewQLSchema(ql.Object{
+		Name: "Query",
+		Fields: ql.FieldMap{
+			"boolean": {Type: ql.Boolean},
+			"string":  {Type: ql.String},
+			"list":    {Type: ql.List{OfType: ql.String}},
+		},
+	}, nil)
+	request := `{ boolean string list }`
+	plan := prepare(T, schema, request)
+	result := ExecutePlan(plan, Options{RootValue: map[string]interface{}{
+		"boolean": true,
+		"string":  "s",
+		"list":    []string{"a", "b"},
+	}})
+	expectData(T, request, result, map[string]interface{}{
+		"boolean": true,
+		"string":  "s",
+		"list":    []interface{}{"a", "b"},
+	})
+}
+
+var upperDirective = ql.Directive{
+	Name:      "upper",
+	Locations: []ql.DirectiveLocation{ql.LocationField},
+	Resolve: func(ctx context.Context, args map[string]interface{}, next func() (interface{}, error)) (interface{}, error) {
+		value, err := next()
+		if s, ok := value.(string); ok {
+			return strings.ToUpper(s), err
+		}
+		return value, err
+	},
+}
+
+var suffixDirective = ql.Directive{
+	Name:      "suffix",
+	Locations: []ql.DirectiveLocation{ql.LocationField},
+	Args:      ql.ArgumentMap{"value": {Type: ql.String}},
+	Resolve: func(ctx context.Context, args map[string]interface{}, next func() (interface{}, error)) (interface{}, error) {
+		value, err := next()
+		if s, ok := value.(string); ok {
+			return s + fmt.Sprint(args["value"]), err
+		}
+		return value, err
+	},
+}
+
+func TestExecute_QueryDirectives(T *testing.T) {
+	schema := typs.NewQLSchema(testQueryConfig, nil, upperDirective, suffixDirective)
+	rootValue := map[string]interface{}{"a": "hello", "b": "world"}
+	tests := []struct {
+		request  string
+		expected map[string]interface{}
+	}{
+		{`{ a @upper b }`, map[string]interface{}{"a": "HELLO", "b": "world"}},
+
+		// Each directive wraps the previous ones
+		{`{ a @suffix(value: "!") @upper }`, map[string]interface{}{"a": "HELLO!"}},
+		{`{ a @upper @suffix(value: "!") }`, map[string]interface{}{"a": "HELLO!"}},
+
+		// The directives of every selection of the field are collected
+		{`{ a ...F } fragment F on Query { a @upper }`, map[string]interface{}{"a": "HELLO"}},
+		{`{ a @suffix(value: "!") ... on Query { a @upper } }`, map[string]interface{}{"a": "HELLO!"}},
+
+		// and applied once
+		{`{ a @suffix(value: "!") ... on Query { a @suffix(value: "!") } }`, map[string]interface{}{"a": "hello!"}},
+
+		// except from the selections which are skipped
+		{`{ a ... on Query @skip(if: true) { a @upper } }`, map[string]interface{}{"a": "hello"}},
+		{`{ a @include(if: false) @upper ... on Query { a } }`, map[string]interface{}{"a": "hello"}},
+	}
+	for _, test := range tests {
+		plan := prepare(T, schema, test.request)
+		result := ExecutePlan(plan, Options{RootValue: rootValue})
+		expectData(T, test.request, result, test.expected)
+	}
+}
+
+func TestExecute_ConflictingQueryDirectives(T *testing.T) {
+	schema := typs.NewQLSchema(testQueryConfig, nil, upperDirective, suffixDirective)
+	rootValue := map[string]interface{}{"a": "hello", "b": "world"}
+	request := `query Q($suffix: String) {
+		a @suffix(value: $suffix)
+		... on Query { a @suffix(value: "!") }
+		b
+	}`
+	plan := prepare(T, schema, request)
+
+	result := ExecutePlan(plan, Options{
+		RootValue:      rootValue,
+		VariableValues: map[string]interface{}{"suffix": "!"},
+	})
+	expectData(T, request, result, map[string]interface{}{"a": "hello!", "b": "world"})
+
+	result = ExecutePlan(plan, Options{
+		RootValue:      rootValue,
+		VariableValues: map[string]interface{}{"suffix": "?"},
+	})
+	expected := map[string]interface{}{"b": "world"}
+	if !reflect.DeepEqual(result.Data, expected) {
+		T.Errorf("Expect data:\n%#v\nbut got:\n%#v", expected, result.Data)
+	}
+	message := `Directive "suffix" is applied to field "a" with different arguments.`
+	if len(result.Errors) != 1 || result.Errors[0].Error() != message {
+		T.Errorf("Expect error %q but got: %v", message, result.Errors)
+	}
+}
+
+var nonNullQueryConfig ql.Object
+
+func init() {
+	nonNullQueryConfig = ql.Object{
+		Name: "Query",
+		FieldsFunc: func() ql.FieldMap {
+			return ql.FieldMap{
+				"a":      {Type: ql.NonNull{OfType: ql.String}},
+				"b":      {Type: ql.String},
+				"nested": {Type: nonNullQueryConfig},
+			}
+		},
+	}
+}
+
+/**
+ * Executes the request and expects its data and the messages of its errors,
+ * in any order.
+ */
+func expectErrors(T *testing.T, plan *Plan, opts Options, expected interface{}, messages ...string) {
+	result := ExecutePlan(plan, opts)
+	if !reflect.DeepEqual(result.Data, expected) {
+		T.Errorf("Expect data:\n%#v\nbut got:\n%#v", expected, result.Data)
+	}
+	var actual []string
+	for _, err := range result.Errors {
+		actual = append(actual, err.Error())
+	}
+	sort.Strings(actual)
+	sort.Strings(messages)
+	if !reflect.DeepEqual(actual, messages) {
+		T.Errorf("Expect errors %q but got %q", messages, actual)
+	}
+}
+
+func TestExecute_NonNullFieldResolvingToNull(T *testing.T) {
+	schema := typs.NewQLSchema(nonNullQueryConfig, nil)
+	rootValue := map[string]interface{}{
+		"b":      "B",
+		"nested": map[string]interface{}{"b": "nested B"},
+	}
+
+	// The error makes the nullable parent field null
+	plan := prepare(T, schema, `{ b nested { a b } other: nested { b } }`)
+	expectErrors(T, plan, Options{RootValue: rootValue},
+		map[string]interface{}{"b": "B", "other": map[string]interface{}{"b": "nested B"}},
+		`Cannot return null for non-nullable field Query.a.`)
+
+	plan = prepare(T, schema, `{ nested { a } other: nested { a } }`)
+	expectErrors(T, plan, Options{RootValue: rootValue},
+		map[string]interface{}{},
+		`Cannot return null for non-nullable field Query.a.`,
+		`Cannot return null for non-nullable field Query.a.`)
+
+	// up to the data when there is no nullable parent
+	plan = prepare(T, schema, `{ a b }`)
+	expectErrors(T, plan, Options{RootValue: rootValue}, nil,
+		`Cannot return null for non-nullable field Query.a.`)
+}
+
+var denyDirective = ql.Directive{
+	Name:      "deny",
+	Locations: []ql.DirectiveLocation{ql.LocationField},
+	Resolve: func(ctx context.Context, args map[string]interface{}, next func() (interface{}, error)) (interface{}, error) {
+		return nil, errors.New("denied")
+	},
+}
+
+func TestExecute_DirectiveErrorOnNonNullField(T *testing.T) {
+	schema := typs.NewQLSchema(nonNullQueryConfig, nil, denyDirective)
+	rootValue := map[string]interface{}{
+		"a":      "A",
+		"b":      "B",
+		"nested": map[string]interface{}{"a": "nested A", "b": "nested B"},
+	}
+
+	plan := prepare(T, schema, `{ b nested { a @deny b } }`)
+	expectErrors(T, plan, Options{RootValue: rootValue},
+		map[string]interface{}{"b": "B"}, `denied`)
+
+	plan = prepare(T, schema, `{ a @deny b }`)
+	expectErrors(T, plan, Options{RootValue: rootValue}, nil, `denied`)
+}
+
+func TestExecute_ConstantArgumentsAreCopied(T *testing.T) {
+	schema := typs.NewQLSchema(ql.Object{
+		Name: "Query",
+		Fields: ql.FieldMap{
+			"a": {
+				Type: ql.String,
+				Args: ql.ArgumentMap{
+					"input": {Type: ql.InputObject{
+						Name:   "Input",
+						Fields: ql.InputObjectFieldMap{"s": {Type: ql.String}},
+					}},
+				},
+				Resolve: func(args struct{ Input map[string]interface{} }) interface{} {
+					s := args.Input["s"]
+					args.Input["s"] = "changed"
+					return s
+				},
+			},
+		},
+	}, nil)
+	clear := func(ctx context.Context, info ql.ResolveInfo, next func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+		result, err := next(ctx)
+		delete(info.Args, "input")
+		return result, err
+	}
+
+	request := `{ a(input: { s: "s" }) }`
+	plan := prepare(T, schema, request)
+	for i := 0; i < 2; i++ {
+		result := ExecutePlan(plan, Options{Middlewares: []ql.Middleware{clear}})
+		expectData(T, request, result, map[string]interface{}{"a": "s"})
+	}
+}
@@ -1,7 +1,9 @@
 package execution
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 
 	lang "github.com/ng-vu/graphql-go/internal/language"
 	typs "github.com/ng-vu/graphql-go/internal/types"
@@ -16,17 +18,29 @@ func GetVariableValues(
 	values := make(map[string]interface{})
 	for _, defAST := range definitionASTs {
 		varName := defAST.Variable.Name.Value
-		values[varName] = getVariableValue(schema, defAST, inputs[varName])
+		input, provided := inputs[varName]
+		value := getVariableValue(schema, defAST, input, provided)
+
+		// A variable which is neither given nor has a default value is left
+		// out, so that the arguments using it take their own default value.
+		if provided || defAST.DefaultValue != nil {
+			values[varName] = value
+		}
 	}
 	return values
 }
 
+/**
+ * Returns the values of the arguments. An argument which is not given takes
+ * its default value, while one explicitly given null is null.
+ */
+
 func GetArgumentValues(
 	argDefs []*typs.QLArgument,
 	argASTs []*lang.Argument,
 	variableValues map[string]interface{},
 ) map[string]interface{} {
-	if len(argDefs) == 0 || len(argASTs) == 0 {
+	if len(argDefs) == 0 {
 		return nil
 	}
 	argASTMap := make(map[string]*lang.Argument)
@@ -40,45 +54,59 @@ func GetArgumentValues(
 		if argAST, ok := argASTMap[name]; ok {
 			valueAST = argAST.Value
 		}
-		value := util.ValueFromAST(valueAST, argDef.Type, variableValues)
-		if value == nil {
-			value = argDef.DefaultValue
-		}
-		if value != nil {
-			result[name] = value
+		if !util.IsValueProvided(valueAST, variableValues) {
+			if argDef.DefaultValue != nil {
+				result[name] = argDef.DefaultValue
+			}
+			continue
 		}
+		result[name] = util.ValueFromAST(valueAST, argDef.Type, variableValues)
 	}
 	return result
 }
 
+/**
+ * Given a variable definition, and any value of input, return a value which
+ * adheres to the variable definition, or throw an error. The default value is
+ * used when no input is provided, but not when null is.
+ */
 func getVariableValue(
 	schema typs.QLSchema,
 	definitionAST *lang.VariableDefinition,
-	input interface{}) interface{} {
-	return nil
-	// typ := util.TypeFromAST(schema, definitionAST.Type)
-	// variable := definitionAST.Variable
-	// if _, ok := typ.(typs.QLInputType); !ok {
-	// 	panic(lang.NewQLError(
-	// 		fmt.Sprintf(
-	// 			`Variable "$%v" expected value of type "%v" which cannot be used as an input type.`,
-	// 			variable.Name.Value, lang.Print(definitionAST.Type)),
-	// 		[]lang.INode{definitionAST}))
-	// }
-	// // TODO: isValidJSValue
-	// return coerceValue(typ, input)
-	// if input == nil {
-	// 	panic(lang.NewQLError(
-	// 		fmt.Sprintf(
-	// 			`Variable "$%v" of required type "$%v" was not provided.`,
-	// 			variable.Name.Value, lang.Print(definitionAST.Type)),
-	// 		[]lang.INode{definitionAST}))
-	// }
-	// panic(lang.NewQLError(
-	// 	fmt.Sprintf(
-	// 		`Variable "$%v" expected value of type "%v" but got: %v`,
-	// 		variable.Name.Value, lang.Print(definitionAST.Type)),
-	// 	[]lang.INode{definitionAST}))
+	input interface{},
+	provided bool) interface{} {
+
+	variable := definitionAST.Variable
+	typ, ok := util.TypeFromAST(schema, definitionAST.Type).(typs.QLInputType)
+	if !ok {
+		panic(lang.NewQLError(
+			fmt.Sprintf(
+				`Variable "$%v" expected value of type "%v" which cannot be used as an input type.`,
+				variable.Name.Value, lang.Print(definitionAST.Type)),
+			[]lang.INode{definitionAST}))
+	}
+	if util.IsNil(input) {
+		if _, ok := typ.(*typs.QLNonNull); ok {
+			panic(lang.NewQLError(
+				fmt.Sprintf(
+					`Variable "$%v" of required type "%v" was not provided.`,
+					variable.Name.Value, lang.Print(definitionAST.Type)),
+				[]lang.INode{definitionAST}))
+		}
+		if !provided && definitionAST.DefaultValue != nil {
+			return util.ValueFromAST(definitionAST.DefaultValue, typ, nil)
+		}
+		return nil
+	}
+	value := coerceValue(typ, input)
+	if value == nil {
+		panic(lang.NewQLError(
+			fmt.Sprintf(
+				`Variable "$%v" expected value of type "%v" but got: %v.`,
+				variable.Name.Value, lang.Print(definitionAST.Type), input),
+			[]lang.INode{definitionAST}))
+	}
+	return value
 }
 
 /**
@@ -90,19 +118,18 @@ func coerceValue(typ typs.QLType, value interface{}) interface{} {
 		return coerceValue(nullableType, value)
 	}
 
-	v := reflect.ValueOf(value)
-	if value == nil || v.IsNil() {
+	if util.IsNil(value) {
 		return nil
 	}
 
+	v := reflect.Indirect(reflect.ValueOf(value))
 	switch typ := typ.(type) {
 	case *typs.QLList:
 		itemType := typ.OfType
 		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
 			result := make([]interface{}, v.Len())[:0]
 			for i, n := 0, v.Len(); i < n; i++ {
-				fieldValue := v.Field(i)
-				result = append(result, coerceValue(itemType, fieldValue))
+				result = append(result, coerceValue(itemType, v.Index(i).Interface()))
 			}
 			return result
 		}
@@ -112,12 +139,29 @@ func coerceValue(typ typs.QLType, value interface{}) interface{} {
 		fields := typ.GetFields()
 		obj := make(map[string]interface{})
 		for fieldName, field := range fields {
-			runtimeField := v.FieldByName(fieldName)
-			if !runtimeField.IsValid() {
-				continue
+			var fieldValue interface{}
+			switch v.Kind() {
+			case reflect.Map:
+				if v.Type().Key().Kind() != reflect.String {
+					return nil
+				}
+				key := reflect.ValueOf(fieldName).Convert(v.Type().Key())
+				if runtimeField := v.MapIndex(key); runtimeField.IsValid() {
+					// A field given null is null rather than its default
+					if util.IsNil(runtimeField.Interface()) {
+						obj[fieldName] = nil
+						continue
+					}
+					fieldValue = coerceValue(field.Type, runtimeField.Interface())
+				}
+			case reflect.Struct:
+				name := strings.ToUpper(fieldName[:1]) + fieldName[1:]
+				if runtimeField := v.FieldByName(name); runtimeField.IsValid() {
+					fieldValue = coerceValue(field.Type, runtimeField.Interface())
+				}
+			default:
+				return nil
 			}
-
-			fieldValue := coerceValue(field.Type, runtimeField.Interface())
 			if fieldValue == nil {
 				fieldValue = field.DefaultValue
 			}
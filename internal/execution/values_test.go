@@ -0,0 +1,183 @@
+package execution
+
+import (
+	"reflect"
+	"testing"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	typs "github.com/ng-vu/graphql-go/internal/types"
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+var valuesInputConfig = ql.InputObject{
+	Name: "Input",
+	Fields: ql.InputObjectFieldMap{
+		"name":  {Type: ql.NonNull{OfType: ql.String}},
+		"count": {Type: ql.Int, DefaultValue: 1},
+		"tags":  {Type: ql.List{OfType: ql.String}},
+	},
+}
+
+var valuesSchema = typs.NewQLSchema(ql.Object{
+	Name: "Query",
+	Fields: ql.FieldMap{
+		"field": {
+			Type: ql.String,
+			Args: ql.ArgumentMap{
+				"input": {Type: valuesInputConfig},
+				"n":     {Type: ql.Int, DefaultValue: 5},
+			},
+		},
+	},
+}, nil)
+
+/**
+ * Returns the values of the variables of the request, or the message of the
+ * error raised while coercing them.
+ */
+func variableValues(T *testing.T, request string, inputs map[string]interface{}) (values map[string]interface{}, message string) {
+	plan := prepare(T, valuesSchema, request)
+	defer func() {
+		if e := recover(); e != nil {
+			err, ok := e.(error)
+			if !ok {
+				panic(e)
+			}
+			message = err.Error()
+		}
+	}()
+	return GetVariableValues(valuesSchema, plan.Operation.VariableDefinitions, inputs), ""
+}
+
+func TestGetVariableValues(T *testing.T) {
+	tests := []struct {
+		request  string
+		inputs   map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{`query Q($a: Int) { field }`, map[string]interface{}{"a": 1}, map[string]interface{}{"a": int64(1)}},
+		{`query Q($a: Int = 3) { field }`, nil, map[string]interface{}{"a": int64(3)}},
+		{`query Q($a: Int = 3) { field }`, map[string]interface{}{"a": 4}, map[string]interface{}{"a": int64(4)}},
+
+		// A variable given null is null, and one not given is left out
+		{`query Q($a: Int) { field }`, map[string]interface{}{"a": nil}, map[string]interface{}{"a": nil}},
+		{`query Q($a: Int = 3) { field }`, map[string]interface{}{"a": nil}, map[string]interface{}{"a": nil}},
+		{`query Q($a: Int) { field }`, nil, map[string]interface{}{}},
+
+		// A single value is given to a list as a list of one item
+		{`query Q($a: [String]) { field }`, map[string]interface{}{"a": "x"}, map[string]interface{}{"a": []interface{}{"x"}}},
+		{`query Q($a: [String]) { field }`, map[string]interface{}{"a": []string{"x", "y"}}, map[string]interface{}{"a": []interface{}{"x", "y"}}},
+
+		// The fields of input objects are coerced, and take their default values
+		{
+			`query Q($a: Input) { field }`,
+			map[string]interface{}{"a": map[string]interface{}{"name": "n", "tags": "t"}},
+			map[string]interface{}{"a": map[string]interface{}{"name": "n", "count": 1, "tags": []interface{}{"t"}}},
+		},
+		{
+			`query Q($a: Input) { field }`,
+			map[string]interface{}{"a": map[string]interface{}{"name": "n", "count": nil}},
+			map[string]interface{}{"a": map[string]interface{}{"name": "n", "count": nil}},
+		},
+		{
+			`query Q($a: Input) { field }`,
+			map[string]interface{}{"a": struct {
+				Name  string
+				Count int
+			}{"n", 2}},
+			map[string]interface{}{"a": map[string]interface{}{"name": "n", "count": int64(2)}},
+		},
+	}
+	for _, test := range tests {
+		values, message := variableValues(T, test.request, test.inputs)
+		if message != "" {
+			T.Errorf("Expect no error for %v but got: %v", test.request, message)
+			continue
+		}
+		if !reflect.DeepEqual(values, test.expected) {
+			T.Errorf("Expect values for %v:\n%#v\nbut got:\n%#v", test.request, test.expected, values)
+		}
+	}
+}
+
+/**
+ * Returns the values of the arguments of the first field of the request.
+ */
+func argumentValues(T *testing.T, request string, inputs map[string]interface{}) map[string]interface{} {
+	plan := prepare(T, valuesSchema, request)
+	variables := GetVariableValues(valuesSchema, plan.Operation.VariableDefinitions, inputs)
+	fieldAST := plan.Operation.SelectionSet.Selections[0].(*lang.Field)
+	fieldDef := valuesSchema.GetQueryType().GetFields()[fieldAST.Name.Value]
+	return GetArgumentValues(fieldDef.Args, fieldAST.Arguments, variables)
+}
+
+func TestGetArgumentValues(T *testing.T) {
+	tests := []struct {
+		request  string
+		inputs   map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{`{ field }`, nil, map[string]interface{}{"n": 5}},
+		{`{ field(n: 2) }`, nil, map[string]interface{}{"n": int64(2)}},
+
+		// An explicit null does not fall back to the default value
+		{`{ field(n: null) }`, nil, map[string]interface{}{"n": nil}},
+		{`query Q($v: Int) { field(n: $v) }`, nil, map[string]interface{}{"n": 5}},
+		{`query Q($v: Int) { field(n: $v) }`, map[string]interface{}{"v": nil}, map[string]interface{}{"n": nil}},
+		{`query Q($v: Int = 3) { field(n: $v) }`, nil, map[string]interface{}{"n": int64(3)}},
+		{`query Q($v: Int = 3) { field(n: $v) }`, map[string]interface{}{"v": nil}, map[string]interface{}{"n": nil}},
+
+		// and neither does a field of an input object
+		{
+			`{ field(input: {name: "a"}) }`,
+			nil,
+			map[string]interface{}{"n": 5, "input": map[string]interface{}{"name": "a", "count": 1}},
+		},
+		{
+			`{ field(input: {name: "a", count: null, tags: null}) }`,
+			nil,
+			map[string]interface{}{"n": 5, "input": map[string]interface{}{"name": "a", "count": nil, "tags": nil}},
+		},
+		{
+			`query Q($c: Int) { field(input: {name: "a", count: $c}) }`,
+			nil,
+			map[string]interface{}{"n": 5, "input": map[string]interface{}{"name": "a", "count": 1}},
+		},
+		{
+			`query Q($c: Int) { field(input: {name: "a", count: $c}) }`,
+			map[string]interface{}{"c": nil},
+			map[string]interface{}{"n": 5, "input": map[string]interface{}{"name": "a", "count": nil}},
+		},
+	}
+	for _, test := range tests {
+		values := argumentValues(T, test.request, test.inputs)
+		if !reflect.DeepEqual(values, test.expected) {
+			T.Errorf("Expect arguments of %v with %v:\n%#v\nbut got:\n%#v", test.request, test.inputs, test.expected, values)
+		}
+	}
+}
+
+func TestGetVariableValues_Errors(T *testing.T) {
+	tests := []struct {
+		request string
+		inputs  map[string]interface{}
+		message string
+	}{
+		{
+			`query Q($a: Int!) { field }`,
+			nil,
+			`Variable "$a" of required type "Int!" was not provided.`,
+		},
+		{
+			`query Q($a: Input) { field }`,
+			map[string]interface{}{"a": 1},
+			`Variable "$a" expected value of type "Input" but got: 1.`,
+		},
+	}
+	for _, test := range tests {
+		_, message := variableValues(T, test.request, test.inputs)
+		if message != test.message {
+			T.Errorf("Expect error for %v:\n%v\nbut got:\n%v", test.request, test.message, message)
+		}
+	}
+}
@@ -4,6 +4,32 @@ type Location struct {
 	Start  int
 	End    int
 	Source *Source
+
+	// Comments are only kept with ParseOptions.Comments.
+	Comments *Comments
+}
+
+/**
+ * The comments attached to a node. The text of a comment is what follows its
+ * #, without the trailing whitespace.
+ *
+ * The comments are attached to the definitions, the variable definitions,
+ * the selections, the arguments, the descriptions, and the field, argument,
+ * enum value and operation type definitions. The other comments are moved
+ * before the next of these nodes.
+ */
+type Comments struct {
+	// Before are the comments on the lines before the node. They are printed
+	// before its description.
+	Before []string
+
+	// After is the comment following the node on its last line.
+	After string
+
+	// Inner are the comments before the closing brace of the block of the
+	// node, at the end of the document, or between a description and its
+	// definition.
+	Inner []string
 }
 
 func (l *Location) Loc() *Location {
@@ -32,6 +58,7 @@ func (n *IntValue) Kind() NodeKind                  { return INT_VALUE }
 func (n *FloatValue) Kind() NodeKind                { return FLOAT_VALUE }
 func (n *StringValue) Kind() NodeKind               { return STRING_VALUE }
 func (n *BooleanValue) Kind() NodeKind              { return BOOLEAN_VALUE }
+func (n *NullValue) Kind() NodeKind                 { return NULL_VALUE }
 func (n *EnumValue) Kind() NodeKind                 { return ENUM_VALUE }
 func (n *ListValue) Kind() NodeKind                 { return LIST_VALUE }
 func (n *ObjectValue) Kind() NodeKind               { return OBJECT_VALUE }
@@ -50,6 +77,15 @@ func (n *EnumTypeDefinition) Kind() NodeKind        { return ENUM_TYPE_DEFINITIO
 func (n *EnumValueDefinition) Kind() NodeKind       { return ENUM_VALUE_DEFINITION }
 func (n *InputObjectTypeDefinition) Kind() NodeKind { return INPUT_OBJECT_TYPE_DEFINITION }
 func (n *TypeExtensionDefinition) Kind() NodeKind   { return TYPE_EXTENSION_DEFINITION }
+func (n *SchemaDefinition) Kind() NodeKind          { return SCHEMA_DEFINITION }
+func (n *OperationTypeDefinition) Kind() NodeKind   { return OPERATION_TYPE_DEFINITION }
+func (n *DirectiveDefinition) Kind() NodeKind       { return DIRECTIVE_DEFINITION }
+func (n *SchemaExtension) Kind() NodeKind           { return SCHEMA_EXTENSION }
+func (n *ScalarTypeExtension) Kind() NodeKind       { return SCALAR_TYPE_EXTENSION }
+func (n *InterfaceTypeExtension) Kind() NodeKind    { return INTERFACE_TYPE_EXTENSION }
+func (n *UnionTypeExtension) Kind() NodeKind        { return UNION_TYPE_EXTENSION }
+func (n *EnumTypeExtension) Kind() NodeKind         { return ENUM_TYPE_EXTENSION }
+func (n *InputObjectTypeExtension) Kind() NodeKind  { return INPUT_OBJECT_TYPE_EXTENSION }
 
 func (n *Name) clone() INode                    { var v = &Name{}; *v = *n; return v }
 func (n *Document) clone() INode                { var v = &Document{}; *v = *n; return v }
@@ -66,6 +102,7 @@ func (n *IntValue) clone() INode                { var v = &IntValue{}; *v = *n;
 func (n *FloatValue) clone() INode              { var v = &FloatValue{}; *v = *n; return v }
 func (n *StringValue) clone() INode             { var v = &StringValue{}; *v = *n; return v }
 func (n *BooleanValue) clone() INode            { var v = &BooleanValue{}; *v = *n; return v }
+func (n *NullValue) clone() INode               { var v = &NullValue{}; *v = *n; return v }
 func (n *EnumValue) clone() INode               { var v = &EnumValue{}; *v = *n; return v }
 func (n *ListValue) clone() INode               { var v = &ListValue{}; *v = *n; return v }
 func (n *ObjectValue) clone() INode             { var v = &ObjectValue{}; *v = *n; return v }
@@ -88,6 +125,19 @@ func (n *InputObjectTypeDefinition) clone() INode {
 	return v
 }
 func (n *TypeExtensionDefinition) clone() INode { var v = &TypeExtensionDefinition{}; *v = *n; return v }
+func (n *SchemaDefinition) clone() INode        { var v = &SchemaDefinition{}; *v = *n; return v }
+func (n *OperationTypeDefinition) clone() INode { var v = &OperationTypeDefinition{}; *v = *n; return v }
+func (n *DirectiveDefinition) clone() INode     { var v = &DirectiveDefinition{}; *v = *n; return v }
+func (n *SchemaExtension) clone() INode         { var v = &SchemaExtension{}; *v = *n; return v }
+func (n *ScalarTypeExtension) clone() INode     { var v = &ScalarTypeExtension{}; *v = *n; return v }
+func (n *InterfaceTypeExtension) clone() INode  { var v = &InterfaceTypeExtension{}; *v = *n; return v }
+func (n *UnionTypeExtension) clone() INode      { var v = &UnionTypeExtension{}; *v = *n; return v }
+func (n *EnumTypeExtension) clone() INode       { var v = &EnumTypeExtension{}; *v = *n; return v }
+func (n *InputObjectTypeExtension) clone() INode {
+	var v = &InputObjectTypeExtension{}
+	*v = *n
+	return v
+}
 
 // Name
 
@@ -224,6 +274,7 @@ func (n *IntValue) valueNode()     {}
 func (n *FloatValue) valueNode()   {}
 func (n *StringValue) valueNode()  {}
 func (n *BooleanValue) valueNode() {}
+func (n *NullValue) valueNode()    {}
 func (n *EnumValue) valueNode()    {}
 func (n *ListValue) valueNode()    {}
 func (n *ObjectValue) valueNode()  {}
@@ -251,6 +302,10 @@ type FloatValue struct {
 type StringValue struct {
 	*Location
 	Value string
+
+	// Block is true when the value was written as a block string, in which
+	// case it is printed as one.
+	Block bool
 }
 
 type BooleanValue struct {
@@ -258,6 +313,10 @@ type BooleanValue struct {
 	Value string
 }
 
+type NullValue struct {
+	*Location
+}
+
 type EnumValue struct {
 	*Location
 	Value string
@@ -321,6 +380,40 @@ type NonNullType struct {
 	Type INonNullType
 }
 
+// Type System Definitions
+
+func (n *SchemaDefinition) definitionNode()    {}
+func (n *DirectiveDefinition) definitionNode() {}
+func (n *SchemaExtension) definitionNode()     {}
+
+type SchemaDefinition struct {
+	*Location
+	Description    *StringValue
+	Directives     []*Directive
+	OperationTypes []*OperationTypeDefinition
+}
+
+type OperationTypeDefinition struct {
+	*Location
+	Operation OperationType
+	Type      *NamedType
+}
+
+type DirectiveDefinition struct {
+	*Location
+	Description *StringValue
+	Name        *Name
+	Arguments   []*InputValueDefinition
+	Repeatable  bool
+	Locations   []*Name
+}
+
+type SchemaExtension struct {
+	*Location
+	Directives     []*Directive
+	OperationTypes []*OperationTypeDefinition
+}
+
 // IType IDefinition
 
 type ITypeDefinition interface {
@@ -346,60 +439,138 @@ func (n *TypeExtensionDefinition) typeDefinitionNode()   {}
 
 type ObjectTypeDefinition struct {
 	*Location
-	Name       *Name
-	Interfaces []*NamedType
-	Fields     []*FieldDefinition
+	Description *StringValue
+	Name        *Name
+	Interfaces  []*NamedType
+	Directives  []*Directive
+	Fields      []*FieldDefinition
 }
 
 type FieldDefinition struct {
 	*Location
-	Name      *Name
-	Arguments []*InputValueDefinition
-	Type      IType
+	Description *StringValue
+	Name        *Name
+	Arguments   []*InputValueDefinition
+	Type        IType
+	Directives  []*Directive
 }
 
 type InputValueDefinition struct {
 	*Location
+	Description  *StringValue
 	Name         *Name
 	Type         IType
 	DefaultValue IValue
+	Directives   []*Directive
 }
 
 type InterfaceTypeDefinition struct {
 	*Location
-	Name   *Name
-	Fields []*FieldDefinition
+	Description *StringValue
+	Name        *Name
+	Interfaces  []*NamedType
+	Directives  []*Directive
+	Fields      []*FieldDefinition
 }
 
 type UnionTypeDefinition struct {
 	*Location
-	Name  *Name
-	Types []*NamedType
+	Description *StringValue
+	Name        *Name
+	Directives  []*Directive
+	Types       []*NamedType
 }
 
 type ScalarTypeDefinition struct {
 	*Location
-	Name *Name
+	Description *StringValue
+	Name        *Name
+	Directives  []*Directive
 }
 
 type EnumTypeDefinition struct {
 	*Location
-	Name   *Name
-	Values []*EnumValueDefinition
+	Description *StringValue
+	Name        *Name
+	Directives  []*Directive
+	Values      []*EnumValueDefinition
 }
 
 type EnumValueDefinition struct {
 	*Location
-	Name *Name
+	Description *StringValue
+	Name        *Name
+	Directives  []*Directive
 }
 
 type InputObjectTypeDefinition struct {
 	*Location
-	Name   *Name
-	Fields []*InputValueDefinition
+	Description *StringValue
+	Name        *Name
+	Directives  []*Directive
+	Fields      []*InputValueDefinition
 }
 
 type TypeExtensionDefinition struct {
 	*Location
 	Definition *ObjectTypeDefinition
 }
+
+// Type Extensions
+
+/**
+ * The extension of a type defined elsewhere. TypeExtensionDefinition extends
+ * object types.
+ */
+type ITypeExtension interface {
+	IDefinition
+	typeExtensionNode()
+}
+
+func (n *ScalarTypeExtension) definitionNode()      {}
+func (n *InterfaceTypeExtension) definitionNode()   {}
+func (n *UnionTypeExtension) definitionNode()       {}
+func (n *EnumTypeExtension) definitionNode()        {}
+func (n *InputObjectTypeExtension) definitionNode() {}
+
+func (n *TypeExtensionDefinition) typeExtensionNode()  {}
+func (n *ScalarTypeExtension) typeExtensionNode()      {}
+func (n *InterfaceTypeExtension) typeExtensionNode()   {}
+func (n *UnionTypeExtension) typeExtensionNode()       {}
+func (n *EnumTypeExtension) typeExtensionNode()        {}
+func (n *InputObjectTypeExtension) typeExtensionNode() {}
+
+type ScalarTypeExtension struct {
+	*Location
+	Name       *Name
+	Directives []*Directive
+}
+
+type InterfaceTypeExtension struct {
+	*Location
+	Name       *Name
+	Interfaces []*NamedType
+	Directives []*Directive
+	Fields     []*FieldDefinition
+}
+
+type UnionTypeExtension struct {
+	*Location
+	Name       *Name
+	Directives []*Directive
+	Types      []*NamedType
+}
+
+type EnumTypeExtension struct {
+	*Location
+	Name       *Name
+	Directives []*Directive
+	Values     []*EnumValueDefinition
+}
+
+type InputObjectTypeExtension struct {
+	*Location
+	Name       *Name
+	Directives []*Directive
+	Fields     []*InputValueDefinition
+}
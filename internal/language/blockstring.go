@@ -0,0 +1,116 @@
+package language
+
+import (
+	"strings"
+)
+
+/**
+ * Produces the value of a block string from its raw content, as described by
+ * BlockStringValue() in the specification:
+ *
+ *   - the line terminators are normalized to "\n",
+ *   - the common indentation of the lines after the first one is removed,
+ *   - the leading and trailing blank lines are removed.
+ */
+func blockStringValue(raw string) string {
+	lines := splitLines(raw)
+
+	commonIndent := -1
+	for _, line := range lines[1:] {
+		indent := leadingWhitespace(line)
+		if indent < len(line) && (commonIndent < 0 || indent < commonIndent) {
+			commonIndent = indent
+		}
+	}
+	if commonIndent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) < commonIndent {
+				lines[i] = ""
+			} else {
+				lines[i] = lines[i][commonIndent:]
+			}
+		}
+	}
+
+	for len(lines) > 0 && leadingWhitespace(lines[0]) == len(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && leadingWhitespace(lines[len(lines)-1]) == len(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+/**
+ * Prints a value as a block string which blockStringValue reads back as the
+ * same value. It is written on one line when short enough, otherwise the
+ * content starts on the line after the opening quotes.
+ */
+func printBlockString(value string) string {
+	escaped := strings.Replace(value, `"""`, `\"""`, -1)
+	lines := splitLines(escaped)
+	singleLine := len(lines) == 1
+
+	// The indentation of the lines after the first one would be removed if
+	// the first line was not a blank line.
+	forceLeadingNewline := len(lines) > 1
+	for _, line := range lines[1:] {
+		if line != "" && leadingWhitespace(line) == 0 {
+			forceLeadingNewline = false
+			break
+		}
+	}
+
+	// The closing quotes would be read as part of an escape sequence or
+	// of the content.
+	hasTrailingTripleQuotes := strings.HasSuffix(escaped, `\"""`)
+	forceTrailingNewline := strings.HasSuffix(value, `"`) && !hasTrailingTripleQuotes ||
+		strings.HasSuffix(value, `\`)
+
+	multiline := !singleLine || len(value) > 70 ||
+		forceTrailingNewline || forceLeadingNewline || hasTrailingTripleQuotes
+
+	result := `"""`
+	skipLeadingNewline := singleLine && leadingWhitespace(value) > 0
+	if multiline && !skipLeadingNewline || forceLeadingNewline {
+		result += "\n"
+	}
+	result += escaped
+	if multiline || forceTrailingNewline {
+		result += "\n"
+	}
+	return result + `"""`
+}
+
+/**
+ * Splits s at the line terminators: "\r\n", "\n" and "\r".
+ */
+func splitLines(s string) []string {
+	var lines []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\r':
+			lines = append(lines, s[start:i])
+			if i+1 < len(s) && s[i+1] == '\n' {
+				i++
+			}
+			start = i + 1
+		case '\n':
+			lines = append(lines, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(lines, s[start:])
+}
+
+/**
+ * Returns the number of spaces and tabs at the start of line.
+ */
+func leadingWhitespace(line string) int {
+	i := 0
+	for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	return i
+}
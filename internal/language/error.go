@@ -4,12 +4,8 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
-
-	"github.com/ng-vu/graphql-go/internal/debug"
 )
 
-var LOG = debug.New("language")
-
 type QLError struct {
 	Message   string
 	Stack     string
@@ -0,0 +1,317 @@
+package language
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+/**
+ * Creates the node of each kind of the graphql-js JSON. The JSON fields of a
+ * node are the fields of its struct, starting with a lower case letter.
+ */
+var nodesByKind = map[NodeKind]func() INode{
+	NAME:                         func() INode { return &Name{} },
+	DOCUMENT:                     func() INode { return &Document{} },
+	OPERATION_DEFINITION:         func() INode { return &OperationDefinition{} },
+	VARIABLE_DEFINITION:          func() INode { return &VariableDefinition{} },
+	VARIABLE:                     func() INode { return &Variable{} },
+	SELECTION_SET:                func() INode { return &SelectionSet{} },
+	FIELD:                        func() INode { return &Field{} },
+	ARGUMENT:                     func() INode { return &Argument{} },
+	FRAGMENT_SPREAD:              func() INode { return &FragmentSpread{} },
+	INLINE_FRAGMENT:              func() INode { return &InlineFragment{} },
+	FRAGMENT_DEFINITION:          func() INode { return &FragmentDefinition{} },
+	INT_VALUE:                    func() INode { return &IntValue{} },
+	FLOAT_VALUE:                  func() INode { return &FloatValue{} },
+	STRING_VALUE:                 func() INode { return &StringValue{} },
+	BOOLEAN_VALUE:                func() INode { return &BooleanValue{} },
+	NULL_VALUE:                   func() INode { return &NullValue{} },
+	ENUM_VALUE:                   func() INode { return &EnumValue{} },
+	LIST_VALUE:                   func() INode { return &ListValue{} },
+	OBJECT_VALUE:                 func() INode { return &ObjectValue{} },
+	OBJECT_FIELD:                 func() INode { return &ObjectField{} },
+	DIRECTIVE:                    func() INode { return &Directive{} },
+	NAMED_TYPE:                   func() INode { return &NamedType{} },
+	LIST_TYPE:                    func() INode { return &ListType{} },
+	NON_NULL_TYPE:                func() INode { return &NonNullType{} },
+	OBJECT_TYPE_DEFINITION:       func() INode { return &ObjectTypeDefinition{} },
+	FIELD_DEFINITION:             func() INode { return &FieldDefinition{} },
+	INPUT_VALUE_DEFINITION:       func() INode { return &InputValueDefinition{} },
+	INTERFACE_TYPE_DEFINITION:    func() INode { return &InterfaceTypeDefinition{} },
+	UNION_TYPE_DEFINITION:        func() INode { return &UnionTypeDefinition{} },
+	SCALAR_TYPE_DEFINITION:       func() INode { return &ScalarTypeDefinition{} },
+	ENUM_TYPE_DEFINITION:         func() INode { return &EnumTypeDefinition{} },
+	ENUM_VALUE_DEFINITION:        func() INode { return &EnumValueDefinition{} },
+	INPUT_OBJECT_TYPE_DEFINITION: func() INode { return &InputObjectTypeDefinition{} },
+	TYPE_EXTENSION_DEFINITION:    func() INode { return &TypeExtensionDefinition{} },
+	SCHEMA_DEFINITION:            func() INode { return &SchemaDefinition{} },
+	OPERATION_TYPE_DEFINITION:    func() INode { return &OperationTypeDefinition{} },
+	DIRECTIVE_DEFINITION:         func() INode { return &DirectiveDefinition{} },
+	SCHEMA_EXTENSION:             func() INode { return &SchemaExtension{} },
+	SCALAR_TYPE_EXTENSION:        func() INode { return &ScalarTypeExtension{} },
+	INTERFACE_TYPE_EXTENSION:     func() INode { return &InterfaceTypeExtension{} },
+	UNION_TYPE_EXTENSION:         func() INode { return &UnionTypeExtension{} },
+	ENUM_TYPE_EXTENSION:          func() INode { return &EnumTypeExtension{} },
+	INPUT_OBJECT_TYPE_EXTENSION:  func() INode { return &InputObjectTypeExtension{} },
+}
+
+/**
+ * Returns the graphql-js JSON of the node, such as
+ * `{"kind":"Name","value":"id","loc":{"start":2,"end":4}}`.
+ *
+ * The lists are always written, while the missing nodes are left out. The
+ * value of a BooleanValue is written as a boolean, and a
+ * TypeExtensionDefinition is written as an ObjectTypeExtension. Only the
+ * start and end of the locations are written.
+ */
+func MarshalNode(node INode) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeNodeJSON(&buf, node); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+/**
+ * Returns the node of the graphql-js JSON written by MarshalNode. The legacy
+ * TypeExtensionDefinition kind is read too, and the fields which have no
+ * counterpart in the AST are ignored. The locations have no source.
+ */
+func UnmarshalNode(data []byte) (INode, error) {
+	return readNodeJSON(data)
+}
+
+func (n *Document) MarshalJSON() ([]byte, error) {
+	return MarshalNode(n)
+}
+
+func (n *Document) UnmarshalJSON(data []byte) error {
+	node, err := UnmarshalNode(data)
+	if err != nil {
+		return err
+	}
+	doc, ok := node.(*Document)
+	if !ok {
+		return fmt.Errorf("graphql/json: expected Document but got %v", node.Kind())
+	}
+	*n = *doc
+	return nil
+}
+
+func jsonFieldName(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(first)) + name[size:]
+}
+
+func writeNodeJSON(buf *bytes.Buffer, node INode) error {
+	if extension, ok := node.(*TypeExtensionDefinition); ok {
+		return writeFieldsJSON(buf, OBJECT_TYPE_EXTENSION, extension.Definition, extension.Location)
+	}
+	return writeFieldsJSON(buf, node.Kind(), node, node.Loc())
+}
+
+func writeFieldsJSON(buf *bytes.Buffer, kind NodeKind, node INode, loc *Location) error {
+	buf.WriteString(`{"kind":`)
+	writeStringJSON(buf, string(kind))
+
+	value := reflect.ValueOf(node).Elem()
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous {
+			continue
+		}
+		if kind == OBJECT_TYPE_EXTENSION && field.Name == "Description" {
+			continue
+		}
+		fieldValue := value.Field(i)
+		if (fieldValue.Kind() == reflect.Ptr || fieldValue.Kind() == reflect.Interface) && fieldValue.IsNil() {
+			continue
+		}
+
+		buf.WriteString(",")
+		writeStringJSON(buf, jsonFieldName(field.Name))
+		buf.WriteString(":")
+		switch fieldValue.Kind() {
+		case reflect.Slice:
+			buf.WriteString("[")
+			for j := 0; j < fieldValue.Len(); j++ {
+				if j > 0 {
+					buf.WriteString(",")
+				}
+				element, ok := fieldValue.Index(j).Interface().(INode)
+				if !ok || reflect.ValueOf(element).IsNil() {
+					return fmt.Errorf("graphql/json: unexpected nil in %v.%v", kind, field.Name)
+				}
+				if err := writeNodeJSON(buf, element); err != nil {
+					return err
+				}
+			}
+			buf.WriteString("]")
+
+		case reflect.Ptr, reflect.Interface:
+			if err := writeNodeJSON(buf, fieldValue.Interface().(INode)); err != nil {
+				return err
+			}
+
+		case reflect.String:
+			if kind == BOOLEAN_VALUE {
+				buf.WriteString(strconv.FormatBool(fieldValue.String() == "true"))
+			} else {
+				writeStringJSON(buf, fieldValue.String())
+			}
+
+		case reflect.Bool:
+			buf.WriteString(strconv.FormatBool(fieldValue.Bool()))
+
+		default:
+			return fmt.Errorf("graphql/json: unexpected field %v.%v", kind, field.Name)
+		}
+	}
+
+	if loc != nil {
+		fmt.Fprintf(buf, `,"loc":{"start":%v,"end":%v}`, loc.Start, loc.End)
+	}
+	buf.WriteString("}")
+	return nil
+}
+
+func writeStringJSON(buf *bytes.Buffer, s string) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		panic(err)
+	}
+	buf.Write(data)
+}
+
+type _LocationJSON struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+func readNodeJSON(data []byte) (INode, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("graphql/json: %v", err)
+	}
+	var kind NodeKind
+	if err := json.Unmarshal(object["kind"], &kind); err != nil {
+		return nil, fmt.Errorf("graphql/json: invalid kind: %s", object["kind"])
+	}
+
+	var loc *Location
+	if data, ok := object["loc"]; ok && string(data) != "null" {
+		var locJSON _LocationJSON
+		if err := json.Unmarshal(data, &locJSON); err != nil {
+			return nil, fmt.Errorf("graphql/json: invalid loc of %v: %v", kind, err)
+		}
+		loc = &Location{Start: locJSON.Start, End: locJSON.End}
+	}
+
+	if kind == OBJECT_TYPE_EXTENSION {
+		definition := &ObjectTypeDefinition{}
+		if err := readFieldsJSON(object, kind, definition, loc); err != nil {
+			return nil, err
+		}
+		return &TypeExtensionDefinition{Location: loc, Definition: definition}, nil
+	}
+
+	newNode, ok := nodesByKind[kind]
+	if !ok {
+		return nil, fmt.Errorf("graphql/json: unknown kind %q", kind)
+	}
+	node := newNode()
+	if err := readFieldsJSON(object, kind, node, loc); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func readFieldsJSON(object map[string]json.RawMessage, kind NodeKind, node INode, loc *Location) error {
+	value := reflect.ValueOf(node).Elem()
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldValue := value.Field(i)
+		if field.Anonymous {
+			if loc != nil {
+				fieldValue.Set(reflect.ValueOf(loc))
+			}
+			continue
+		}
+		data, ok := object[jsonFieldName(field.Name)]
+		if !ok || string(data) == "null" {
+			continue
+		}
+
+		switch fieldValue.Kind() {
+		case reflect.Slice:
+			var elements []json.RawMessage
+			if err := json.Unmarshal(data, &elements); err != nil {
+				return fmt.Errorf("graphql/json: invalid %v.%v: %v", kind, field.Name, err)
+			}
+			if len(elements) == 0 {
+				continue
+			}
+			slice := reflect.MakeSlice(field.Type, len(elements), len(elements))
+			for j, element := range elements {
+				child, err := readChildJSON(element, kind, field.Name, field.Type.Elem())
+				if err != nil {
+					return err
+				}
+				slice.Index(j).Set(child)
+			}
+			fieldValue.Set(slice)
+
+		case reflect.Ptr, reflect.Interface:
+			child, err := readChildJSON(data, kind, field.Name, field.Type)
+			if err != nil {
+				return err
+			}
+			fieldValue.Set(child)
+
+		case reflect.String:
+			if kind == BOOLEAN_VALUE {
+				var b bool
+				if err := json.Unmarshal(data, &b); err != nil {
+					return fmt.Errorf("graphql/json: invalid %v.%v: %v", kind, field.Name, err)
+				}
+				fieldValue.SetString(strconv.FormatBool(b))
+				continue
+			}
+			var s string
+			if err := json.Unmarshal(data, &s); err != nil {
+				return fmt.Errorf("graphql/json: invalid %v.%v: %v", kind, field.Name, err)
+			}
+			fieldValue.SetString(s)
+
+		case reflect.Bool:
+			var b bool
+			if err := json.Unmarshal(data, &b); err != nil {
+				return fmt.Errorf("graphql/json: invalid %v.%v: %v", kind, field.Name, err)
+			}
+			fieldValue.SetBool(b)
+		}
+	}
+	return nil
+}
+
+/**
+ * Reads the node of a field, which must be assignable to the field type.
+ */
+func readChildJSON(data []byte, kind NodeKind, fieldName string, typ reflect.Type) (reflect.Value, error) {
+	child, err := readNodeJSON(data)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	value := reflect.ValueOf(child)
+	if !value.Type().AssignableTo(typ) {
+		return reflect.Value{}, fmt.Errorf("graphql/json: unexpected %v in %v.%v", child.Kind(), kind, fieldName)
+	}
+	return value, nil
+}
@@ -0,0 +1,111 @@
+package language
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestJSON_RoundTripsKitchenSinks(T *testing.T) {
+	for _, filename := range []string{"kitchen-sink.graphql", "schema-kitchen-sink.graphql"} {
+		kitchenSink, err := ioutil.ReadFile(filename)
+		if err != nil {
+			panic(err)
+		}
+		tree, err := Parse(NewSource(string(kitchenSink), ""), ParseOptions{NoSource: true})
+		if err != nil {
+			T.Fatal(err)
+		}
+
+		data, err := json.Marshal(tree)
+		if err != nil {
+			T.Fatal(err)
+		}
+		var doc Document
+		if err := json.Unmarshal(data, &doc); err != nil {
+			T.Fatal(err)
+		}
+		remarshaled, err := json.Marshal(&doc)
+		if err != nil {
+			T.Fatal(err)
+		}
+		expect(T, string(remarshaled) == string(data),
+			"Expect JSON of %v to be stable:\n%s\n%s", filename, data, remarshaled)
+		expect(T, Print(&doc) == Print(tree),
+			"Expect %v to be read back:\n%v---\n%v", filename, Print(tree), Print(&doc))
+	}
+}
+
+func TestJSON_MarshalsGraphQLJSShape(T *testing.T) {
+	tree, err := Parse(NewSource(`query Q($a: Int = 1) { f(b: true) }`, ""), ParseOptions{NoLocation: true})
+	if err != nil {
+		T.Fatal(err)
+	}
+	data, err := MarshalNode(tree)
+	if err != nil {
+		T.Fatal(err)
+	}
+	expected := `{"kind":"Document","definitions":[{"kind":"OperationDefinition","operation":"query",` +
+		`"name":{"kind":"Name","value":"Q"},"variableDefinitions":[{"kind":"VariableDefinition",` +
+		`"variable":{"kind":"Variable","name":{"kind":"Name","value":"a"}},` +
+		`"type":{"kind":"NamedType","name":{"kind":"Name","value":"Int"}},` +
+		`"defaultValue":{"kind":"IntValue","value":"1"}}],"directives":[],` +
+		`"selectionSet":{"kind":"SelectionSet","selections":[{"kind":"Field",` +
+		`"name":{"kind":"Name","value":"f"},"arguments":[{"kind":"Argument",` +
+		`"name":{"kind":"Name","value":"b"},"value":{"kind":"BooleanValue","value":true}}],` +
+		`"directives":[]}]}}]}`
+	expect(T, string(data) == expected, "Expect JSON:\n%v\nbut got:\n%v", expected, string(data))
+
+	tree, err = Parse(NewSource(`{ a }`, ""), ParseOptions{NoSource: true})
+	if err != nil {
+		T.Fatal(err)
+	}
+	data, _ = MarshalNode(tree.Definitions[0].(*OperationDefinition).SelectionSet.Selections[0])
+	expected = `{"kind":"Field","name":{"kind":"Name","value":"a","loc":{"start":2,"end":3}},` +
+		`"arguments":[],"directives":[],"loc":{"start":2,"end":3}}`
+	expect(T, string(data) == expected, "Expect JSON:\n%v\nbut got:\n%v", expected, string(data))
+}
+
+func TestJSON_ReadsObjectTypeExtensions(T *testing.T) {
+	node, err := UnmarshalNode([]byte(`{"kind":"ObjectTypeExtension","name":{"kind":"Name","value":"T"},` +
+		`"interfaces":[],"directives":[{"kind":"Directive","name":{"kind":"Name","value":"d"}}],"fields":[]}`))
+	if err != nil {
+		T.Fatal(err)
+	}
+	expect(T, Print(node) == "extend type T @d", "Unexpected print: %v", Print(node))
+
+	data, _ := MarshalNode(node)
+	expect(T, strings.HasPrefix(string(data), `{"kind":"ObjectTypeExtension","name":`),
+		"Expect an ObjectTypeExtension but got: %s", data)
+
+	node, err = UnmarshalNode([]byte(`{"kind":"TypeExtensionDefinition","definition":{"kind":"ObjectTypeDefinition",` +
+		`"name":{"kind":"Name","value":"T"},"fields":[{"kind":"FieldDefinition",` +
+		`"name":{"kind":"Name","value":"f"},"type":{"kind":"NamedType","name":{"kind":"Name","value":"Int"}}}]}}`))
+	if err != nil {
+		T.Fatal(err)
+	}
+	expect(T, Print(node) == "extend type T {\n    f: Int\n}", "Unexpected print: %v", Print(node))
+}
+
+func TestJSON_RejectsInvalidNodes(T *testing.T) {
+	tests := []struct {
+		json, message string
+	}{
+		{`{"kind":"Unknown"}`, `unknown kind "Unknown"`},
+		{`{"value":"a"}`, "invalid kind"},
+		{`{"kind":"Field","name":{"kind":"IntValue","value":"1"}}`, "unexpected IntValue in Field.Name"},
+		{`{"kind":"Document","definitions":[{"kind":"Name","value":"a"}]}`, "unexpected Name in Document.Definitions"},
+		{`{"kind":"BooleanValue","value":"true"}`, "invalid BooleanValue.Value"},
+	}
+	for _, test := range tests {
+		_, err := UnmarshalNode([]byte(test.json))
+		expect(T, err != nil && strings.Contains(err.Error(), test.message),
+			"Expect %v to fail with %q but got: %v", test.json, test.message, err)
+	}
+
+	var doc Document
+	err := json.Unmarshal([]byte(`{"kind":"Name","value":"a"}`), &doc)
+	expect(T, err != nil && strings.Contains(err.Error(), "expected Document but got Name"),
+		"Expect a Document error but got: %v", err)
+}
@@ -29,6 +29,7 @@ const (
 	FLOAT_VALUE   = NodeKind("FloatValue")
 	STRING_VALUE  = NodeKind("StringValue")
 	BOOLEAN_VALUE = NodeKind("BooleanValue")
+	NULL_VALUE    = NodeKind("NullValue")
 	ENUM_VALUE    = NodeKind("EnumValue")
 	LIST_VALUE    = NodeKind("ListValue")
 	OBJECT_VALUE  = NodeKind("ObjectValue")
@@ -56,4 +57,23 @@ const (
 	ENUM_VALUE_DEFINITION        = NodeKind("EnumValueDefinition")
 	INPUT_OBJECT_TYPE_DEFINITION = NodeKind("InputObjectTypeDefinition")
 	TYPE_EXTENSION_DEFINITION    = NodeKind("TypeExtensionDefinition")
+
+	// Type System Definitions
+
+	SCHEMA_DEFINITION         = NodeKind("SchemaDefinition")
+	OPERATION_TYPE_DEFINITION = NodeKind("OperationTypeDefinition")
+	DIRECTIVE_DEFINITION      = NodeKind("DirectiveDefinition")
+	SCHEMA_EXTENSION          = NodeKind("SchemaExtension")
+
+	// Type Extensions
+
+	// OBJECT_TYPE_EXTENSION is the kind of TypeExtensionDefinition in the
+	// graphql-js JSON of the AST.
+	OBJECT_TYPE_EXTENSION = NodeKind("ObjectTypeExtension")
+
+	SCALAR_TYPE_EXTENSION       = NodeKind("ScalarTypeExtension")
+	INTERFACE_TYPE_EXTENSION    = NodeKind("InterfaceTypeExtension")
+	UNION_TYPE_EXTENSION        = NodeKind("UnionTypeExtension")
+	ENUM_TYPE_EXTENSION         = NodeKind("EnumTypeExtension")
+	INPUT_OBJECT_TYPE_EXTENSION = NodeKind("InputObjectTypeExtension")
 )
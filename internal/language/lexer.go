@@ -2,6 +2,7 @@ package language
 
 import (
 	"fmt"
+	"strings"
 	"unicode/utf8"
 )
 
@@ -35,28 +36,36 @@ const (
 	TOKEN_INT
 	TOKEN_FLOAT
 	TOKEN_STRING
+	TOKEN_AMP
+	TOKEN_BLOCK_STRING
+	TOKEN_COMMENT
+	TOKEN_INVALID
 )
 
 var tokenDescription = map[TokenKind]string{
-	TOKEN_EOF:       "EOF",
-	TOKEN_BANG:      "!",
-	TOKEN_DOLLAR:    "$",
-	TOKEN_PAREN_L:   "(",
-	TOKEN_PAREN_R:   ")",
-	TOKEN_SPREAD:    "...",
-	TOKEN_COLON:     ":",
-	TOKEN_EQUALS:    "=",
-	TOKEN_AT:        "@",
-	TOKEN_BRACKET_L: "[",
-	TOKEN_BRACKET_R: "]",
-	TOKEN_BRACE_L:   "{",
-	TOKEN_PIPE:      "|",
-	TOKEN_BRACE_R:   "}",
-	TOKEN_NAME:      "Name",
-	TOKEN_VARIABLE:  "Variable",
-	TOKEN_INT:       "Int",
-	TOKEN_FLOAT:     "Float",
-	TOKEN_STRING:    "String",
+	TOKEN_EOF:          "EOF",
+	TOKEN_BANG:         "!",
+	TOKEN_DOLLAR:       "$",
+	TOKEN_PAREN_L:      "(",
+	TOKEN_PAREN_R:      ")",
+	TOKEN_SPREAD:       "...",
+	TOKEN_COLON:        ":",
+	TOKEN_EQUALS:       "=",
+	TOKEN_AT:           "@",
+	TOKEN_BRACKET_L:    "[",
+	TOKEN_BRACKET_R:    "]",
+	TOKEN_BRACE_L:      "{",
+	TOKEN_PIPE:         "|",
+	TOKEN_BRACE_R:      "}",
+	TOKEN_NAME:         "Name",
+	TOKEN_VARIABLE:     "Variable",
+	TOKEN_INT:          "Int",
+	TOKEN_FLOAT:        "Float",
+	TOKEN_STRING:       "String",
+	TOKEN_AMP:          "&",
+	TOKEN_BLOCK_STRING: "BlockString",
+	TOKEN_COMMENT:      "Comment",
+	TOKEN_INVALID:      "Invalid",
 }
 
 const EOF = -1
@@ -141,13 +150,16 @@ func (l *Lexer) readToken(fromPosition int) (result Token) {
 		return newToken(TOKEN_BANG, l.position, l.position+1, "")
 	case '$':
 		return newToken(TOKEN_DOLLAR, l.position, l.position+1, "")
+	case '&':
+		return newToken(TOKEN_AMP, l.position, l.position+1, "")
 	case '(':
 		return newToken(TOKEN_PAREN_L, l.position, l.position+1, "")
 	case ')':
 		return newToken(TOKEN_PAREN_R, l.position, l.position+1, "")
 	case '.':
-		// TODO(qv)
-		return
+		if strings.HasPrefix(l.body[l.position:], "...") {
+			return newToken(TOKEN_SPREAD, l.position, l.position+3, "")
+		}
 	case ':':
 		return newToken(TOKEN_COLON, l.position, l.position+1, "")
 	case '=':
@@ -165,6 +177,9 @@ func (l *Lexer) readToken(fromPosition int) (result Token) {
 	case '}':
 		return newToken(TOKEN_BRACE_R, l.position, l.position+1, "")
 	case '"':
+		if strings.HasPrefix(l.body[l.position:], `"""`) {
+			return l.readBlockString()
+		}
 		return l.readString()
 	}
 
@@ -179,15 +194,42 @@ func (l *Lexer) readToken(fromPosition int) (result Token) {
 		fmt.Sprintf(`Unexpected character "%c".`, ch)))
 }
 
+/**
+ * Returns the position after the invalid token read from fromPosition, whose
+ * error was found at errorPosition. The rest of an invalid string is skipped
+ * up to its closing quote or the end of its line.
+ */
+func (l *Lexer) recoverPosition(fromPosition, errorPosition int) int {
+	l.resetPosition(fromPosition)
+	l.skipWhitespace()
+	if l.char == '"' && !strings.HasPrefix(l.body[l.position:], `"""`) {
+		for i := errorPosition; i < len(l.body); i++ {
+			switch l.body[i] {
+			case '"':
+				return i + 1
+			case '\n', '\r':
+				return i
+			}
+		}
+		return len(l.body)
+	}
+	if errorPosition >= len(l.body) {
+		return len(l.body)
+	}
+	_, size := utf8.DecodeRuneInString(l.body[errorPosition:])
+	return errorPosition + size
+}
+
 func (l *Lexer) next() rune {
 	if l.nextPosition >= len(l.body) {
 		l.char = EOF
+		l.position = len(l.body)
 		return EOF
 	}
 
 	ch, size := utf8.DecodeRuneInString(l.body[l.nextPosition:])
 	if ch == utf8.RuneError {
-		panic("illegal character")
+		panic(SyntaxError(l.source, l.nextPosition, "Invalid character encoding."))
 	}
 
 	l.char = ch
@@ -208,23 +250,13 @@ func (l *Lexer) resetPosition(position int) {
  */
 func (l *Lexer) skipWhitespace() {
 	for {
-		switch l.char {
-		case ' ', ',', 9, 10, 11, 12, 13, 0xa0, 0x2028, 0x2029:
+		switch {
+		case isWhitespace(l.char):
 			l.next()
 
-		case '#':
+		case l.char == '#':
 			// skip comments
-			l.next()
-
-		SKIP_COMMENT:
-			for {
-				switch l.char {
-				case EOF, 10, 13, 0x2028, 0x2029:
-					break SKIP_COMMENT
-				default:
-					l.next()
-				}
-			}
+			l.readComment()
 
 		default:
 			return
@@ -232,6 +264,47 @@ func (l *Lexer) skipWhitespace() {
 	}
 }
 
+func isWhitespace(ch rune) bool {
+	switch ch {
+	case ' ', ',', 9, 10, 11, 12, 13, 0xa0, 0x2028, 0x2029:
+		return true
+	}
+	return false
+}
+
+/**
+ * Reads a comment from the # at the current position to the end of its
+ * line. Its value is the text after the #, without the trailing whitespace.
+ */
+func (l *Lexer) readComment() Token {
+	start := l.position
+	l.next()
+	for {
+		switch l.char {
+		case EOF, 10, 13, 0x2028, 0x2029:
+			value := strings.TrimRight(l.body[start+1:l.position], " \t")
+			return newToken(TOKEN_COMMENT, start, l.position, value)
+		default:
+			l.next()
+		}
+	}
+}
+
+/**
+ * Gets the next comment or token from the source starting at the given
+ * position.
+ */
+func (l *Lexer) readTokenOrComment(fromPosition int) Token {
+	l.resetPosition(fromPosition)
+	for isWhitespace(l.char) {
+		l.next()
+	}
+	if l.char == '#' {
+		return l.readComment()
+	}
+	return l.readToken(l.position)
+}
+
 /**
  * Reads a number token from the source file, either a float
  * or an int depending on whether a decimal point appears.
@@ -350,7 +423,6 @@ func (l *Lexer) readString() Token {
 	}
 
 	if l.char != '"' {
-		LOG.Printf("STRING %c %d", l.char, l.char)
 		panic(SyntaxError(l.source, l.position, "Unterminated string."))
 	}
 
@@ -358,6 +430,38 @@ func (l *Lexer) readString() Token {
 	return newToken(TOKEN_STRING, start, l.position+1, value)
 }
 
+/**
+ * Reads a block string token from the source file. Its value is the raw
+ * content with only \""" escaped, processed by blockStringValue.
+ *
+ * """("?"?(\\"""|\\(?!""")|[^"\\]))*"""
+ */
+func (l *Lexer) readBlockString() Token {
+	start := l.position
+	raw := ""
+
+	l.next()
+	l.next()
+	l.next()
+	chunkStart := l.position
+	for l.char != EOF {
+		if l.char == '"' && strings.HasPrefix(l.body[l.position:], `"""`) {
+			raw += l.source.Body[chunkStart:l.position]
+			return newToken(TOKEN_BLOCK_STRING, start, l.position+3, blockStringValue(raw))
+		}
+		if l.char == '\\' && strings.HasPrefix(l.body[l.position:], `\"""`) {
+			raw += l.source.Body[chunkStart:l.position] + `"""`
+			l.next()
+			l.next()
+			l.next()
+			chunkStart = l.position + 1
+		}
+		l.next()
+	}
+
+	panic(SyntaxError(l.source, l.position, "Unterminated string."))
+}
+
 /**
  * Converts four hexidecimal chars to the integer that the
  * string represents. For example, uniCharCode('0','0','0','f')
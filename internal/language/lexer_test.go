@@ -60,6 +60,51 @@ func TestLex_Strings(T *testing.T) {
 		newToken(TOKEN_STRING, 0, 34, "unicode \u1234\u5678\u90AB\uCDEF"))
 }
 
+func TestLex_BlockStrings(T *testing.T) {
+	deepEqual(T, lexOne(`"""simple"""`),
+		newToken(TOKEN_BLOCK_STRING, 0, 12, "simple"))
+
+	deepEqual(T, lexOne(`""" white space """`),
+		newToken(TOKEN_BLOCK_STRING, 0, 19, " white space "))
+
+	deepEqual(T, lexOne(`"""contains " quote"""`),
+		newToken(TOKEN_BLOCK_STRING, 0, 22, `contains " quote`))
+
+	deepEqual(T, lexOne(`"""contains \""" triplequote"""`),
+		newToken(TOKEN_BLOCK_STRING, 0, 31, `contains """ triplequote`))
+
+	deepEqual(T, lexOne("\"\"\"multi\nline\"\"\""),
+		newToken(TOKEN_BLOCK_STRING, 0, 16, "multi\nline"))
+
+	deepEqual(T, lexOne("\"\"\"multi\rline\r\nnormalized\"\"\""),
+		newToken(TOKEN_BLOCK_STRING, 0, 28, "multi\nline\nnormalized"))
+
+	deepEqual(T, lexOne(`"""unescaped \n\r\b\t\f\u1234"""`),
+		newToken(TOKEN_BLOCK_STRING, 0, 32, `unescaped \n\r\b\t\f\u1234`))
+
+	deepEqual(T, lexOne(`"""slashes \\ \/"""`),
+		newToken(TOKEN_BLOCK_STRING, 0, 19, `slashes \\ \/`))
+
+	deepEqual(T, lexOne(`"""
+
+        spans
+          multiple
+            lines
+
+        """`),
+		newToken(TOKEN_BLOCK_STRING, 0, 68, "spans\n  multiple\n    lines"))
+}
+
+func TestLex_ReportBlockStringErrors(T *testing.T) {
+	expectPanic(T, func() {
+		lexOne(`"""no end quote`)
+	}, "Syntax Error GraphQL (1:16) Unterminated string")
+
+	expectPanic(T, func() {
+		lexOne(`"""contains \""" only"`)
+	}, "Syntax Error GraphQL (1:23) Unterminated string")
+}
+
 func TestLex_ReportStringErrors(T *testing.T) {
 	expectPanic(T, func() {
 		lexOne(`"no end quote`)
@@ -266,3 +311,70 @@ func TestLex_ReportDashesInNames(T *testing.T) {
 		lexer.nextToken()
 	}, `Syntax Error GraphQL (1:3) Invalid number, expected digit but got: "b".`)
 }
+
+func sourceToken(kind TokenKind, start, end int, value string, line, column int) SourceToken {
+	return SourceToken{Token: newToken(kind, start, end, value), Line: line, Column: column}
+}
+
+func TestTokenizer_ReadsCommentsAndPunctuation(T *testing.T) {
+	tokens, errs := Tokenize(NewSource("# query\r\nquery Q {\n  a(b: \"é\") # a field  \n  ...F\n}", ""))
+	deepEqual(T, errs, []error(nil))
+	deepEqual(T, tokens, []SourceToken{
+		sourceToken(TOKEN_COMMENT, 0, 7, " query", 1, 1),
+		sourceToken(TOKEN_NAME, 9, 14, "query", 2, 1),
+		sourceToken(TOKEN_NAME, 15, 16, "Q", 2, 7),
+		sourceToken(TOKEN_BRACE_L, 17, 18, "", 2, 9),
+		sourceToken(TOKEN_NAME, 21, 22, "a", 3, 3),
+		sourceToken(TOKEN_PAREN_L, 22, 23, "", 3, 4),
+		sourceToken(TOKEN_NAME, 23, 24, "b", 3, 5),
+		sourceToken(TOKEN_COLON, 24, 25, "", 3, 6),
+		sourceToken(TOKEN_STRING, 26, 30, "é", 3, 8),
+		sourceToken(TOKEN_PAREN_R, 30, 31, "", 3, 11),
+		sourceToken(TOKEN_COMMENT, 32, 43, " a field", 3, 13),
+		sourceToken(TOKEN_SPREAD, 46, 49, "", 4, 3),
+		sourceToken(TOKEN_NAME, 49, 50, "F", 4, 6),
+		sourceToken(TOKEN_BRACE_R, 51, 52, "", 5, 1),
+	})
+}
+
+func TestTokenizer_CountsLinesOfBlockStrings(T *testing.T) {
+	tokens, _ := Tokenize(NewSource("\"\"\"\na\nb\"\"\" c", ""))
+	deepEqual(T, tokens[1], sourceToken(TOKEN_NAME, 11, 12, "c", 3, 6))
+}
+
+func TestTokenizer_ContinuesAfterInvalidTokens(T *testing.T) {
+	tokens, errs := Tokenize(NewSource("a ? \"b\\x\" c\n\"unterminated\nd \xff", ""))
+	deepEqual(T, tokens, []SourceToken{
+		sourceToken(TOKEN_NAME, 0, 1, "a", 1, 1),
+		sourceToken(TOKEN_INVALID, 2, 3, "?", 1, 3),
+		sourceToken(TOKEN_INVALID, 4, 9, "\"b\\x\"", 1, 5),
+		sourceToken(TOKEN_NAME, 10, 11, "c", 1, 11),
+		sourceToken(TOKEN_INVALID, 12, 25, "\"unterminated", 2, 1),
+		sourceToken(TOKEN_NAME, 26, 27, "d", 3, 1),
+		sourceToken(TOKEN_INVALID, 28, 29, "\xff", 3, 3),
+	})
+	expect(T, len(errs) == 4, "Expect 4 errors but got %v", errs)
+	for _, err := range errs {
+		_, ok := err.(QLError)
+		expect(T, ok, "Expect a syntax error but got %v", err)
+	}
+}
+
+func TestTokenizer_ReadsPartialInput(T *testing.T) {
+	tokenizer := NewTokenizer(NewSource(`{ user(id: "1`, ""))
+	var kinds []TokenKind
+	for {
+		token, _ := tokenizer.Next()
+		kinds = append(kinds, token.Kind)
+		if token.Kind == TOKEN_EOF {
+			break
+		}
+	}
+	deepEqual(T, kinds, []TokenKind{
+		TOKEN_BRACE_L, TOKEN_NAME, TOKEN_PAREN_L, TOKEN_NAME, TOKEN_COLON, TOKEN_INVALID, TOKEN_EOF,
+	})
+
+	token, err := tokenizer.Next()
+	expect(T, token.Kind == TOKEN_EOF && token.Start == 13 && err == nil,
+		"Expect EOF to be read again but got %v %v", token, err)
+}
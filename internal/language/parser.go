@@ -3,11 +3,24 @@ package language
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 type ParseOptions struct {
 	NoLocation bool
 	NoSource   bool
+
+	// MaxTokens is the maximum number of tokens in the source, not counting
+	// the end of file. Zero means no limit.
+	MaxTokens int
+
+	// MaxDepth is the maximum nesting of selection sets, list and object
+	// values, and list types. Zero means no limit.
+	MaxDepth int
+
+	// Comments keeps the comments on the locations of the nodes, so that they
+	// can be printed with PrintOptions.Comments. Requires the locations.
+	Comments bool
 }
 
 func Parse(source Source, options ...ParseOptions) (result *Document, err error) {
@@ -31,6 +44,55 @@ func Parse(source Source, options ...ParseOptions) (result *Document, err error)
 	return parser.parseDocument(), nil
 }
 
+/**
+ * Parses the source like Parse, but does not stop at the first syntax error:
+ *
+ *   - an invalid character or string is reported and skipped,
+ *   - a selection which cannot be parsed is reported and left out of its
+ *     selection set, and parsing resumes at the next selection,
+ *   - a definition which cannot be parsed is reported and left out of the
+ *     document, and parsing resumes at the next definition,
+ *   - a definition starting while a brace opened before it is never closed
+ *     ends the selection sets left open, which is reported, and is parsed
+ *     as the next definition.
+ *
+ * The selection sets left without any selection are left out, along with
+ * the definitions and inline fragments they belong to, so that the partial
+ * document is still valid GraphQL.
+ *
+ * Returns the partial document along with the syntax errors, in the order of
+ * the source. The document is nil when a limit of the options is exceeded.
+ */
+func ParseWithRecovery(source Source, options ...ParseOptions) (result *Document, errs []error) {
+	var opts ParseOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	parser := &Parser{
+		lexer:      newLexer(source),
+		source:     source,
+		options:    opts,
+		recovering: true,
+	}
+	defer func() {
+		e := recover()
+		if e != nil {
+			result = nil
+			if e, ok := e.(error); ok {
+				errs = append(parser.errors, e)
+			} else {
+				errs = append(parser.errors, errors.New(fmt.Sprint("graphql/parser: ", e)))
+			}
+		}
+	}()
+
+	parser.definitionStarts = parser.findDefinitionStarts()
+	parser.token = parser.readToken(0, true)
+	parser.countToken()
+	parser.collectComments(0)
+	return parser.parseDocument(), parser.errors
+}
+
 func ParseValue(source Source, options ...ParseOptions) (result IValue, err error) {
 	defer func() {
 		e := recover()
@@ -52,12 +114,70 @@ func ParseValue(source Source, options ...ParseOptions) (result IValue, err erro
 	return parser.parseValueLiteral(false), nil
 }
 
+/**
+ * Parses the source as a type reference, such as `[String!]!`. The whole
+ * source must be the type.
+ */
+func ParseType(source Source, options ...ParseOptions) (result IType, err error) {
+	defer func() {
+		e := recover()
+		if e != nil {
+			result = nil
+			if e, ok := e.(error); ok {
+				err = e
+			} else {
+				err = errors.New(fmt.Sprint("graphql/parser: ", e))
+			}
+		}
+	}()
+
+	var opts ParseOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	parser := newParser(source, opts)
+	typ := parser.parseType()
+	parser.expect(TOKEN_EOF)
+	return typ, nil
+}
+
 type Parser struct {
 	lexer   *Lexer
 	token   Token
 	source  Source
 	options ParseOptions
 	prevEnd int
+
+	tokenCount int
+	depth      int
+
+	// The state of ParseWithRecovery. The brackets are the kinds of the
+	// opening brackets which are not closed yet.
+	recovering   bool
+	errors       []error
+	brackets     []TokenKind
+	aborted      bool
+	stoppedAtEOF bool
+
+	// The positions of the tokens which start a definition inside a brace
+	// which is never closed, and the position of the last one where the
+	// selection sets left open were reported.
+	definitionStarts map[int]bool
+	unclosedAt       int
+
+	// The comments read but not attached yet, and the comments before the
+	// closing brace of the last block.
+	comments      []_Comment
+	blockComments []string
+}
+
+type _Comment struct {
+	text string
+
+	// Whether the comment follows the token ending at the after position, on
+	// the same line.
+	trailing bool
+	after    int
 }
 
 /**
@@ -66,13 +186,16 @@ type Parser struct {
  */
 func newParser(source Source, options ParseOptions) *Parser {
 	lexer := newLexer(source)
-	return &Parser{
+	p := &Parser{
 		lexer:   lexer,
 		token:   lexer.nextToken(),
 		source:  source,
 		options: options,
 		prevEnd: 0,
 	}
+	p.countToken()
+	p.collectComments(0)
+	return p
 }
 
 /**
@@ -102,9 +225,358 @@ func (p *Parser) loc(start int) *Location {
  * Moves the internal parser object to the next lexed token.
  */
 func (p *Parser) advance() {
+	if p.recovering {
+		p.trackBracket(p.token)
+	}
 	prevEnd := p.token.End
 	p.prevEnd = prevEnd
-	p.token = p.lexer.nextTokenFromPosition(prevEnd)
+	p.token = p.readToken(prevEnd, true)
+	p.countToken()
+	p.collectComments(prevEnd)
+}
+
+/**
+ * Reads the comments between the given position, the end of the previous
+ * token, and the current token.
+ */
+func (p *Parser) collectComments(from int) {
+	if !p.options.Comments || p.options.NoLocation {
+		return
+	}
+	body := p.source.Body
+	sameLine := from > 0
+	for i := from; i < p.token.Start; i++ {
+		switch body[i] {
+		case '\n', '\r':
+			sameLine = false
+		case '#':
+			end := i
+			for end < len(body) && body[end] != '\n' && body[end] != '\r' {
+				end++
+			}
+			p.comments = append(p.comments, _Comment{
+				text:     strings.TrimRight(body[i+1:end], " \t"),
+				trailing: sameLine,
+				after:    from,
+			})
+			sameLine = false
+			i = end - 1
+		}
+	}
+}
+
+/**
+ * Returns the texts of the comments not attached yet.
+ */
+func (p *Parser) takeComments() []string {
+	if len(p.comments) == 0 {
+		return nil
+	}
+	texts := make([]string, len(p.comments))
+	for i, comment := range p.comments {
+		texts[i] = comment.text
+	}
+	p.comments = p.comments[:0]
+	return texts
+}
+
+/**
+ * Returns the location of a node starting at start, with the given comments
+ * before it, the comment after its last token, and the comments of its
+ * block, if any.
+ */
+func (p *Parser) commentedLoc(start int, before []string) *Location {
+	loc := p.loc(start)
+	if loc == nil || !p.options.Comments {
+		return loc
+	}
+
+	comments := &Comments{Before: before, Inner: p.blockComments}
+	p.blockComments = nil
+	if len(p.comments) > 0 && p.comments[0].trailing && p.comments[0].after == p.prevEnd {
+		comments.After = p.comments[0].text
+		p.comments = p.comments[1:]
+	}
+	if len(comments.Before) > 0 || comments.After != "" || len(comments.Inner) > 0 {
+		loc.Comments = comments
+	}
+	return loc
+}
+
+/**
+ * Reads the token at the given position. When recovering, the invalid tokens
+ * are skipped, and reported if report is set.
+ */
+func (p *Parser) readToken(position int, report bool) (token Token) {
+	if !p.recovering {
+		return p.lexer.nextTokenFromPosition(position)
+	}
+	for {
+		err, ok := p.tryReadToken(position, &token)
+		if ok {
+			return token
+		}
+		if report {
+			p.errors = append(p.errors, err)
+		}
+		position = p.lexer.recoverPosition(position, err.Positions[0])
+	}
+}
+
+func (p *Parser) tryReadToken(position int, token *Token) (err QLError, ok bool) {
+	defer func() {
+		if e := recover(); e != nil {
+			if e, isQLError := e.(QLError); isQLError && len(e.Positions) > 0 {
+				err = e
+				return
+			}
+			panic(e)
+		}
+	}()
+	*token = p.lexer.nextTokenFromPosition(position)
+	return QLError{}, true
+}
+
+func (p *Parser) trackBracket(token Token) {
+	switch token.Kind {
+	case TOKEN_BRACE_L, TOKEN_PAREN_L, TOKEN_BRACKET_L:
+		p.brackets = append(p.brackets, token.Kind)
+	case TOKEN_BRACE_R:
+		if i := p.innermostBrace(); i >= 0 {
+			p.brackets = p.brackets[:i]
+		}
+	case TOKEN_PAREN_R:
+		p.popBracket(TOKEN_PAREN_L)
+	case TOKEN_BRACKET_R:
+		p.popBracket(TOKEN_BRACKET_L)
+	}
+}
+
+func (p *Parser) popBracket(kind TokenKind) {
+	if n := len(p.brackets); n > 0 && p.brackets[n-1] == kind {
+		p.brackets = p.brackets[:n-1]
+	}
+}
+
+func (p *Parser) innermostBrace() int {
+	for i := len(p.brackets) - 1; i >= 0; i-- {
+		if p.brackets[i] == TOKEN_BRACE_L {
+			return i
+		}
+	}
+	return -1
+}
+
+/**
+ * Calls parseFn. When recovering, a syntax error raised by parseFn is
+ * recorded, then the tokens are skipped up to the end of the source or up to
+ * the first one accepted by resume, which is given the number of brackets
+ * open before parseFn. Returns whether parseFn succeeded.
+ */
+func (p *Parser) recoverable(parseFn func(), resume func(open int) bool) (ok bool) {
+	if !p.recovering {
+		parseFn()
+		return true
+	}
+
+	start, depth, open := p.token.Start, p.depth, len(p.brackets)
+	defer func() {
+		e := recover()
+		if e == nil {
+			return
+		}
+		if err, isError := e.(error); !isError || p.aborted {
+			panic(e)
+		} else {
+			p.errors = append(p.errors, err)
+		}
+
+		p.depth = depth
+		if p.token.Start == start && !p.peek(TOKEN_EOF) {
+			p.advance()
+		}
+		for !p.peek(TOKEN_EOF) && !resume(open) {
+			p.advance()
+		}
+		p.stoppedAtEOF = p.peek(TOKEN_EOF)
+		ok = false
+	}()
+	parseFn()
+	return true
+}
+
+/**
+ * Finds the tokens which start a definition after the first brace which is
+ * never closed. As the braces do not match, the selection sets which are left
+ * open are taken to end before them.
+ */
+func (p *Parser) findDefinitionStarts() map[int]bool {
+	var tokens []Token
+	var braces []int
+	for position := 0; ; {
+		token := p.readToken(position, false)
+		switch token.Kind {
+		case TOKEN_BRACE_L:
+			braces = append(braces, len(tokens))
+		case TOKEN_BRACE_R:
+			if len(braces) > 0 {
+				braces = braces[:len(braces)-1]
+			}
+		}
+		tokens = append(tokens, token)
+		// The parsing stops at the limit anyway.
+		if token.Kind == TOKEN_EOF || p.options.MaxTokens > 0 && len(tokens) > p.options.MaxTokens {
+			break
+		}
+		position = token.End
+	}
+	if len(braces) == 0 {
+		return nil
+	}
+
+	result := make(map[int]bool)
+	for i := braces[0] + 1; i < len(tokens); i++ {
+		if p.startsDefinition(tokens[i:]) {
+			result[tokens[i].Start] = true
+		}
+	}
+	return result
+}
+
+/**
+ * Whether the tokens start a definition rather than selections. The keywords
+ * which are also common field names must be followed by a name, except at the
+ * start of a line.
+ */
+func (p *Parser) startsDefinition(tokens []Token) bool {
+	next := func(i int) Token {
+		if i < len(tokens) {
+			return tokens[i]
+		}
+		return Token{Kind: TOKEN_EOF}
+	}
+	isName := func(token Token, values ...string) bool {
+		if token.Kind != TOKEN_NAME {
+			return false
+		}
+		for _, value := range values {
+			if token.Value == value {
+				return true
+			}
+		}
+		return len(values) == 0
+	}
+	isKind := func(token Token, kinds ...TokenKind) bool {
+		for _, kind := range kinds {
+			if token.Kind == kind {
+				return true
+			}
+		}
+		return false
+	}
+
+	start := tokens[0].Start
+	atLineStart := start == 0 || p.source.Body[start-1] == '\n' || p.source.Body[start-1] == '\r'
+	if !isName(tokens[0]) {
+		return false
+	}
+	switch tokens[0].Value {
+	case "fragment":
+		return isName(next(1)) && next(1).Value != "on" && isName(next(2), "on")
+	case "query", "mutation", "subscription":
+		return isName(next(1)) && isKind(next(2), TOKEN_PAREN_L, TOKEN_AT, TOKEN_BRACE_L)
+	case "type", "interface", "input":
+		return isName(next(1)) && (isKind(next(2), TOKEN_BRACE_L, TOKEN_AT) || isName(next(2), "implements"))
+	case "enum":
+		return isName(next(1)) && isKind(next(2), TOKEN_BRACE_L, TOKEN_AT)
+	case "union":
+		return isName(next(1)) && isKind(next(2), TOKEN_EQUALS, TOKEN_AT)
+	case "scalar":
+		return atLineStart && isName(next(1))
+	case "schema":
+		return atLineStart && isKind(next(1), TOKEN_BRACE_L, TOKEN_AT)
+	case "directive":
+		return isKind(next(1), TOKEN_AT)
+	case "extend":
+		return isName(next(1), "schema", "type", "interface", "union", "scalar", "enum", "input")
+	}
+	return false
+}
+
+/**
+ * Whether a selection of the selection set with the given brackets open
+ * starts at the current token, or the selection set ends.
+ */
+func (p *Parser) atSelection(open int) bool {
+	if p.definitionStarts[p.token.Start] {
+		return true
+	}
+	switch p.token.Kind {
+	case TOKEN_NAME, TOKEN_SPREAD:
+		return len(p.brackets) == open
+	case TOKEN_BRACE_R:
+		return p.innermostBrace() == open-1
+	}
+	return false
+}
+
+/**
+ * Whether a definition starts at the current token. It must not be enclosed
+ * by brackets, unless it starts with a keyword at the start of a line, so
+ * that a missing closing bracket does not hide the rest of the document. A
+ * selection set must be at the start of a line, as it would otherwise be the
+ * end of the invalid definition.
+ */
+func (p *Parser) atDefinition(open int) bool {
+	start := p.token.Start
+	atLineStart := start == 0 || p.source.Body[start-1] == '\n' || p.source.Body[start-1] == '\r'
+	atDefinition := false
+	switch p.token.Kind {
+	case TOKEN_BRACE_L:
+		atDefinition = len(p.brackets) == 0 && atLineStart
+	case TOKEN_STRING, TOKEN_BLOCK_STRING:
+		atDefinition = len(p.brackets) == 0
+	case TOKEN_NAME:
+		switch p.token.Value {
+		case "query", "mutation", "subscription", "fragment",
+			"schema", "directive", "type", "interface", "union", "scalar", "enum", "input", "extend":
+			atDefinition = len(p.brackets) == 0 || atLineStart
+		}
+	}
+	if atDefinition || p.definitionStarts[start] {
+		p.brackets = p.brackets[:0]
+		return true
+	}
+	return false
+}
+
+func (p *Parser) countToken() {
+	if p.options.MaxTokens <= 0 || p.token.Kind == TOKEN_EOF {
+		return
+	}
+	p.tokenCount++
+	if p.tokenCount > p.options.MaxTokens {
+		p.aborted = true
+		panic(SyntaxError(p.source, p.token.Start,
+			fmt.Sprintf("Document contains more than %v tokens", p.options.MaxTokens)))
+	}
+}
+
+/**
+ * Must be called before parsing a nested construct, and paired with a call to
+ * leave once it is parsed.
+ */
+func (p *Parser) enter() {
+	p.depth++
+	if p.options.MaxDepth > 0 && p.depth > p.options.MaxDepth {
+		p.aborted = true
+		panic(SyntaxError(p.source, p.token.Start,
+			fmt.Sprintf("Document exceeds the maximum nesting depth of %v", p.options.MaxDepth)))
+	}
+}
+
+func (p *Parser) leave() {
+	p.depth--
 }
 
 /**
@@ -157,6 +629,16 @@ func (p *Parser) expectKeyword(value string) Token {
 		fmt.Sprintf("Expected %v, found %v", value, token)))
 }
 
+/**
+ * Returns the token after the current one, without advancing the parser.
+ */
+func (p *Parser) lookahead() Token {
+	if p.recovering {
+		return p.readToken(p.token.End, false)
+	}
+	return p.lexer.readToken(p.token.End)
+}
+
 /**
  * Helper function for creating an error when an unexpected lexed token
  * is encountered.
@@ -198,6 +680,23 @@ func (p *Parser) many(openKind, closeKind TokenKind, parseFn func()) {
 	}
 }
 
+/**
+ * Parses the block of a type system definition, like any or many according
+ * to nonEmpty. The comments before its closing brace are kept for the
+ * definition.
+ */
+func (p *Parser) block(nonEmpty bool, parseFn func()) {
+	p.expect(TOKEN_BRACE_L)
+	if nonEmpty {
+		parseFn()
+	}
+	for !p.peek(TOKEN_BRACE_R) {
+		parseFn()
+	}
+	p.blockComments = p.takeComments()
+	p.advance()
+}
+
 /**
  * Converts a name lex token into a name parse node.
  */
@@ -217,14 +716,32 @@ func (p *Parser) parseName() *Name {
 func (p *Parser) parseDocument() *Document {
 	start := p.token.Start
 	definitions := make([]IDefinition, 4)[:0]
-	definitions = append(definitions, p.parseDefinition())
+	parseFn := func() {
+		definition := p.parseDefinition()
+		switch definition := definition.(type) {
+		case *OperationDefinition:
+			if definition.SelectionSet == nil {
+				return
+			}
+		case *FragmentDefinition:
+			if definition.SelectionSet == nil {
+				return
+			}
+		}
+		definitions = append(definitions, definition)
+	}
+	p.recoverable(parseFn, p.atDefinition)
 	for !p.skip(TOKEN_EOF) {
-		definitions = append(definitions, p.parseDefinition())
+		p.recoverable(parseFn, p.atDefinition)
 	}
 
+	loc := p.loc(start)
+	if inner := p.takeComments(); loc != nil && len(inner) > 0 {
+		loc.Comments = &Comments{Inner: inner}
+	}
 	return &Document{
 		Definitions: definitions,
-		Location:    p.loc(start),
+		Location:    loc,
 	}
 }
 
@@ -232,21 +749,25 @@ func (p *Parser) parseDocument() *Document {
  * IDefinition :
  *   - OperationDefinition
  *   - FragmentDefinition
- *   - TypeDefinition
+ *   - TypeSystemDefinition
  */
 func (p *Parser) parseDefinition() IDefinition {
 	if p.peek(TOKEN_BRACE_L) {
 		return p.parseOperationDefinition()
 	}
 
+	if p.peekDescription() {
+		return p.parseTypeSystemDefinition()
+	}
+
 	if p.peek(TOKEN_NAME) {
 		switch p.token.Value {
 		case "query", "mutation", "subscription":
-			p.parseOperationDefinition()
+			return p.parseOperationDefinition()
 		case "fragment":
 			return p.parseFragmentDefinition()
-		case "type", "interface", "union", "scalar", "enum", "input", "extend":
-			return p.parseTypeDefinition()
+		case "schema", "directive", "type", "interface", "union", "scalar", "enum", "input", "extend":
+			return p.parseTypeSystemDefinition()
 		}
 	}
 
@@ -264,6 +785,7 @@ func (p *Parser) parseDefinition() IDefinition {
  */
 func (p *Parser) parseOperationDefinition() *OperationDefinition {
 	start := p.token.Start
+	comments := p.takeComments()
 	if p.peek(TOKEN_BRACE_L) {
 		return &OperationDefinition{
 			Operation:           OperationQuery,
@@ -271,7 +793,7 @@ func (p *Parser) parseOperationDefinition() *OperationDefinition {
 			VariableDefinitions: nil,
 			Directives:          nil,
 			SelectionSet:        p.parseSelectionSet(),
-			Location:            p.loc(start),
+			Location:            p.commentedLoc(start, comments),
 		}
 	}
 
@@ -284,7 +806,7 @@ func (p *Parser) parseOperationDefinition() *OperationDefinition {
 		VariableDefinitions: p.parseVariableDefinitions(),
 		Directives:          p.parseDirectives(),
 		SelectionSet:        p.parseSelectionSet(),
-		Location:            p.loc(start),
+		Location:            p.commentedLoc(start, comments),
 	}
 }
 
@@ -309,6 +831,7 @@ func (p *Parser) parseVariableDefinitions() []*VariableDefinition {
 func (p *Parser) parseVariableDefinition() *VariableDefinition {
 	start := p.token.Start
 
+	comments := p.takeComments()
 	result := &VariableDefinition{}
 	result.Variable = p.parseVariable()
 	p.expect(TOKEN_COLON)
@@ -316,7 +839,7 @@ func (p *Parser) parseVariableDefinition() *VariableDefinition {
 	if p.skip(TOKEN_EQUALS) {
 		result.DefaultValue = p.parseValueLiteral(true)
 	}
-	result.Location = p.loc(start)
+	result.Location = p.commentedLoc(start, comments)
 	return result
 }
 
@@ -334,19 +857,77 @@ func (p *Parser) parseVariable() *Variable {
 
 /**
  * SelectionSet : { ISelection+ }
+ *
+ * When recovering, the selection set may end before its closing brace, and
+ * is nil when it has no selection.
  */
 func (p *Parser) parseSelectionSet() *SelectionSet {
 	start := p.token.Start
+	p.enter()
+	defer p.leave()
 	selections := make([]ISelection, 4)[:0]
-	p.many(TOKEN_BRACE_L, TOKEN_BRACE_R, func() {
-		selections = append(selections, p.parseSelection())
-	})
+	p.expect(TOKEN_BRACE_L)
+	for {
+		// A brace opened before is never closed, and a definition starts here.
+		if p.definitionStarts[p.token.Start] {
+			p.leaveUnclosedSelectionSet()
+			return p.partialSelectionSet(selections, start)
+		}
+
+		p.recoverable(func() {
+			if selection := p.parseSelection(); selection != nil {
+				selections = append(selections, selection)
+			}
+		}, p.atSelection)
+
+		if p.peek(TOKEN_BRACE_R) {
+			break
+		}
+		// The source ended in the selection set and this was reported.
+		if p.stoppedAtEOF && p.peek(TOKEN_EOF) {
+			return p.partialSelectionSet(selections, start)
+		}
+	}
+	inner := p.takeComments()
+	p.advance()
+	if len(selections) == 0 {
+		return nil
+	}
+	loc := p.loc(start)
+	if loc != nil && len(inner) > 0 {
+		loc.Comments = &Comments{Inner: inner}
+	}
+	return &SelectionSet{
+		Selections: selections,
+		Location:   loc,
+	}
+}
+
+func (p *Parser) partialSelectionSet(selections []ISelection, start int) *SelectionSet {
+	if len(selections) == 0 {
+		return nil
+	}
 	return &SelectionSet{
 		Selections: selections,
 		Location:   p.loc(start),
 	}
 }
 
+/**
+ * Reports the missing closing brace once for all the selection sets left
+ * open before the current token, and forgets the brace of the current one.
+ */
+func (p *Parser) leaveUnclosedSelectionSet() {
+	if p.unclosedAt != p.token.Start {
+		p.unclosedAt = p.token.Start
+		p.errors = append(p.errors, SyntaxError(p.source, p.token.Start,
+			fmt.Sprintf("Expected %v, found %v", TOKEN_BRACE_R, p.token)))
+	}
+	if i := p.innermostBrace(); i >= 0 {
+		p.brackets = p.brackets[:i]
+	}
+}
+
 /**
  * ISelection :
  *   - Field
@@ -368,6 +949,7 @@ func (p *Parser) parseSelection() ISelection {
  */
 func (p *Parser) parseField() *Field {
 	start := p.token.Start
+	comments := p.takeComments()
 	nameOrAlias := p.parseName()
 	var alias *Name
 	var name *Name
@@ -387,7 +969,7 @@ func (p *Parser) parseField() *Field {
 		selectionSet := p.parseSelectionSet()
 		result.SelectionSet = selectionSet
 	}
-	result.Location = p.loc(start)
+	result.Location = p.commentedLoc(start, comments)
 	return result
 }
 
@@ -412,11 +994,12 @@ func (p *Parser) parseArguments() []*Argument {
 func (p *Parser) parseArgument() *Argument {
 	start := p.token.Start
 
+	comments := p.takeComments()
 	result := &Argument{}
 	result.Name = p.parseName()
 	p.expect(TOKEN_COLON)
 	result.Value = p.parseValueLiteral(false)
-	result.Location = p.loc(start)
+	result.Location = p.commentedLoc(start, comments)
 	return result
 }
 
@@ -431,21 +1014,26 @@ func (p *Parser) parseArgument() *Argument {
  */
 func (p *Parser) parseFragment() IFragment {
 	start := p.token.Start
+	comments := p.takeComments()
 	p.expect(TOKEN_SPREAD)
 	if p.token.Value == "on" {
 		p.advance()
-		return &InlineFragment{
+		result := &InlineFragment{
 			TypeCondition: p.parseNamedType(),
 			Directives:    p.parseDirectives(),
 			SelectionSet:  p.parseSelectionSet(),
-			Location:      p.loc(start),
+			Location:      p.commentedLoc(start, comments),
 		}
+		if result.SelectionSet == nil {
+			return nil
+		}
+		return result
 	}
 
 	return &FragmentSpread{
 		Name:       p.parseFragmentName(),
 		Directives: p.parseDirectives(),
-		Location:   p.loc(start),
+		Location:   p.commentedLoc(start, comments),
 	}
 }
 
@@ -457,6 +1045,7 @@ func (p *Parser) parseFragment() IFragment {
  */
 func (p *Parser) parseFragmentDefinition() *FragmentDefinition {
 	start := p.token.Start
+	comments := p.takeComments()
 
 	result := &FragmentDefinition{}
 	p.expectKeyword("fragment")
@@ -465,7 +1054,7 @@ func (p *Parser) parseFragmentDefinition() *FragmentDefinition {
 	result.TypeCondition = p.parseNamedType()
 	result.Directives = p.parseDirectives()
 	result.SelectionSet = p.parseSelectionSet()
-	result.Location = p.loc(start)
+	result.Location = p.commentedLoc(start, comments)
 	return result
 }
 
@@ -488,12 +1077,15 @@ func (p *Parser) parseFragmentName() *Name {
  *   - FloatValue
  *   - StringValue
  *   - BooleanValue
+ *   - NullValue
  *   - EnumValue
  *   - ListValue[?Const]
  *   - ObjectValue[?Const]
  *
  * BooleanValue : one of `true` `false`
  *
+ * NullValue : `null`
+ *
  * EnumValue : Name but not `true`, `false` or `null`
  */
 func (p *Parser) parseValueLiteral(isConst bool) IValue {
@@ -515,10 +1107,11 @@ func (p *Parser) parseValueLiteral(isConst bool) IValue {
 			Value:    token.Value,
 			Location: p.loc(token.Start),
 		}
-	case TOKEN_STRING:
+	case TOKEN_STRING, TOKEN_BLOCK_STRING:
 		p.advance()
 		return &StringValue{
 			Value:    token.Value,
+			Block:    token.Kind == TOKEN_BLOCK_STRING,
 			Location: p.loc(token.Start),
 		}
 	case TOKEN_NAME:
@@ -528,7 +1121,12 @@ func (p *Parser) parseValueLiteral(isConst bool) IValue {
 				Value:    token.Value,
 				Location: p.loc(token.Start),
 			}
-		} else if token.Value != "null" {
+		} else if token.Value == "null" {
+			p.advance()
+			return &NullValue{
+				Location: p.loc(token.Start),
+			}
+		} else {
 			p.advance()
 			return &EnumValue{
 				Value:    token.Value,
@@ -558,6 +1156,8 @@ func (p *Parser) parseValueValue() IValue {
  */
 func (p *Parser) parseList(isConst bool) *ListValue {
 	start := p.token.Start
+	p.enter()
+	defer p.leave()
 	var itemFn func() IValue
 	if isConst {
 		itemFn = p.parseConstValue
@@ -582,6 +1182,8 @@ func (p *Parser) parseList(isConst bool) *ListValue {
  */
 func (p *Parser) parseObject(isConst bool) *ObjectValue {
 	start := p.token.Start
+	p.enter()
+	defer p.leave()
 	p.expect(TOKEN_BRACE_L)
 	fieldNames := make(map[string]struct{})
 	fields := make([]*ObjectField, 4)[:0]
@@ -652,10 +1254,12 @@ func (p *Parser) parseType() IType {
 	start := p.token.Start
 	var typ IType
 	if p.skip(TOKEN_BRACKET_L) {
-		typ := p.parseType()
+		p.enter()
+		ofType := p.parseType()
 		p.expect(TOKEN_BRACKET_R)
+		p.leave()
 		typ = &ListType{
-			Type:     typ,
+			Type:     ofType,
 			Location: p.loc(start),
 		}
 	} else {
@@ -681,7 +1285,104 @@ func (p *Parser) parseNamedType() *NamedType {
 	}
 }
 
-// Implements the parsing rules in the IType IDefinition section.
+// Implements the parsing rules in the Type System section.
+
+/**
+ * TypeSystemDefinition :
+ *   - SchemaDefinition
+ *   - TypeDefinition
+ *   - DirectiveDefinition
+ *   - TypeSystemExtension
+ *
+ * The keyword of a definition follows its description, if any.
+ */
+func (p *Parser) parseTypeSystemDefinition() IDefinition {
+	keyword := p.token
+	if p.peekDescription() {
+		keyword = p.lookahead()
+	}
+	if keyword.Kind == TOKEN_NAME {
+		switch keyword.Value {
+		case "schema":
+			return p.parseSchemaDefinition()
+		case "directive":
+			return p.parseDirectiveDefinition()
+		case "extend":
+			if p.peekDescription() {
+				panic(p.unexpected(nil))
+			}
+			return p.parseTypeSystemExtension()
+		}
+	}
+	return p.parseTypeDefinition()
+}
+
+func (p *Parser) peekDescription() bool {
+	return p.peek(TOKEN_STRING) || p.peek(TOKEN_BLOCK_STRING)
+}
+
+/**
+ * Description : StringValue
+ *
+ * The description keeps the comment following it on its line, and the
+ * comments between it and its definition as its inner comments.
+ */
+func (p *Parser) parseDescription() *StringValue {
+	if !p.peekDescription() {
+		return nil
+	}
+	start := p.token.Start
+	description := p.parseValueLiteral(true).(*StringValue)
+	description.Location = p.commentedLoc(start, nil)
+	if inner := p.takeComments(); description.Location != nil && len(inner) > 0 {
+		if description.Location.Comments == nil {
+			description.Location.Comments = &Comments{}
+		}
+		description.Location.Comments.Inner = inner
+	}
+	return description
+}
+
+/**
+ * SchemaDefinition : Description? schema Directives? { OperationTypeDefinition+ }
+ */
+func (p *Parser) parseSchemaDefinition() *SchemaDefinition {
+	start := p.token.Start
+	comments := p.takeComments()
+	description := p.parseDescription()
+	p.expectKeyword("schema")
+	directives := p.parseDirectives()
+	operationTypes := make([]*OperationTypeDefinition, 3)[:0]
+	p.block(true, func() {
+		operationTypes = append(operationTypes, p.parseOperationTypeDefinition())
+	})
+	return &SchemaDefinition{
+		Description:    description,
+		Directives:     directives,
+		OperationTypes: operationTypes,
+		Location:       p.commentedLoc(start, comments),
+	}
+}
+
+/**
+ * OperationTypeDefinition : OperationType : NamedType
+ */
+func (p *Parser) parseOperationTypeDefinition() *OperationTypeDefinition {
+	start := p.token.Start
+	comments := p.takeComments()
+	operationToken := p.expect(TOKEN_NAME)
+	switch OperationType(operationToken.Value) {
+	case OperationQuery, OperationMutation, OperationSubscription:
+	default:
+		panic(p.unexpected(&operationToken))
+	}
+	p.expect(TOKEN_COLON)
+	return &OperationTypeDefinition{
+		Operation: OperationType(operationToken.Value),
+		Type:      p.parseNamedType(),
+		Location:  p.commentedLoc(start, comments),
+	}
+}
 
 /**
  * TypeDefinition :
@@ -691,13 +1392,16 @@ func (p *Parser) parseNamedType() *NamedType {
  *   - ScalarTypeDefinition
  *   - EnumTypeDefinition
  *   - InputObjectTypeDefinition
- *   - TypeExtensionDefinition
  */
 func (p *Parser) parseTypeDefinition() ITypeDefinition {
-	if !p.peek(TOKEN_NAME) {
-		panic(p.unexpected(nil))
+	keyword := p.token
+	if p.peekDescription() {
+		keyword = p.lookahead()
 	}
-	switch p.token.Value {
+	if keyword.Kind != TOKEN_NAME {
+		panic(p.unexpected(&keyword))
+	}
+	switch keyword.Value {
 	case "type":
 		return p.parseObjectTypeDefinition()
 	case "interface":
@@ -710,42 +1414,46 @@ func (p *Parser) parseTypeDefinition() ITypeDefinition {
 		return p.parseEnumTypeDefinition()
 	case "input":
 		return p.parseInputObjectTypeDefinition()
-	case "extend":
-		return p.parseTypeExtensionDefinition()
 	default:
-		panic(p.unexpected(nil))
+		panic(p.unexpected(&keyword))
 	}
 }
 
 /**
- * ObjectTypeDefinition : type Name ImplementsInterfaces? { FieldDefinition+ }
+ * ObjectTypeDefinition :
+ *   - Description? type Name ImplementsInterfaces? Directives? FieldsDefinition?
  */
 func (p *Parser) parseObjectTypeDefinition() *ObjectTypeDefinition {
 	start := p.token.Start
+	comments := p.takeComments()
+	description := p.parseDescription()
 	p.expectKeyword("type")
 	name := p.parseName()
 	interfaces := p.parseImplementsInterfaces()
-	fields := make([]*FieldDefinition, 4)[:0]
-	p.any(TOKEN_BRACE_L, TOKEN_BRACE_R, func() {
-		fields = append(fields, p.parseFieldDefinition())
-	})
+	directives := p.parseDirectives()
+	fields := p.parseFieldsDefinition()
 	return &ObjectTypeDefinition{
-		Name:       name,
-		Interfaces: interfaces,
-		Fields:     fields,
-		Location:   p.loc(start),
+		Description: description,
+		Name:        name,
+		Interfaces:  interfaces,
+		Directives:  directives,
+		Fields:      fields,
+		Location:    p.commentedLoc(start, comments),
 	}
 }
 
 /**
- * ImplementsInterfaces : implements NamedType+
+ * ImplementsInterfaces :
+ *   - implements &? NamedType
+ *   - ImplementsInterfaces & NamedType
  */
 func (p *Parser) parseImplementsInterfaces() []*NamedType {
 	types := make([]*NamedType, 4)[:0]
-	if p.token.Value == "implements" {
+	if p.peek(TOKEN_NAME) && p.token.Value == "implements" {
 		p.advance()
+		p.skip(TOKEN_AMP)
 		types = append(types, p.parseNamedType())
-		for !p.peek(TOKEN_BRACE_L) {
+		for p.skip(TOKEN_AMP) {
 			types = append(types, p.parseNamedType())
 		}
 	}
@@ -753,16 +1461,33 @@ func (p *Parser) parseImplementsInterfaces() []*NamedType {
 }
 
 /**
- * FieldDefinition : Name ArgumentsDefinition? : IType
+ * FieldsDefinition : { FieldDefinition* }
+ */
+func (p *Parser) parseFieldsDefinition() []*FieldDefinition {
+	fields := make([]*FieldDefinition, 4)[:0]
+	if p.peek(TOKEN_BRACE_L) {
+		p.block(false, func() {
+			fields = append(fields, p.parseFieldDefinition())
+		})
+	}
+	return fields
+}
+
+/**
+ * FieldDefinition :
+ *   - Description? Name ArgumentsDefinition? : IType Directives?
  */
 func (p *Parser) parseFieldDefinition() *FieldDefinition {
 	start := p.token.Start
+	comments := p.takeComments()
 	result := &FieldDefinition{}
+	result.Description = p.parseDescription()
 	result.Name = p.parseName()
 	result.Arguments = p.parseArgumentDefs()
 	p.expect(TOKEN_COLON)
 	result.Type = p.parseType()
-	result.Location = p.loc(start)
+	result.Directives = p.parseDirectives()
+	result.Location = p.commentedLoc(start, comments)
 	return result
 }
 
@@ -782,61 +1507,80 @@ func (p *Parser) parseArgumentDefs() []*InputValueDefinition {
 }
 
 /**
- * InputValueDefinition : Name : IType DefaultValue?
+ * InputValueDefinition :
+ *   - Description? Name : IType DefaultValue? Directives?
  */
 func (p *Parser) parseInputValueDef() *InputValueDefinition {
 	start := p.token.Start
+	comments := p.takeComments()
 	result := &InputValueDefinition{}
+	result.Description = p.parseDescription()
 	result.Name = p.parseName()
 	p.expect(TOKEN_COLON)
 	result.Type = p.parseType()
 	if p.skip(TOKEN_EQUALS) {
 		result.DefaultValue = p.parseConstValue()
 	}
-	result.Location = p.loc(start)
+	result.Directives = p.parseDirectives()
+	result.Location = p.commentedLoc(start, comments)
 	return result
 }
 
 /**
- * InterfaceTypeDefinition : interface Name { FieldDefinition+ }
+ * InterfaceTypeDefinition :
+ *   - Description? interface Name ImplementsInterfaces? Directives? FieldsDefinition?
  */
 func (p *Parser) parseInterfaceTypeDefinition() *InterfaceTypeDefinition {
 	start := p.token.Start
+	comments := p.takeComments()
+	description := p.parseDescription()
 	p.expectKeyword("interface")
 	name := p.parseName()
-	fields := make([]*FieldDefinition, 4)[:0]
-	p.any(TOKEN_BRACE_L, TOKEN_BRACE_R, func() {
-		fields = append(fields, p.parseFieldDefinition())
-	})
+	interfaces := p.parseImplementsInterfaces()
+	directives := p.parseDirectives()
+	fields := p.parseFieldsDefinition()
 	return &InterfaceTypeDefinition{
-		Name:     name,
-		Fields:   fields,
-		Location: p.loc(start),
+		Description: description,
+		Name:        name,
+		Interfaces:  interfaces,
+		Directives:  directives,
+		Fields:      fields,
+		Location:    p.commentedLoc(start, comments),
 	}
 }
 
 /**
- * UnionTypeDefinition : union Name = UnionMembers
+ * UnionTypeDefinition :
+ *   - Description? union Name Directives? UnionMemberTypes?
  */
 func (p *Parser) parseUnionTypeDefinition() *UnionTypeDefinition {
 	start := p.token.Start
+	comments := p.takeComments()
+	description := p.parseDescription()
 	p.expectKeyword("union")
 	name := p.parseName()
-	types := p.parseUnionMembers()
+	directives := p.parseDirectives()
+	var types []*NamedType
+	if p.skip(TOKEN_EQUALS) {
+		types = p.parseUnionMembers()
+	}
 	return &UnionTypeDefinition{
-		Name:     name,
-		Types:    types,
-		Location: p.loc(start),
+		Description: description,
+		Name:        name,
+		Directives:  directives,
+		Types:       types,
+		Location:    p.commentedLoc(start, comments),
 	}
 }
 
 /**
  * UnionMembers :
- *   - NamedType
+ *   - |? NamedType
  *   - UnionMembers | NamedType
  */
 func (p *Parser) parseUnionMembers() []*NamedType {
 	result := make([]*NamedType, 4)[:0]
+	p.skip(TOKEN_PIPE)
 	result = append(result, p.parseNamedType())
 	for p.skip(TOKEN_PIPE) {
 		result = append(result, p.parseNamedType())
@@ -845,77 +1589,395 @@ func (p *Parser) parseUnionMembers() []*NamedType {
 }
 
 /**
- * ScalarTypeDefinition : scalar Name
+ * ScalarTypeDefinition : Description? scalar Name Directives?
  */
 func (p *Parser) parseScalarTypeDefinition() *ScalarTypeDefinition {
 	start := p.token.Start
+	comments := p.takeComments()
+	description := p.parseDescription()
 	p.expectKeyword("scalar")
 	name := p.parseName()
+	directives := p.parseDirectives()
 	return &ScalarTypeDefinition{
-		Name:     name,
-		Location: p.loc(start),
+		Description: description,
+		Name:        name,
+		Directives:  directives,
+		Location:    p.commentedLoc(start, comments),
 	}
 }
 
 /**
- * EnumTypeDefinition : enum Name { EnumValueDefinition+ }
+ * EnumTypeDefinition :
+ *   - Description? enum Name Directives? { EnumValueDefinition+ }?
  */
 func (p *Parser) parseEnumTypeDefinition() *EnumTypeDefinition {
 	start := p.token.Start
+	comments := p.takeComments()
+	description := p.parseDescription()
 	p.expectKeyword("enum")
 	name := p.parseName()
-	values := make([]*EnumValueDefinition, 4)[:0]
-	p.many(TOKEN_BRACE_L, TOKEN_BRACE_R, func() {
-		values = append(values, p.parseEnumValueDefinition())
-	})
+	directives := p.parseDirectives()
+	values := p.parseEnumValuesDefinition()
 	return &EnumTypeDefinition{
-		Name:     name,
-		Values:   values,
-		Location: p.loc(start),
+		Description: description,
+		Name:        name,
+		Directives:  directives,
+		Values:      values,
+		Location:    p.commentedLoc(start, comments),
 	}
 }
 
 /**
- * EnumValueDefinition : EnumValue
+ * EnumValuesDefinition : { EnumValueDefinition+ }
+ */
+func (p *Parser) parseEnumValuesDefinition() []*EnumValueDefinition {
+	values := make([]*EnumValueDefinition, 4)[:0]
+	if p.peek(TOKEN_BRACE_L) {
+		p.block(true, func() {
+			values = append(values, p.parseEnumValueDefinition())
+		})
+	}
+	return values
+}
+
+/**
+ * EnumValueDefinition : Description? EnumValue Directives?
  *
- * EnumValue : Name
+ * EnumValue : Name but not `true`, `false` or `null`
  */
 func (p *Parser) parseEnumValueDefinition() *EnumValueDefinition {
 	start := p.token.Start
+	comments := p.takeComments()
+	description := p.parseDescription()
+	switch p.token.Value {
+	case "true", "false", "null":
+		panic(p.unexpected(nil))
+	}
 	name := p.parseName()
+	directives := p.parseDirectives()
 	return &EnumValueDefinition{
-		Name:     name,
-		Location: p.loc(start),
+		Description: description,
+		Name:        name,
+		Directives:  directives,
+		Location:    p.commentedLoc(start, comments),
 	}
 }
 
 /**
- * InputObjectTypeDefinition : input Name { InputValueDefinition+ }
+ * InputObjectTypeDefinition :
+ *   - Description? input Name Directives? { InputValueDefinition* }?
  */
 func (p *Parser) parseInputObjectTypeDefinition() *InputObjectTypeDefinition {
 	start := p.token.Start
+	comments := p.takeComments()
+	description := p.parseDescription()
 	p.expectKeyword("input")
 	name := p.parseName()
-	fields := make([]*InputValueDefinition, 4)[:0]
-	p.any(TOKEN_BRACE_L, TOKEN_BRACE_R, func() {
-		fields = append(fields, p.parseInputValueDef())
-	})
+	directives := p.parseDirectives()
+	fields := p.parseInputFieldsDefinition()
 	return &InputObjectTypeDefinition{
-		Name:     name,
-		Fields:   fields,
-		Location: p.loc(start),
+		Description: description,
+		Name:        name,
+		Directives:  directives,
+		Fields:      fields,
+		Location:    p.commentedLoc(start, comments),
+	}
+}
+
+/**
+ * InputFieldsDefinition : { InputValueDefinition* }
+ */
+func (p *Parser) parseInputFieldsDefinition() []*InputValueDefinition {
+	fields := make([]*InputValueDefinition, 4)[:0]
+	if p.peek(TOKEN_BRACE_L) {
+		p.block(false, func() {
+			fields = append(fields, p.parseInputValueDef())
+		})
+	}
+	return fields
+}
+
+/**
+ * TypeSystemExtension :
+ *   - SchemaExtension
+ *   - TypeExtension
+ *
+ * TypeExtension :
+ *   - ScalarTypeExtension
+ *   - TypeExtensionDefinition
+ *   - InterfaceTypeExtension
+ *   - UnionTypeExtension
+ *   - EnumTypeExtension
+ *   - InputObjectTypeExtension
+ */
+func (p *Parser) parseTypeSystemExtension() IDefinition {
+	keyword := p.lookahead()
+	if keyword.Kind == TOKEN_NAME {
+		switch keyword.Value {
+		case "schema":
+			return p.parseSchemaExtension()
+		case "scalar":
+			return p.parseScalarTypeExtension()
+		case "type":
+			return p.parseTypeExtensionDefinition()
+		case "interface":
+			return p.parseInterfaceTypeExtension()
+		case "union":
+			return p.parseUnionTypeExtension()
+		case "enum":
+			return p.parseEnumTypeExtension()
+		case "input":
+			return p.parseInputObjectTypeExtension()
+		}
 	}
+	panic(p.unexpected(&keyword))
 }
 
 /**
- * TypeExtensionDefinition : extend ObjectTypeDefinition
+ * SchemaExtension :
+ *   - extend schema Directives? { OperationTypeDefinition+ }
+ *   - extend schema Directives
+ */
+func (p *Parser) parseSchemaExtension() *SchemaExtension {
+	start := p.token.Start
+	comments := p.takeComments()
+	p.expectKeyword("extend")
+	p.expectKeyword("schema")
+	directives := p.parseDirectives()
+	var operationTypes []*OperationTypeDefinition
+	if p.peek(TOKEN_BRACE_L) {
+		p.block(true, func() {
+			operationTypes = append(operationTypes, p.parseOperationTypeDefinition())
+		})
+	}
+	if len(directives) == 0 && len(operationTypes) == 0 {
+		panic(p.unexpected(nil))
+	}
+	return &SchemaExtension{
+		Directives:     directives,
+		OperationTypes: operationTypes,
+		Location:       p.commentedLoc(start, comments),
+	}
+}
+
+/**
+ * TypeExtensionDefinition :
+ *   - extend type Name ImplementsInterfaces? Directives? FieldsDefinition
+ *   - extend type Name ImplementsInterfaces? Directives
+ *   - extend type Name ImplementsInterfaces
  */
 func (p *Parser) parseTypeExtensionDefinition() *TypeExtensionDefinition {
 	start := p.token.Start
+	comments := p.takeComments()
 	p.expectKeyword("extend")
 	definition := p.parseObjectTypeDefinition()
+	if len(definition.Interfaces) == 0 &&
+		len(definition.Directives) == 0 &&
+		len(definition.Fields) == 0 {
+		panic(p.unexpected(nil))
+	}
 	return &TypeExtensionDefinition{
 		Definition: definition,
-		Location:   p.loc(start),
+		Location:   p.commentedLoc(start, comments),
+	}
+}
+
+/**
+ * ScalarTypeExtension : extend scalar Name Directives
+ */
+func (p *Parser) parseScalarTypeExtension() *ScalarTypeExtension {
+	start := p.token.Start
+	comments := p.takeComments()
+	p.expectKeyword("extend")
+	p.expectKeyword("scalar")
+	name := p.parseName()
+	directives := p.parseDirectives()
+	if len(directives) == 0 {
+		panic(p.unexpected(nil))
+	}
+	return &ScalarTypeExtension{
+		Name:       name,
+		Directives: directives,
+		Location:   p.commentedLoc(start, comments),
+	}
+}
+
+/**
+ * InterfaceTypeExtension :
+ *   - extend interface Name ImplementsInterfaces? Directives? FieldsDefinition
+ *   - extend interface Name ImplementsInterfaces? Directives
+ *   - extend interface Name ImplementsInterfaces
+ */
+func (p *Parser) parseInterfaceTypeExtension() *InterfaceTypeExtension {
+	start := p.token.Start
+	comments := p.takeComments()
+	p.expectKeyword("extend")
+	p.expectKeyword("interface")
+	name := p.parseName()
+	interfaces := p.parseImplementsInterfaces()
+	directives := p.parseDirectives()
+	fields := p.parseFieldsDefinition()
+	if len(interfaces) == 0 && len(directives) == 0 && len(fields) == 0 {
+		panic(p.unexpected(nil))
+	}
+	return &InterfaceTypeExtension{
+		Name:       name,
+		Interfaces: interfaces,
+		Directives: directives,
+		Fields:     fields,
+		Location:   p.commentedLoc(start, comments),
+	}
+}
+
+/**
+ * UnionTypeExtension :
+ *   - extend union Name Directives? = UnionMembers
+ *   - extend union Name Directives
+ */
+func (p *Parser) parseUnionTypeExtension() *UnionTypeExtension {
+	start := p.token.Start
+	comments := p.takeComments()
+	p.expectKeyword("extend")
+	p.expectKeyword("union")
+	name := p.parseName()
+	directives := p.parseDirectives()
+	var types []*NamedType
+	if p.skip(TOKEN_EQUALS) {
+		types = p.parseUnionMembers()
+	}
+	if len(directives) == 0 && len(types) == 0 {
+		panic(p.unexpected(nil))
+	}
+	return &UnionTypeExtension{
+		Name:       name,
+		Directives: directives,
+		Types:      types,
+		Location:   p.commentedLoc(start, comments),
+	}
+}
+
+/**
+ * EnumTypeExtension :
+ *   - extend enum Name Directives? EnumValuesDefinition
+ *   - extend enum Name Directives
+ */
+func (p *Parser) parseEnumTypeExtension() *EnumTypeExtension {
+	start := p.token.Start
+	comments := p.takeComments()
+	p.expectKeyword("extend")
+	p.expectKeyword("enum")
+	name := p.parseName()
+	directives := p.parseDirectives()
+	values := p.parseEnumValuesDefinition()
+	if len(directives) == 0 && len(values) == 0 {
+		panic(p.unexpected(nil))
+	}
+	return &EnumTypeExtension{
+		Name:       name,
+		Directives: directives,
+		Values:     values,
+		Location:   p.commentedLoc(start, comments),
+	}
+}
+
+/**
+ * InputObjectTypeExtension :
+ *   - extend input Name Directives? InputFieldsDefinition
+ *   - extend input Name Directives
+ */
+func (p *Parser) parseInputObjectTypeExtension() *InputObjectTypeExtension {
+	start := p.token.Start
+	comments := p.takeComments()
+	p.expectKeyword("extend")
+	p.expectKeyword("input")
+	name := p.parseName()
+	directives := p.parseDirectives()
+	fields := p.parseInputFieldsDefinition()
+	if len(directives) == 0 && len(fields) == 0 {
+		panic(p.unexpected(nil))
+	}
+	return &InputObjectTypeExtension{
+		Name:       name,
+		Directives: directives,
+		Fields:     fields,
+		Location:   p.commentedLoc(start, comments),
+	}
+}
+
+/**
+ * DirectiveDefinition :
+ *   - Description? directive @ Name ArgumentsDefinition? repeatable? on DirectiveLocations
+ */
+func (p *Parser) parseDirectiveDefinition() *DirectiveDefinition {
+	start := p.token.Start
+	comments := p.takeComments()
+	description := p.parseDescription()
+	p.expectKeyword("directive")
+	p.expect(TOKEN_AT)
+	name := p.parseName()
+	args := p.parseArgumentDefs()
+	repeatable := false
+	if p.peek(TOKEN_NAME) && p.token.Value == "repeatable" {
+		p.advance()
+		repeatable = true
+	}
+	p.expectKeyword("on")
+	return &DirectiveDefinition{
+		Description: description,
+		Name:        name,
+		Arguments:   args,
+		Repeatable:  repeatable,
+		Locations:   p.parseDirectiveLocations(),
+		Location:    p.commentedLoc(start, comments),
+	}
+}
+
+/**
+ * DirectiveLocations :
+ *   - |? DirectiveLocation
+ *   - DirectiveLocations | DirectiveLocation
+ */
+func (p *Parser) parseDirectiveLocations() []*Name {
+	locations := make([]*Name, 4)[:0]
+	p.skip(TOKEN_PIPE)
+	locations = append(locations, p.parseDirectiveLocation())
+	for p.skip(TOKEN_PIPE) {
+		locations = append(locations, p.parseDirectiveLocation())
+	}
+	return locations
+}
+
+var directiveLocations = map[string]bool{
+	"QUERY":                  true,
+	"MUTATION":               true,
+	"SUBSCRIPTION":           true,
+	"FIELD":                  true,
+	"FRAGMENT_DEFINITION":    true,
+	"FRAGMENT_SPREAD":        true,
+	"INLINE_FRAGMENT":        true,
+	"VARIABLE_DEFINITION":    true,
+	"SCHEMA":                 true,
+	"SCALAR":                 true,
+	"OBJECT":                 true,
+	"FIELD_DEFINITION":       true,
+	"ARGUMENT_DEFINITION":    true,
+	"INTERFACE":              true,
+	"UNION":                  true,
+	"ENUM":                   true,
+	"ENUM_VALUE":             true,
+	"INPUT_OBJECT":           true,
+	"INPUT_FIELD_DEFINITION": true,
+}
+
+/**
+ * DirectiveLocation : one of the executable or type system directive
+ * locations, such as QUERY, FIELD or OBJECT
+ */
+func (p *Parser) parseDirectiveLocation() *Name {
+	token := p.token
+	name := p.parseName()
+	if !directiveLocations[name.Value] {
+		panic(p.unexpected(&token))
 	}
+	return name
 }
@@ -3,6 +3,7 @@ package language
 import (
 	"fmt"
 	"io/ioutil"
+	"strings"
 	"testing"
 )
 
@@ -140,10 +141,19 @@ func TestParse_DoesNotAcceptFragmentsSpreadOfOn(T *testing.T) {
 	}, `Syntax Error GraphQL (1:9) Expected Name, found }`)
 }
 
-func TestParse_DoesNotAllowNullAsValue(T *testing.T) {
-	expectPanic(T, func() {
-		Parse(NewSource(`{ fieldWithNullableStringInput(input: null) }`, ""), ParseOptions{})
-	}, `Syntax Error GraphQL (1:39) Unexpected Name "null"`)
+func TestParse_AllowsNullAsValue(T *testing.T) {
+	tree, err := Parse(NewSource(`{ fieldWithNullableStringInput(input: null) }`, ""), ParseOptions{NoLocation: true})
+	if err != nil {
+		T.Error(err)
+		return
+	}
+	field := tree.Definitions[0].(*OperationDefinition).SelectionSet.Selections[0].(*Field)
+	deepEqual(T, field.Arguments[0].Value, &NullValue{})
+}
+
+func TestParse_DoesNotAllowNullAsEnumValue(T *testing.T) {
+	expectParseError(T, `enum E { A null }`, ParseOptions{},
+		"Unexpected Name null")
 }
 
 func TestParse_ParsesKitchenSink(T *testing.T) {
@@ -345,3 +355,542 @@ func TestParse_ParseCreatesAst(T *testing.T) {
 		},
 	})
 }
+
+func expectParseError(T *testing.T, source string, options ParseOptions, msg string) {
+	_, err := Parse(NewSource(source, ""), options)
+	if err == nil {
+		T.Errorf("Expect error with message:\n%v\n---", msg)
+		return
+	}
+	if !strings.Contains(err.Error(), msg) {
+		T.Errorf("Expect error with message:\n%v---\nbut got:\n%v---", msg, err)
+	}
+}
+
+func expectParseOK(T *testing.T, source string, options ParseOptions) {
+	_, err := Parse(NewSource(source, ""), options)
+	if err != nil {
+		T.Errorf("Expect no error but got:\n%v---", err)
+	}
+}
+
+func TestParse_LimitsNumberOfTokens(T *testing.T) {
+	options := ParseOptions{MaxTokens: 6}
+	expectParseOK(T, `{ a b c d }`, options)
+	expectParseError(T, `{ a b c d e }`, options,
+		"Document contains more than 6 tokens")
+
+	// Comments and whitespace are not tokens
+	expectParseOK(T, "{ a # comment\n   b c d }", options)
+
+	// Stops lexing as soon as the limit is reached
+	expectParseError(T, "{ "+strings.Repeat("a ", 1000000)+"}", options,
+		"Document contains more than 6 tokens")
+}
+
+func TestParse_LimitsNestingOfSelectionSets(T *testing.T) {
+	options := ParseOptions{MaxDepth: 3}
+	expectParseOK(T, `{ a { b { c } } }`, options)
+	expectParseOK(T, `{ a { b { c } } d { e { f } } }`, options)
+	expectParseError(T, `{ a { b { c { d } } } }`, options,
+		"Document exceeds the maximum nesting depth of 3")
+	expectParseError(T, `{ ... on T { ... on T { ... on T { a } } } }`, options,
+		"Document exceeds the maximum nesting depth of 3")
+}
+
+func TestParse_LimitsNestingOfValues(T *testing.T) {
+	options := ParseOptions{MaxDepth: 4}
+	expectParseOK(T, `{ f(a: [[[1]]]) }`, options)
+	expectParseError(T, `{ f(a: [[[[1]]]]) }`, options,
+		"Document exceeds the maximum nesting depth of 4")
+	expectParseOK(T, `{ f(a: { b: { c: 1 } }) }`, options)
+	expectParseError(T, `{ f(a: { b: { c: { d: { e: 1 } } } }) }`, options,
+		"Document exceeds the maximum nesting depth of 4")
+	expectParseOK(T, `query Q($a: T = [[[[1]]]]) { f }`, options)
+	expectParseError(T, `query Q($a: T = [[[[[1]]]]]) { f }`, options,
+		"Document exceeds the maximum nesting depth of 4")
+
+	_, err := ParseValue(NewSource(`[[[[[1]]]]]`, ""), options)
+	expect(T, err != nil && strings.Contains(err.Error(), "maximum nesting depth of 4"),
+		"Expect nesting error but got: %v", err)
+}
+
+func TestParse_LimitsNestingOfListTypes(T *testing.T) {
+	options := ParseOptions{MaxDepth: 3}
+	expectParseOK(T, `query Q($a: [[[Int]]]) { f }`, options)
+	expectParseError(T, `query Q($a: [[[[Int]]]]) { f }`, options,
+		"Document exceeds the maximum nesting depth of 3")
+}
+
+func TestParse_ParsesTypeReferences(T *testing.T) {
+	typ, err := ParseType(NewSource(`[String!]!`, ""), ParseOptions{NoLocation: true})
+	if err != nil {
+		T.Fatal(err)
+	}
+	deepEqual(T, typ, &NonNullType{
+		Type: &ListType{
+			Type: &NonNullType{
+				Type: &NamedType{Name: &Name{Value: "String"}},
+			},
+		},
+	})
+
+	_, err = ParseType(NewSource(`String Int`, ""))
+	expect(T, err != nil && strings.Contains(err.Error(), "Expected EOF, found Name Int"),
+		"Expect EOF error but got: %v", err)
+}
+
+func TestParse_RejectsDeeplyNestedInput(T *testing.T) {
+	options := ParseOptions{MaxDepth: 100}
+	n := 1000000
+	expectParseError(T, strings.Repeat("{ a ", n)+strings.Repeat("}", n), options,
+		"Document exceeds the maximum nesting depth of 100")
+	expectParseError(T, "{ f(a: "+strings.Repeat("[", n)+") }", options,
+		"Document exceeds the maximum nesting depth of 100")
+	expectParseError(T, "{ f(a: "+strings.Repeat("{ a: ", n)+") }", options,
+		"Document exceeds the maximum nesting depth of 100")
+	expectParseError(T, "query Q($a: "+strings.Repeat("[", n)+") { f }", options,
+		"Document exceeds the maximum nesting depth of 100")
+}
+
+func TestParse_ParsesListTypes(T *testing.T) {
+	tree, err := Parse(NewSource(`query Q($a: [Int!]) { f }`, ""), ParseOptions{NoLocation: true})
+	if err != nil {
+		T.Error(err)
+		return
+	}
+	operation := tree.Definitions[0].(*OperationDefinition)
+	deepEqual(T, operation.VariableDefinitions[0].Type, &ListType{
+		Type: &NonNullType{
+			Type: &NamedType{
+				Name: &Name{Value: "Int"},
+			},
+		},
+	})
+}
+
+func TestParse_ParsesSchemaKitchenSink(T *testing.T) {
+	kitchenSink, err := ioutil.ReadFile("schema-kitchen-sink.graphql")
+	if err != nil {
+		panic(err)
+	}
+
+	tree, err := Parse(NewSource(string(kitchenSink), ""), ParseOptions{NoLocation: true})
+	if err != nil {
+		T.Error(err)
+		return
+	}
+
+	// Printing is stable once the source has been normalized
+	printed := Print(tree)
+	reparsed, err := Parse(NewSource(printed, ""), ParseOptions{NoLocation: true})
+	if err != nil {
+		T.Errorf("Expect printed schema to parse but got:\n%v---\n%v", err, printed)
+		return
+	}
+	expect(T, Print(reparsed) == printed,
+		"Expect printing to be stable:\n%v---\n%v", printed, Print(reparsed))
+	deepEqual(T, reparsed, tree)
+}
+
+func TestParse_ParsesSchemaDefinition(T *testing.T) {
+	tree, err := Parse(NewSource(`
+"Description" schema @a { query: Q mutation: M }`, ""), ParseOptions{NoLocation: true})
+	if err != nil {
+		T.Error(err)
+		return
+	}
+	deepEqual(T, tree.Definitions[0], &SchemaDefinition{
+		Description: &StringValue{Value: "Description"},
+		Directives: []*Directive{
+			{Name: &Name{Value: "a"}},
+		},
+		OperationTypes: []*OperationTypeDefinition{
+			{Operation: OperationQuery, Type: &NamedType{Name: &Name{Value: "Q"}}},
+			{Operation: OperationMutation, Type: &NamedType{Name: &Name{Value: "M"}}},
+		},
+	})
+
+	expectParseError(T, `schema { query: Q, fragment: F }`, ParseOptions{},
+		"Unexpected Name fragment")
+	expectParseError(T, `extend schema`, ParseOptions{},
+		"Unexpected EOF")
+	expectParseError(T, `"Description" extend type T { a: A }`, ParseOptions{},
+		"Unexpected String")
+}
+
+func TestParse_ParsesDirectiveDefinition(T *testing.T) {
+	tree, err := Parse(NewSource(`
+"Description"
+directive @a(b: Int = 1) repeatable on | FIELD | OBJECT`, ""), ParseOptions{NoLocation: true})
+	if err != nil {
+		T.Error(err)
+		return
+	}
+	deepEqual(T, tree.Definitions[0], &DirectiveDefinition{
+		Description: &StringValue{Value: "Description"},
+		Name:        &Name{Value: "a"},
+		Arguments: []*InputValueDefinition{
+			{
+				Name:         &Name{Value: "b"},
+				Type:         &NamedType{Name: &Name{Value: "Int"}},
+				DefaultValue: &IntValue{Value: "1"},
+				Directives:   []*Directive{},
+			},
+		},
+		Repeatable: true,
+		Locations:  []*Name{{Value: "FIELD"}, {Value: "OBJECT"}},
+	})
+
+	expectParseError(T, `directive @a on FIELD | NOWHERE`, ParseOptions{},
+		"Unexpected Name NOWHERE")
+	expectParseError(T, `directive @a on`, ParseOptions{},
+		"Expected Name, found EOF")
+}
+
+func TestParse_ParsesDescriptionsAndDirectivesOnTypeSystemElements(T *testing.T) {
+	tree, err := Parse(NewSource(`
+"Type" type T implements & I & J @a {
+  "Field" f("Arg" x: Int @b): Int @c
+}
+"Enum" enum E @d { "Value" V @e }`, ""), ParseOptions{NoLocation: true})
+	if err != nil {
+		T.Error(err)
+		return
+	}
+
+	object := tree.Definitions[0].(*ObjectTypeDefinition)
+	deepEqual(T, object.Description, &StringValue{Value: "Type"})
+	expect(T, len(object.Interfaces) == 2, "Expect 2 interfaces but got %v", len(object.Interfaces))
+	deepEqual(T, object.Directives, []*Directive{{Name: &Name{Value: "a"}}})
+	field := object.Fields[0]
+	deepEqual(T, field.Description, &StringValue{Value: "Field"})
+	deepEqual(T, field.Directives, []*Directive{{Name: &Name{Value: "c"}}})
+	deepEqual(T, field.Arguments[0].Description, &StringValue{Value: "Arg"})
+	deepEqual(T, field.Arguments[0].Directives, []*Directive{{Name: &Name{Value: "b"}}})
+
+	enum := tree.Definitions[1].(*EnumTypeDefinition)
+	deepEqual(T, enum.Description, &StringValue{Value: "Enum"})
+	deepEqual(T, enum.Directives, []*Directive{{Name: &Name{Value: "d"}}})
+	deepEqual(T, enum.Values[0], &EnumValueDefinition{
+		Description: &StringValue{Value: "Value"},
+		Name:        &Name{Value: "V"},
+		Directives:  []*Directive{{Name: &Name{Value: "e"}}},
+	})
+}
+
+func TestParse_ParsesBlockStrings(T *testing.T) {
+	tree, err := Parse(NewSource(`
+"""
+  Description
+    indented
+"""
+type T {
+  f(a: String = """default"""): String
+}`, ""), ParseOptions{NoLocation: true})
+	if err != nil {
+		T.Error(err)
+		return
+	}
+
+	object := tree.Definitions[0].(*ObjectTypeDefinition)
+	deepEqual(T, object.Description, &StringValue{Value: "Description\n  indented", Block: true})
+	deepEqual(T, object.Fields[0].Arguments[0].DefaultValue, &StringValue{Value: "default", Block: true})
+}
+
+func TestPrint_PrintsBlockStrings(T *testing.T) {
+	tests := []struct {
+		value, printed string
+	}{
+		{"short", `"""short"""`},
+		{"first\nsecond", "\"\"\"\nfirst\nsecond\n\"\"\""},
+		{"  leading space", `"""  leading space"""`},
+		{"first\n  indented", "\"\"\"\nfirst\n  indented\n\"\"\""},
+		{`ends with "`, "\"\"\"\nends with \"\n\"\"\""},
+		{`contains """`, "\"\"\"\ncontains \\\"\"\"\n\"\"\""},
+	}
+	for _, test := range tests {
+		printed := Print(&StringValue{Value: test.value, Block: true})
+		expect(T, printed == test.printed, "Expect %q to be printed as:\n%v\nbut got:\n%v", test.value, test.printed, printed)
+		expect(T, lexOne(printed).Value == test.value, "Expect %v to be read as %q", printed, test.value)
+	}
+}
+
+func TestParse_ParsesTypeExtensions(T *testing.T) {
+	tree, err := Parse(NewSource(`
+extend scalar S @a
+extend interface I implements J { f: Int }
+extend union U @b = A | B
+extend enum E { V }
+extend input In @c`, ""), ParseOptions{NoLocation: true})
+	if err != nil {
+		T.Error(err)
+		return
+	}
+
+	deepEqual(T, tree.Definitions[0], &ScalarTypeExtension{
+		Name:       &Name{Value: "S"},
+		Directives: []*Directive{{Name: &Name{Value: "a"}}},
+	})
+	deepEqual(T, tree.Definitions[1], &InterfaceTypeExtension{
+		Name:       &Name{Value: "I"},
+		Interfaces: []*NamedType{{Name: &Name{Value: "J"}}},
+		Directives: []*Directive{},
+		Fields: []*FieldDefinition{{
+			Name:       &Name{Value: "f"},
+			Type:       &NamedType{Name: &Name{Value: "Int"}},
+			Directives: []*Directive{},
+		}},
+	})
+	deepEqual(T, tree.Definitions[2], &UnionTypeExtension{
+		Name:       &Name{Value: "U"},
+		Directives: []*Directive{{Name: &Name{Value: "b"}}},
+		Types: []*NamedType{
+			{Name: &Name{Value: "A"}},
+			{Name: &Name{Value: "B"}},
+		},
+	})
+	deepEqual(T, tree.Definitions[3], &EnumTypeExtension{
+		Name:       &Name{Value: "E"},
+		Directives: []*Directive{},
+		Values: []*EnumValueDefinition{{
+			Name:       &Name{Value: "V"},
+			Directives: []*Directive{},
+		}},
+	})
+	deepEqual(T, tree.Definitions[4], &InputObjectTypeExtension{
+		Name:       &Name{Value: "In"},
+		Directives: []*Directive{{Name: &Name{Value: "c"}}},
+		Fields:     []*InputValueDefinition{},
+	})
+}
+
+func TestParse_RejectsEmptyTypeExtensions(T *testing.T) {
+	for _, source := range []string{
+		`extend type T`,
+		`extend scalar S`,
+		`extend interface I`,
+		`extend union U`,
+		`extend enum E`,
+		`extend input In {}`,
+	} {
+		expectParseError(T, source, ParseOptions{}, "Unexpected")
+	}
+	expectParseError(T, `extend directive @a on FIELD`, ParseOptions{},
+		"Unexpected Name directive")
+}
+
+func TestParse_ParsesInterfacesImplementingInterfaces(T *testing.T) {
+	tree, err := Parse(NewSource(`interface Document implements Node & Resource { id: ID }`, ""),
+		ParseOptions{NoLocation: true})
+	if err != nil {
+		T.Error(err)
+		return
+	}
+	deepEqual(T, tree.Definitions[0], &InterfaceTypeDefinition{
+		Name: &Name{Value: "Document"},
+		Interfaces: []*NamedType{
+			{Name: &Name{Value: "Node"}},
+			{Name: &Name{Value: "Resource"}},
+		},
+		Directives: []*Directive{},
+		Fields: []*FieldDefinition{{
+			Name:       &Name{Value: "id"},
+			Type:       &NamedType{Name: &Name{Value: "ID"}},
+			Directives: []*Directive{},
+		}},
+	})
+	expect(T, Print(tree) == "interface Document implements Node & Resource {\n    id: ID\n}\n",
+		"Unexpected print: %v", Print(tree))
+}
+
+func expectRecoveredParse(T *testing.T, source string, printed string, messages ...string) {
+	doc, errs := ParseWithRecovery(NewSource(source, ""), ParseOptions{NoLocation: true})
+	if len(errs) != len(messages) {
+		T.Errorf("Expect %v errors but got:\n%v---", len(messages), errs)
+		return
+	}
+	for i, msg := range messages {
+		if !strings.Contains(errs[i].Error(), msg) {
+			T.Errorf("Expect error with message:\n%v---\nbut got:\n%v---", msg, errs[i])
+		}
+	}
+	if doc == nil {
+		T.Errorf("Expect a partial document")
+		return
+	}
+	expect(T, Print(doc) == printed, "Unexpected print of `%v`:\n%v---", source, Print(doc))
+}
+
+func TestParseWithRecovery_ParsesValidDocument(T *testing.T) {
+	expectRecoveredParse(T, `{ a b }`, "{\n    a\n    b\n}\n")
+}
+
+func TestParseWithRecovery_ResumesAtNextSelection(T *testing.T) {
+	expectRecoveredParse(T, `{ a(x: ) b { c(y: {z: }) d } }`,
+		"{\n    b {\n        d\n    }\n}\n",
+		"(1:7) Unexpected )",
+		"(1:22) Unexpected }")
+	expectRecoveredParse(T, `{ a { b ) } c }`,
+		"{\n    a {\n        b\n    }\n    c\n}\n",
+		"(1:8) Expected Name, found )")
+}
+
+func TestParseWithRecovery_ResumesAtNextDefinition(T *testing.T) {
+	expectRecoveredParse(T, `query Q($a: ) { a } fragment F on T { b }`,
+		"fragment F on T {\n    b\n}\n",
+		"(1:12) Expected Name, found )")
+	expectRecoveredParse(T, "type T { a: [Int }\ntype U { b: Int }",
+		"type U {\n    b: Int\n}\n",
+		"(1:17) Expected ], found }")
+	expectRecoveredParse(T, "query A { a(x: [1 }\n{ b }",
+		"{\n    b\n}\n",
+		"(1:18) Unexpected }")
+}
+
+func TestParseWithRecovery_ResumesInNestedSelections(T *testing.T) {
+	expectRecoveredParse(T, `{ a { b { c } d ( } e } query Q { x }`,
+		"{\n    a {\n        b {\n            c\n        }\n    }\n    e\n}\n\nquery Q {\n    x\n}\n",
+		"(1:18) Expected Name, found }")
+	expectRecoveredParse(T, `{ a { b ... on T { ( } c } d }`,
+		"{\n    a {\n        b\n        c\n    }\n    d\n}\n",
+		"(1:19) Expected Name, found (")
+}
+
+func TestParseWithRecovery_ResumesAtDefinitionsInUnclosedBraces(T *testing.T) {
+	expectRecoveredParse(T, `{ a( } query { b { c } fragment F on { x } ...`, "\n",
+		"(1:5) Expected Name, found }",
+		"(1:13) Expected Name, found {",
+		"(1:37) Expected Name, found {")
+	expectRecoveredParse(T, `{ a { b } query Q { c { d } fragment F on T { e }`,
+		"{\n    a {\n        b\n    }\n}\n\nquery Q {\n    c {\n        d\n    }\n}\n\nfragment F on T {\n    e\n}\n",
+		"(1:10) Expected }, found Name query",
+		"(1:28) Expected }, found Name fragment")
+	expectRecoveredParse(T, "query Q { a { b\ntype T { c: Int }\nscalar S",
+		"query Q {\n    a {\n        b\n    }\n}\n\ntype T {\n    c: Int\n}\n\nscalar S\n",
+		"(2:1) Expected }, found Name type")
+
+	// Fields named like keywords are still selections when the braces match
+	expectRecoveredParse(T, `{ node { type owner { name } query { a } fragment } }`,
+		"{\n    node {\n        type\n        owner {\n            name\n        }\n        query {\n            a\n        }\n        fragment\n    }\n}\n")
+}
+
+func TestParseWithRecovery_LeavesOutEmptySelectionSets(T *testing.T) {
+	expectRecoveredParse(T, `{ a { ( } b ... on T { ) } c } fragment F on T { ) } query Q { ( }`,
+		"{\n    a\n    b\n    c\n}\n",
+		"(1:6) Expected Name, found (",
+		"(1:23) Expected Name, found )",
+		"(1:49) Expected Name, found )",
+		"(1:63) Expected Name, found (")
+}
+
+func TestParseWithRecovery_SkipsInvalidCharacters(T *testing.T) {
+	expectRecoveredParse(T, "{ a ? b \"\\q\" c \"d\n e }",
+		"{\n    a\n    b\n    c\n    e\n}\n",
+		`(1:4) Unexpected character "?".`,
+		"(1:10) Bad character escape sequence.",
+		"(1:17) Unterminated string.")
+}
+
+func TestParseWithRecovery_ReportsEndOfSourceOnce(T *testing.T) {
+	expectRecoveredParse(T, `{ a { b { c`, "{\n    a {\n        b {\n            c\n        }\n    }\n}\n",
+		"(1:12) Expected Name, found EOF")
+	expectRecoveredParse(T, ``, "\n", "(1:1) Unexpected EOF")
+}
+
+func TestParseWithRecovery_StopsAtLimits(T *testing.T) {
+	doc, errs := ParseWithRecovery(NewSource(`{ a(x: ) b c d }`, ""), ParseOptions{MaxTokens: 6})
+	expect(T, doc == nil, "Expect no document")
+	expect(T, len(errs) == 2 && strings.Contains(errs[1].Error(), "more than 6 tokens"),
+		"Unexpected errors: %v", errs)
+}
+
+func TestParse_KeepsComments(T *testing.T) {
+	tree, err := Parse(NewSource(`
+# first
+{ # open
+  a # after a
+  # last
+}
+# end`, ""), ParseOptions{Comments: true})
+	if err != nil {
+		T.Error(err)
+		return
+	}
+
+	operation := tree.Definitions[0].(*OperationDefinition)
+	field := operation.SelectionSet.Selections[0].(*Field)
+	deepEqual(T, operation.Location.Comments, &Comments{Before: []string{" first"}})
+	deepEqual(T, field.Location.Comments, &Comments{Before: []string{" open"}, After: " after a"})
+	deepEqual(T, operation.SelectionSet.Location.Comments, &Comments{Inner: []string{" last"}})
+	deepEqual(T, tree.Location.Comments, &Comments{Inner: []string{" end"}})
+
+	tree, _ = Parse(NewSource("# first\n{ a }", ""))
+	expect(T, tree.Definitions[0].Loc().Comments == nil, "Expect no comments by default")
+}
+
+func TestPrint_PrintsComments(T *testing.T) {
+	source := `# The user query
+query User($id: ID) {
+    # the name
+    name # trailing name
+    friends(first: 10) {
+        # the id
+        id # id
+    } # after friends
+    ...F # spread
+    # end of selection
+}
+
+# before the type
+"description"
+type T implements I {
+    a(
+        # about x
+        x: Int # x trailing
+        y: Int
+    ): Int # a trailing
+    # inner T
+}
+
+enum E {
+    A # a
+    B
+}
+
+# the end
+`
+	tree, err := Parse(NewSource(source, ""), ParseOptions{Comments: true})
+	if err != nil {
+		T.Error(err)
+		return
+	}
+	printed := Print(tree, PrintOptions{Comments: true})
+	expect(T, printed == source, "Expect printing to keep the comments:\n%v---", printed)
+
+	expect(T, !strings.Contains(Print(tree), "#"), "Expect comments to be printed only on demand")
+}
+
+func TestPrint_PrintsCommentsOfArgumentsAndDescriptions(T *testing.T) {
+	for _, source := range []string{
+		"query Q(\n    # var comment\n    $a: Int # trailing var\n) {\n    a\n}\n",
+		"{\n    b(\n        x: 1 # c\n    ) @d(\n        y: 2 # e\n    )\n}\n",
+		"# before\n\"desc\" # after desc\n# between\ntype T {\n    \"d\" # after d\n    a(\n        \"x\" # after x\n        x: Int\n    ): Int\n}\n",
+	} {
+		tree, err := Parse(NewSource(source, ""), ParseOptions{Comments: true})
+		if err != nil {
+			T.Error(err)
+			continue
+		}
+		printed := Print(tree, PrintOptions{Comments: true})
+		expect(T, printed == source, "Expect printing to keep the comments:\n%v---", printed)
+	}
+
+	tree, _ := Parse(NewSource("query Q(\n  $a: Int # a\n) { b(x: 1 # x\n) }", ""), ParseOptions{Comments: true})
+	operation := tree.Definitions[0].(*OperationDefinition)
+	field := operation.SelectionSet.Selections[0].(*Field)
+	deepEqual(T, operation.VariableDefinitions[0].Location.Comments, &Comments{After: " a"})
+	deepEqual(T, field.Arguments[0].Location.Comments, &Comments{After: " x"})
+	expect(T, operation.Location.Comments == nil && field.Location.Comments == nil,
+		"Expect the comments on the variable definition and the argument only")
+}
@@ -4,23 +4,76 @@ import (
 	"bytes"
 	"encoding/json"
 	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
 )
 
 const INDENT_CHAR = "    "
 
-func Print(ast INode) string {
-	visitor := &printASTVisitor{}
+type PrintOptions struct {
+	// Comments prints the comments kept by ParseOptions.Comments.
+	Comments bool
+
+	// IndentWidth is the number of spaces per indentation level. Defaults
+	// to 4.
+	IndentWidth int
+
+	// MaxLineLength wraps the arguments of fields, the variable definitions
+	// and the argument definitions one per line when the line up to their
+	// closing parenthesis is longer than this. Zero means no limit.
+	MaxLineLength int
+
+	// Minify prints the smallest document which parses to the same AST,
+	// without comments, indentation or unneeded spaces. Block strings are
+	// printed as strings.
+	Minify bool
+
+	// SortFields sorts the selections of selection sets, and the fields of
+	// type and input definitions, by name.
+	SortFields bool
+
+	// SortArguments sorts the arguments of fields and directives, and the
+	// argument definitions, by name.
+	SortArguments bool
+}
+
+func Print(ast INode, options ...PrintOptions) string {
+	visitor := &printASTVisitor{indentUnit: INDENT_CHAR}
+	if len(options) > 0 {
+		visitor.options = options[0]
+	}
+	if visitor.options.IndentWidth > 0 {
+		visitor.indentUnit = strings.Repeat(" ", visitor.options.IndentWidth)
+	}
+	if visitor.options.Minify {
+		visitor.options.Comments = false
+		visitor.options.MaxLineLength = 0
+	}
 	visitor.visit(ast)
+	if visitor.options.Minify {
+		return minify(visitor.buf.String())
+	}
 	return visitor.buf.String()
 }
 
 type printASTVisitor struct {
-	buf bytes.Buffer
+	buf     bytes.Buffer
+	options PrintOptions
 
+	indentUnit  string
 	indentLevel string
-	wrapStart   []string
-	wrapEnd     []string
-	pendingEnd  string
+	wraps       []_Wrap
+}
+
+/**
+ * Wraps the content written between wrapOpen and wrapClose with start and
+ * end, which are only written if the content is not empty.
+ */
+type _Wrap struct {
+	start  string
+	end    string
+	opened bool
 }
 
 func (p *printASTVisitor) visit(node INode) {
@@ -28,6 +81,14 @@ func (p *printASTVisitor) visit(node INode) {
 		return
 	}
 
+	comments := p.comments(node)
+	if comments != nil {
+		for _, text := range comments.Before {
+			p.write("#" + text)
+			p.newline()
+		}
+	}
+
 	switch node := node.(type) {
 	case *Name:
 		p.write(node.Value)
@@ -43,6 +104,10 @@ func (p *printASTVisitor) visit(node INode) {
 			p.writeIp(i, "\n\n")
 			p.visit(def)
 		}
+		if p.hasInnerComments(node) {
+			p.write("\n")
+			p.innerComments(node, len(node.Definitions))
+		}
 		p.write("\n")
 
 	case *OperationDefinition:
@@ -53,12 +118,16 @@ func (p *printASTVisitor) visit(node INode) {
 		p.write(string(node.Operation))
 		p.write(" ")
 		p.visit(node.Name)
-		p.wrapOpen("(", ")")
-		for i, def := range node.VariableDefinitions {
-			p.writeIp(i, ", ")
-			p.visit(def)
+		defs := node.VariableDefinitions
+		multiline := false
+		for _, def := range defs {
+			if p.comments(def) != nil {
+				multiline = true
+			}
 		}
-		p.wrapClose()
+		p.parenthesized(len(defs), ", ", multiline, func(i int) {
+			p.visit(defs[i])
+		})
 		for _, directive := range node.Directives {
 			p.write(" ")
 			p.visit(directive)
@@ -77,10 +146,11 @@ func (p *printASTVisitor) visit(node INode) {
 
 	case *SelectionSet:
 		p.blockOpen()
-		for i, selection := range node.Selections {
+		for i, selection := range p.sortedSelections(node.Selections) {
 			p.blockLine(i)
 			p.visit(selection)
 		}
+		p.innerComments(node, len(node.Selections))
 		p.blockClose()
 
 	case *Field:
@@ -88,19 +158,19 @@ func (p *printASTVisitor) visit(node INode) {
 		p.writeIf(node.Alias != nil, ": ")
 		p.visit(node.Name)
 
-		p.writeIp(len(node.Arguments), "(")
-		for i, arg := range node.Arguments {
-			p.writeIp(i, ",")
-			p.visit(arg)
-		}
-		p.writeIp(len(node.Arguments), ")")
+		args := p.sortedArguments(node.Arguments)
+		p.parenthesized(len(args), ", ", p.hasCommentedArguments(args), func(i int) {
+			p.visit(args[i])
+		})
 
 		for _, directive := range node.Directives {
 			p.write(" ")
 			p.visit(directive)
 		}
-		p.write(" ")
-		p.visit(node.SelectionSet)
+		if node.SelectionSet != nil {
+			p.write(" ")
+			p.visit(node.SelectionSet)
+		}
 
 	case *Argument:
 		p.visit(node.Name)
@@ -148,6 +218,10 @@ func (p *printASTVisitor) visit(node INode) {
 		p.write(node.Value)
 
 	case *StringValue:
+		if node.Block {
+			p.writeLines(printBlockString(node.Value))
+			break
+		}
 		data, err := json.Marshal(node.Value)
 		if err != nil {
 			panic(err)
@@ -157,6 +231,9 @@ func (p *printASTVisitor) visit(node INode) {
 	case *BooleanValue:
 		p.write(node.Value)
 
+	case *NullValue:
+		p.write("null")
+
 	case *EnumValue:
 		p.write(node.Value)
 
@@ -186,12 +263,19 @@ func (p *printASTVisitor) visit(node INode) {
 	case *Directive:
 		p.write("@")
 		p.visit(node.Name)
-		p.writeIp(len(node.Arguments), "(")
-		for i, arg := range node.Arguments {
+		args := p.sortedArguments(node.Arguments)
+		if p.hasCommentedArguments(args) {
+			p.parenthesized(len(args), ", ", true, func(i int) {
+				p.visit(args[i])
+			})
+			break
+		}
+		p.writeIp(len(args), "(")
+		for i, arg := range args {
 			p.writeIp(i, ", ")
 			p.visit(arg)
 		}
-		p.write(")")
+		p.writeIp(len(args), ")")
 
 	// IType
 
@@ -207,37 +291,70 @@ func (p *printASTVisitor) visit(node INode) {
 		p.visit(node.Type)
 		p.write("!")
 
-	// IType Definitions
+	// Type System Definitions
 
-	case *ObjectTypeDefinition:
-		p.write("type ")
-		p.visit(node.Name)
-		p.write(" ")
-		p.writeIp(len(node.Interfaces), "implements ")
-		for i, iface := range node.Interfaces {
-			p.writeIp(i, ", ")
-			p.visit(iface)
-		}
+	case *SchemaDefinition:
+		p.description(node.Description)
+		p.write("schema")
+		p.directives(node.Directives)
 		p.write(" ")
 		p.blockOpen()
-		for i, field := range node.Fields {
+		for i, operationType := range node.OperationTypes {
 			p.blockLine(i)
-			p.visit(field)
+			p.visit(operationType)
 		}
+		p.innerComments(node, len(node.OperationTypes))
 		p.blockClose()
 
-	case *FieldDefinition:
+	case *OperationTypeDefinition:
+		p.write(string(node.Operation))
+		p.write(": ")
+		p.visit(node.Type)
+
+	case *DirectiveDefinition:
+		p.description(node.Description)
+		p.write("directive @")
 		p.visit(node.Name)
-		p.writeIp(len(node.Arguments), "(")
-		for i, arg := range node.Arguments {
-			p.writeIp(i, ", ")
-			p.visit(arg)
+		p.argumentDefs(node.Arguments)
+		p.writeIf(node.Repeatable, " repeatable")
+		p.write(" on ")
+		for i, location := range node.Locations {
+			p.writeIp(i, " | ")
+			p.visit(location)
 		}
-		p.writeIp(len(node.Arguments), ")")
+
+	case *SchemaExtension:
+		p.write("extend schema")
+		p.directives(node.Directives)
+		if len(node.OperationTypes) > 0 {
+			p.write(" ")
+			p.blockOpen()
+			for i, operationType := range node.OperationTypes {
+				p.blockLine(i)
+				p.visit(operationType)
+			}
+			p.innerComments(node, len(node.OperationTypes))
+			p.blockClose()
+		}
+
+	case *ObjectTypeDefinition:
+		p.description(node.Description)
+		p.write("type ")
+		p.visit(node.Name)
+		p.implements(node.Interfaces)
+		p.directives(node.Directives)
+		p.fieldDefs(node, node.Fields)
+
+	case *FieldDefinition:
+		p.description(node.Description)
+		p.visit(node.Name)
+		p.argumentDefs(node.Arguments)
 		p.write(": ")
 		p.visit(node.Type)
+		p.directives(node.Directives)
 
 	case *InputValueDefinition:
+		p.description(node.Description)
 		p.visit(node.Name)
 		p.write(": ")
 		p.visit(node.Type)
@@ -245,77 +362,342 @@ func (p *printASTVisitor) visit(node INode) {
 		p.wrapOpen(" = ", "")
 		p.visit(node.DefaultValue)
 		p.wrapClose()
+		p.directives(node.Directives)
 
 	case *InterfaceTypeDefinition:
+		p.description(node.Description)
 		p.write("interface ")
 		p.visit(node.Name)
-		p.write(" ")
-		p.blockOpen()
-		for i, field := range node.Fields {
-			p.blockLine(i)
-			p.visit(field)
-		}
-		p.blockClose()
+		p.implements(node.Interfaces)
+		p.directives(node.Directives)
+		p.fieldDefs(node, node.Fields)
 
 	case *UnionTypeDefinition:
+		p.description(node.Description)
 		p.write("union ")
 		p.visit(node.Name)
-		p.write(" = ")
+		p.directives(node.Directives)
+		p.writeIp(len(node.Types), " = ")
 		for i, typ := range node.Types {
 			p.writeIp(i, " | ")
 			p.visit(typ)
 		}
 
 	case *ScalarTypeDefinition:
+		p.description(node.Description)
 		p.write("scalar ")
 		p.visit(node.Name)
+		p.directives(node.Directives)
 
 	case *EnumTypeDefinition:
+		p.description(node.Description)
 		p.write("enum ")
 		p.visit(node.Name)
-		p.write(" ")
-		p.blockOpen()
-		for i, value := range node.Values {
-			p.blockLine(i)
-			p.visit(value)
-		}
-		p.blockClose()
+		p.directives(node.Directives)
+		p.enumValueDefs(node, node.Values)
 
 	case *EnumValueDefinition:
+		p.description(node.Description)
 		p.visit(node.Name)
+		p.directives(node.Directives)
 
 	case *InputObjectTypeDefinition:
+		p.description(node.Description)
 		p.write("input ")
 		p.visit(node.Name)
-		p.write(" ")
-		p.blockOpen()
-		for i, field := range node.Fields {
-			p.blockLine(i)
-			p.visit(field)
-		}
-		p.blockClose()
+		p.directives(node.Directives)
+		p.inputFieldDefs(node, node.Fields)
 
 	case *TypeExtensionDefinition:
 		p.write("extend ")
 		p.visit(node.Definition)
+
+	case *ScalarTypeExtension:
+		p.write("extend scalar ")
+		p.visit(node.Name)
+		p.directives(node.Directives)
+
+	case *InterfaceTypeExtension:
+		p.write("extend interface ")
+		p.visit(node.Name)
+		p.implements(node.Interfaces)
+		p.directives(node.Directives)
+		p.fieldDefs(node, node.Fields)
+
+	case *UnionTypeExtension:
+		p.write("extend union ")
+		p.visit(node.Name)
+		p.directives(node.Directives)
+		p.writeIp(len(node.Types), " = ")
+		for i, typ := range node.Types {
+			p.writeIp(i, " | ")
+			p.visit(typ)
+		}
+
+	case *EnumTypeExtension:
+		p.write("extend enum ")
+		p.visit(node.Name)
+		p.directives(node.Directives)
+		p.enumValueDefs(node, node.Values)
+
+	case *InputObjectTypeExtension:
+		p.write("extend input ")
+		p.visit(node.Name)
+		p.directives(node.Directives)
+		p.inputFieldDefs(node, node.Fields)
+	}
+
+	if comments != nil && comments.After != "" {
+		p.write(" #" + comments.After)
 	}
 }
 
-func (p *printASTVisitor) write(s string) {
-	if s != "" && len(p.wrapStart) > 0 {
-		if len(p.wrapStart) != len(p.wrapEnd) {
-			panic("unexpected different length")
+/**
+ * Returns the comments of node to print, if any.
+ */
+func (p *printASTVisitor) comments(node INode) *Comments {
+	if !p.options.Comments {
+		return nil
+	}
+	if loc := node.Loc(); loc != nil {
+		return loc.Comments
+	}
+	return nil
+}
+
+/**
+ * Writes the comments before the closing brace of the block of node, after
+ * its count elements.
+ */
+func (p *printASTVisitor) innerComments(node INode, count int) {
+	comments := p.comments(node)
+	if comments == nil {
+		return
+	}
+	for i, text := range comments.Inner {
+		p.blockLine(count + i)
+		p.write("#" + text)
+	}
+}
+
+func (p *printASTVisitor) hasInnerComments(node INode) bool {
+	comments := p.comments(node)
+	return comments != nil && len(comments.Inner) > 0
+}
+
+func (p *printASTVisitor) hasCommentedArguments(args []*Argument) bool {
+	for _, arg := range args {
+		if p.comments(arg) != nil {
+			return true
 		}
-		for _, start := range p.wrapStart {
-			_, err := p.buf.WriteString(start)
-			if err != nil {
-				panic(err)
-			}
+	}
+	return false
+}
+
+/**
+ * Writes the description of a type system definition on its own line,
+ * followed by the comments between it and its definition.
+ */
+func (p *printASTVisitor) description(node *StringValue) {
+	if node == nil {
+		return
+	}
+	p.visit(node)
+	p.newline()
+	if comments := p.comments(node); comments != nil {
+		for _, text := range comments.Inner {
+			p.write("#" + text)
+			p.newline()
 		}
-		for i := len(p.wrapEnd) - 1; i >= 0; i-- {
-			p.pendingEnd += p.wrapEnd[i]
+	}
+}
+
+func (p *printASTVisitor) implements(interfaces []*NamedType) {
+	p.writeIp(len(interfaces), " implements ")
+	for i, iface := range interfaces {
+		p.writeIp(i, " & ")
+		p.visit(iface)
+	}
+}
+
+func (p *printASTVisitor) directives(directives []*Directive) {
+	for _, directive := range directives {
+		p.write(" ")
+		p.visit(directive)
+	}
+}
+
+func (p *printASTVisitor) fieldDefs(node INode, fields []*FieldDefinition) {
+	if len(fields) == 0 && !p.hasInnerComments(node) {
+		return
+	}
+	if p.options.SortFields {
+		fields = append([]*FieldDefinition(nil), fields...)
+		sort.SliceStable(fields, func(i, j int) bool {
+			return fields[i].Name.Value < fields[j].Name.Value
+		})
+	}
+	p.write(" ")
+	p.blockOpen()
+	for i, field := range fields {
+		p.blockLine(i)
+		p.visit(field)
+	}
+	p.innerComments(node, len(fields))
+	p.blockClose()
+}
+
+func (p *printASTVisitor) enumValueDefs(node INode, values []*EnumValueDefinition) {
+	if len(values) == 0 && !p.hasInnerComments(node) {
+		return
+	}
+	p.write(" ")
+	p.blockOpen()
+	for i, value := range values {
+		p.blockLine(i)
+		p.visit(value)
+	}
+	p.innerComments(node, len(values))
+	p.blockClose()
+}
+
+func (p *printASTVisitor) inputFieldDefs(node INode, fields []*InputValueDefinition) {
+	if len(fields) == 0 && !p.hasInnerComments(node) {
+		return
+	}
+	if p.options.SortFields {
+		fields = sortInputValues(fields)
+	}
+	p.write(" ")
+	p.blockOpen()
+	for i, field := range fields {
+		p.blockLine(i)
+		p.visit(field)
+	}
+	p.innerComments(node, len(fields))
+	p.blockClose()
+}
+
+/**
+ * Writes the arguments on one line, or one per line when any of them has a
+ * description or comments.
+ */
+func (p *printASTVisitor) argumentDefs(args []*InputValueDefinition) {
+	multiline := false
+	for _, arg := range args {
+		if arg.Description != nil || p.comments(arg) != nil {
+			multiline = true
+		}
+	}
+	if p.options.SortArguments {
+		args = sortInputValues(args)
+	}
+	p.parenthesized(len(args), ", ", multiline, func(i int) {
+		p.visit(args[i])
+	})
+}
+
+/**
+ * Writes count elements between parentheses, separated by separator on one
+ * line, or one per line when multiline is set or when the line would be
+ * longer than MaxLineLength. Nothing is written when count is zero.
+ */
+func (p *printASTVisitor) parenthesized(count int, separator string, multiline bool, visitElement func(i int)) {
+	if count == 0 {
+		return
+	}
+	p.write("(")
+	if !multiline {
+		mark := p.buf.Len()
+		for i := 0; i < count; i++ {
+			p.writeIp(i, separator)
+			visitElement(i)
+		}
+		p.write(")")
+		max := p.options.MaxLineLength
+		if max <= 0 || p.lineLength() <= max {
+			return
+		}
+		p.buf.Truncate(mark)
+	}
+	p.indent()
+	for i := 0; i < count; i++ {
+		p.blockLine(i)
+		visitElement(i)
+	}
+	p.outdent()
+	p.write(")")
+}
+
+/**
+ * Returns the number of characters written on the current line.
+ */
+func (p *printASTVisitor) lineLength() int {
+	buf := p.buf.Bytes()
+	return utf8.RuneCount(buf[bytes.LastIndexByte(buf, '\n')+1:])
+}
+
+/**
+ * Returns the selections sorted when SortFields is set: fields by name then
+ * alias, followed by fragment spreads by name, then inline fragments in
+ * their original order.
+ */
+func (p *printASTVisitor) sortedSelections(selections []ISelection) []ISelection {
+	if !p.options.SortFields {
+		return selections
+	}
+	sorted := append([]ISelection(nil), selections...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		rankI, keyI := selectionSortKey(sorted[i])
+		rankJ, keyJ := selectionSortKey(sorted[j])
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		return keyI < keyJ
+	})
+	return sorted
+}
+
+func selectionSortKey(selection ISelection) (int, string) {
+	switch selection := selection.(type) {
+	case *Field:
+		if selection.Alias != nil {
+			return 0, selection.Name.Value + " " + selection.Alias.Value
+		}
+		return 0, selection.Name.Value
+	case *FragmentSpread:
+		return 1, selection.Name.Value
+	}
+	return 2, ""
+}
+
+func (p *printASTVisitor) sortedArguments(args []*Argument) []*Argument {
+	if !p.options.SortArguments {
+		return args
+	}
+	sorted := append([]*Argument(nil), args...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name.Value < sorted[j].Name.Value
+	})
+	return sorted
+}
+
+func sortInputValues(values []*InputValueDefinition) []*InputValueDefinition {
+	sorted := append([]*InputValueDefinition(nil), values...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name.Value < sorted[j].Name.Value
+	})
+	return sorted
+}
+
+func (p *printASTVisitor) write(s string) {
+	if s != "" {
+		for i := range p.wraps {
+			if !p.wraps[i].opened {
+				p.wraps[i].opened = true
+				p.buf.WriteString(p.wraps[i].start)
+			}
 		}
-		p.wrapStart = p.wrapStart[:0]
 	}
 	_, err := p.buf.WriteString(s)
 	if err != nil {
@@ -323,6 +705,23 @@ func (p *printASTVisitor) write(s string) {
 	}
 }
 
+/**
+ * Writes the lines of s at the current indentation. Empty lines are not
+ * indented.
+ */
+func (p *printASTVisitor) writeLines(s string) {
+	for i, line := range splitLines(s) {
+		if i > 0 {
+			if line == "" {
+				p.write("\n")
+				continue
+			}
+			p.newline()
+		}
+		p.write(line)
+	}
+}
+
 func (p *printASTVisitor) writeIf(cond bool, s string) {
 	if cond {
 		p.write(s)
@@ -341,7 +740,7 @@ func (p *printASTVisitor) newline() {
 }
 
 func (p *printASTVisitor) indent() {
-	p.indentLevel += INDENT_CHAR
+	p.indentLevel += p.indentUnit
 	p.newline()
 }
 
@@ -350,7 +749,7 @@ func (p *printASTVisitor) outdent() {
 	if l == 0 {
 		panic("unexpected outdent")
 	}
-	p.indentLevel = p.indentLevel[:l-len(INDENT_CHAR)]
+	p.indentLevel = p.indentLevel[:l-len(p.indentUnit)]
 	p.newline()
 }
 
@@ -371,17 +770,61 @@ func (p *printASTVisitor) blockLine(i int) {
 }
 
 func (p *printASTVisitor) wrapOpen(start, end string) {
-	p.wrapStart = append(p.wrapStart, start)
-	p.wrapEnd = append(p.wrapEnd, end)
+	p.wraps = append(p.wraps, _Wrap{start: start, end: end})
 }
 
 func (p *printASTVisitor) wrapClose() {
-	l := len(p.wrapEnd)
-	if l > 0 {
-		if len(p.wrapStart) != l {
-			panic("unexpected different length")
+	l := len(p.wraps)
+	if l == 0 {
+		panic("unexpected wrapClose")
+	}
+	wrap := p.wraps[l-1]
+	p.wraps = p.wraps[:l-1]
+	if wrap.opened {
+		p.buf.WriteString(wrap.end)
+	}
+}
+
+/**
+ * Rewrites the printed document with only the spaces needed to separate its
+ * tokens. Block strings are rewritten as strings.
+ */
+func minify(printed string) string {
+	var buf bytes.Buffer
+	lexer := newLexer(NewSource(printed, ""))
+	prev := TOKEN_EOF
+	for {
+		token := lexer.nextToken()
+		if token.Kind == TOKEN_EOF {
+			break
+		}
+		if needsSpace(prev, token.Kind) {
+			buf.WriteByte(' ')
+		}
+		if token.Kind == TOKEN_BLOCK_STRING {
+			data, err := json.Marshal(token.Value)
+			if err != nil {
+				panic(err)
+			}
+			buf.Write(data)
+		} else {
+			buf.WriteString(printed[token.Start:token.End])
 		}
-		p.wrapEnd = p.wrapEnd[:l-1]
-		p.wrapStart = p.wrapStart[:l-1]
+		prev = token.Kind
+	}
+	return buf.String()
+}
+
+/**
+ * Reports whether two adjacent tokens would be lexed as one token without a
+ * space between them.
+ */
+func needsSpace(prev, next TokenKind) bool {
+	isWord := func(kind TokenKind) bool {
+		return kind == TOKEN_NAME || kind == TOKEN_INT || kind == TOKEN_FLOAT
+	}
+	isString := func(kind TokenKind) bool {
+		return kind == TOKEN_STRING || kind == TOKEN_BLOCK_STRING
 	}
+	return isWord(prev) && isWord(next) || isString(prev) && isString(next)
 }
@@ -0,0 +1,182 @@
+package language
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func parseForPrint(T *testing.T, source string) *Document {
+	tree, err := Parse(NewSource(source, ""), ParseOptions{NoLocation: true})
+	if err != nil {
+		T.Fatal(err)
+	}
+	return tree
+}
+
+func TestPrint_RoundTripsWithOptions(T *testing.T) {
+	tests := []PrintOptions{
+		{IndentWidth: 2},
+		{MaxLineLength: 40},
+		{MaxLineLength: 1, IndentWidth: 1},
+		{Minify: true},
+		{SortFields: true, SortArguments: true},
+		{Minify: true, SortFields: true, SortArguments: true},
+	}
+	for _, filename := range []string{"kitchen-sink.graphql", "schema-kitchen-sink.graphql"} {
+		kitchenSink, err := ioutil.ReadFile(filename)
+		if err != nil {
+			panic(err)
+		}
+		tree := parseForPrint(T, string(kitchenSink))
+
+		for _, options := range tests {
+			printed := Print(tree, options)
+			reparsed, err := Parse(NewSource(printed, ""), ParseOptions{NoLocation: true})
+			if err != nil {
+				T.Errorf("Expect %v printed with %+v to parse but got:\n%v---\n%v", filename, options, err, printed)
+				continue
+			}
+			expect(T, Print(reparsed, options) == printed,
+				"Expect printing %v with %+v to be stable:\n%v---\n%v", filename, options, printed, Print(reparsed, options))
+
+			// The minified document is the same for every layout
+			minified := PrintOptions{Minify: true, SortFields: options.SortFields, SortArguments: options.SortArguments}
+			expect(T, Print(reparsed, minified) == Print(tree, minified),
+				"Expect %v printed with %+v to keep its content:\n%v---\n%v", filename, options, Print(tree, minified), Print(reparsed, minified))
+			if !options.Minify && !options.SortFields {
+				deepEqual(T, reparsed, tree)
+			}
+		}
+	}
+}
+
+func TestPrint_UsesIndentWidth(T *testing.T) {
+	tree := parseForPrint(T, `{ a { b } }`)
+	printed := Print(tree, PrintOptions{IndentWidth: 2})
+	expect(T, printed == "{\n  a {\n    b\n  }\n}\n", "Unexpected print:\n%v---", printed)
+}
+
+func TestPrint_WrapsLongArguments(T *testing.T) {
+	tree := parseForPrint(T, `
+query Q($first: Int, $after: String) { users(first: $first, after: $after) { id } short(a: 1) }
+type T { field(first: Int, after: String): [User] }`)
+
+	printed := Print(tree, PrintOptions{MaxLineLength: 30})
+	expected := `query Q(
+    $first: Int
+    $after: String
+) {
+    users(
+        first: $first
+        after: $after
+    ) {
+        id
+    }
+    short(a: 1)
+}
+
+type T {
+    field(
+        first: Int
+        after: String
+    ): [User]
+}
+`
+	expect(T, printed == expected, "Expect print:\n%v---\nbut got:\n%v---", expected, printed)
+
+	printed = Print(tree, PrintOptions{MaxLineLength: 80})
+	expect(T, printed == Print(tree), "Expect short lines not to be wrapped:\n%v---", printed)
+}
+
+func TestPrint_Minifies(T *testing.T) {
+	tree := parseForPrint(T, `
+query Q($a: [Int] = [1, 2], $b: String = "x") {
+  alias: field(list: [A B], strings: ["a" "b"], number: 1.5) @skip(if: false) {
+    ...Frag
+    ... on T { id }
+  }
+}
+
+"""
+Description
+"""
+type T implements A & B { f(a: Int = 1): String }`)
+
+	printed := Print(tree, PrintOptions{Minify: true})
+	expected := `query Q($a:[Int]=[1 2]$b:String="x"){alias:field(list:[A B]strings:["a" "b"]number:1.5)@skip(if:false){...Frag...on T{id}}}"Description"type T implements A&B{f(a:Int=1):String}`
+	expect(T, printed == expected, "Expect print:\n%v\nbut got:\n%v", expected, printed)
+}
+
+func TestPrint_MinifiesWithoutComments(T *testing.T) {
+	tree, err := Parse(NewSource("# before\n{ a # after\n}", ""), ParseOptions{Comments: true})
+	if err != nil {
+		T.Fatal(err)
+	}
+	printed := Print(tree, PrintOptions{Minify: true, Comments: true})
+	expect(T, printed == "{a}", "Unexpected print: %v", printed)
+}
+
+func TestPrint_SortsFieldsAndArguments(T *testing.T) {
+	source := `{ c b: a(z: 1, y: 2) @d(b: 1, a: 2) ... on T { y x } a ...F }
+
+type T { b(z: Int, y: Int): Int a: Int }
+
+input I { b: Int a: Int }
+`
+	tree := parseForPrint(T, source)
+	original := Print(tree)
+
+	printed := Print(tree, PrintOptions{SortFields: true, SortArguments: true})
+	expected := `{
+    a
+    b: a(y: 2, z: 1) @d(a: 2, b: 1)
+    c
+    ...F
+    ... on T {
+        x
+        y
+    }
+}
+
+type T {
+    a: Int
+    b(y: Int, z: Int): Int
+}
+
+input I {
+    a: Int
+    b: Int
+}
+`
+	expect(T, printed == expected, "Expect print:\n%v---\nbut got:\n%v---", expected, printed)
+	expect(T, Print(tree) == original, "Expect sorting not to change the AST:\n%v---", Print(tree))
+
+	printed = Print(tree, PrintOptions{SortFields: true})
+	expect(T, printed != expected && Print(tree, PrintOptions{SortArguments: true}) != expected,
+		"Expect fields and arguments to be sorted separately")
+}
+
+func TestPrint_DefaultValues(T *testing.T) {
+	// The " = " before a default value is only written when there is one
+	tree := parseForPrint(T, `
+query Q($a: Int = 1, $b: [Int], $c: [Int] = [], $d: In = {x: [1, {y: "z"}]}, $e: In) { f }
+type T { f(a: Int = 1, b: Int, c: In = {}): String }
+input In { a: Int = 1 b: Int c: [In] = [{a: 2}] }`)
+
+	printed := Print(tree)
+	expected := `query Q($a: Int = 1, $b: [Int], $c: [Int] = [], $d: In = {x: [1, {y: "z"}]}, $e: In) {
+    f
+}
+
+type T {
+    f(a: Int = 1, b: Int, c: In = {}): String
+}
+
+input In {
+    a: Int = 1
+    b: Int
+    c: [In] = [{a: 2}]
+}
+`
+	expect(T, printed == expected, "Expect print:\n%v---\nbut got:\n%v---", expected, printed)
+}
@@ -0,0 +1,157 @@
+package language
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+/**
+ * A token read by a Tokenizer, with the line and column of its start. Lines
+ * and columns start at 1, and columns count characters. The text of the token
+ * is Body[Start:End] of its source.
+ */
+type SourceToken struct {
+	Token
+	Line   int
+	Column int
+}
+
+/**
+ * Reads the tokens of a source one at a time, including the comments, for
+ * syntax highlighting and completion.
+ *
+ * Reading does not stop at invalid input, so that incomplete sources can be
+ * read: the invalid text is returned as a TOKEN_INVALID token along with its
+ * syntax error, and reading resumes after it. The rest of an invalid string
+ * is part of the invalid token, up to its closing quote or the end of its
+ * line.
+ */
+type Tokenizer struct {
+	lexer    *Lexer
+	source   Source
+	position int
+
+	// line is the line of the position scanned, which starts at lineStart.
+	line      int
+	lineStart int
+	scanned   int
+}
+
+func NewTokenizer(source Source) *Tokenizer {
+	return &Tokenizer{
+		lexer:  newLexer(source),
+		source: source,
+		line:   1,
+	}
+}
+
+/**
+ * Returns all the tokens of the source, without the final TOKEN_EOF, and the
+ * errors of its invalid tokens.
+ */
+func Tokenize(source Source) (tokens []SourceToken, errs []error) {
+	tokenizer := NewTokenizer(source)
+	for {
+		token, err := tokenizer.Next()
+		if token.Kind == TOKEN_EOF {
+			return tokens, errs
+		}
+		tokens = append(tokens, token)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+}
+
+/**
+ * Reads the next token. Returns a TOKEN_EOF token at the end of the source,
+ * and again on each following call. The error is only set for TOKEN_INVALID
+ * tokens.
+ */
+func (t *Tokenizer) Next() (SourceToken, error) {
+	body := t.source.Body
+	start := t.position
+	for start < len(body) {
+		ch, size := utf8.DecodeRuneInString(body[start:])
+		if !isWhitespace(ch) {
+			break
+		}
+		start += size
+	}
+
+	token, err := t.read(start)
+	if err != nil {
+		end := t.invalidEnd(start, err)
+		token = newToken(TOKEN_INVALID, start, end, body[start:end])
+	}
+	t.position = token.End
+	return t.locate(token), err
+}
+
+func (t *Tokenizer) read(position int) (token Token, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			if e, ok := e.(error); ok {
+				err = e
+			} else {
+				err = errors.New(fmt.Sprint("graphql/tokenizer: ", e))
+			}
+		}
+	}()
+	return t.lexer.readTokenOrComment(position), nil
+}
+
+/**
+ * Returns the end of the invalid token starting at start. It is the closing
+ * quote or the end of the line of a string or a comment, or the end of the
+ * invalid character otherwise.
+ */
+func (t *Tokenizer) invalidEnd(start int, err error) int {
+	body := t.source.Body
+	errorPosition := start
+	if err, ok := err.(QLError); ok && len(err.Positions) > 0 {
+		errorPosition = err.Positions[0]
+	}
+	switch {
+	case body[start] == '#':
+		end := start
+		for end < len(body) && body[end] != '\n' && body[end] != '\r' {
+			end++
+		}
+		return end
+	case body[start] == '"':
+		return t.lexer.recoverPosition(start, errorPosition)
+	case errorPosition >= len(body):
+		return len(body)
+	}
+	_, size := utf8.DecodeRuneInString(body[errorPosition:])
+	return errorPosition + size
+}
+
+/**
+ * Returns the token with the line and column of its start, counting the line
+ * terminators (\n, \r and \r\n) read since the previous token.
+ */
+func (t *Tokenizer) locate(token Token) SourceToken {
+	body := t.source.Body
+	for ; t.scanned < token.Start; t.scanned++ {
+		switch body[t.scanned] {
+		case '\n':
+			if t.scanned > 0 && body[t.scanned-1] == '\r' {
+				t.lineStart = t.scanned + 1
+				continue
+			}
+		case '\r':
+		default:
+			continue
+		}
+		t.line++
+		t.lineStart = t.scanned + 1
+	}
+	return SourceToken{
+		Token:  token,
+		Line:   t.line,
+		Column: utf8.RuneCountInString(body[t.lineStart:token.Start]) + 1,
+	}
+}
@@ -1,6 +1,7 @@
 package language
 
 import (
+	"reflect"
 	"unsafe"
 )
 
@@ -23,6 +24,7 @@ type QueryKeyMap struct {
 	FloatValue   struct{}
 	StringValue  struct{}
 	BooleanValue struct{}
+	NullValue    struct{}
 	EnumValue    struct{}
 	ListValue    struct{ Values bool }
 	ObjectValue  struct{ Fields bool }
@@ -34,16 +36,27 @@ type QueryKeyMap struct {
 	ListType    struct{ IType bool }
 	NonNullType struct{ IType bool }
 
-	ObjectTypeDefinition      struct{ Name, Interfaces, Fields bool }
-	FieldDefinition           struct{ Name, Arguments, IType bool }
-	InputValueDefinition      struct{ Name, IType, DefaultValue bool }
-	InterfaceTypeDefinition   struct{ Name, Fields bool }
-	UnionTypeDefinition       struct{ Name, Types bool }
-	ScalarTypeDefinition      struct{ Name bool }
-	EnumTypeDefinition        struct{ Name, Values bool }
-	EnumValueDefinition       struct{ Name bool }
-	InputObjectTypeDefinition struct{ Name, Fields bool }
+	ObjectTypeDefinition      struct{ Description, Name, Interfaces, Directives, Fields bool }
+	FieldDefinition           struct{ Description, Name, Arguments, IType, Directives bool }
+	InputValueDefinition      struct{ Description, Name, IType, DefaultValue, Directives bool }
+	InterfaceTypeDefinition   struct{ Description, Name, Interfaces, Directives, Fields bool }
+	UnionTypeDefinition       struct{ Description, Name, Directives, Types bool }
+	ScalarTypeDefinition      struct{ Description, Name, Directives bool }
+	EnumTypeDefinition        struct{ Description, Name, Directives, Values bool }
+	EnumValueDefinition       struct{ Description, Name, Directives bool }
+	InputObjectTypeDefinition struct{ Description, Name, Directives, Fields bool }
 	TypeExtensionDefinition   struct{ IDefinition bool }
+
+	SchemaDefinition        struct{ Description, Directives, OperationTypes bool }
+	OperationTypeDefinition struct{ IType bool }
+	DirectiveDefinition     struct{ Description, Name, Arguments, Locations bool }
+	SchemaExtension         struct{ Directives, OperationTypes bool }
+
+	ScalarTypeExtension      struct{ Name, Directives bool }
+	InterfaceTypeExtension   struct{ Name, Interfaces, Directives, Fields bool }
+	UnionTypeExtension       struct{ Name, Directives, Types bool }
+	EnumTypeExtension        struct{ Name, Directives, Values bool }
+	InputObjectTypeExtension struct{ Name, Directives, Fields bool }
 }
 
 var QueryDocumentKeys QueryKeyMap
@@ -65,7 +78,7 @@ func (n Name) visit(keyMap *QueryKeyMap) []_VisitNode {
 }
 
 func (n Document) visit(keyMap *QueryKeyMap) []_VisitNode {
-	result := make([]_VisitNode, len(n.Definitions))
+	result := make([]_VisitNode, len(n.Definitions))[:0]
 	for i, node := range n.Definitions {
 		result = append(result, vsi(node, "Definitions", i))
 	}
@@ -172,6 +185,10 @@ func (n BooleanValue) visit(keyMap *QueryKeyMap) []_VisitNode {
 	return nil
 }
 
+func (n NullValue) visit(keyMap *QueryKeyMap) []_VisitNode {
+	return nil
+}
+
 func (n EnumValue) visit(keyMap *QueryKeyMap) []_VisitNode {
 	return nil
 }
@@ -221,11 +238,13 @@ func (n NonNullType) visit(keyMap *QueryKeyMap) []_VisitNode {
 }
 
 func (n ObjectTypeDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
-	result := make([]_VisitNode, 1+len(n.Interfaces)+len(n.Fields))[:0]
+	result := make([]_VisitNode, 2+len(n.Interfaces)+len(n.Directives)+len(n.Fields))[:0]
+	result = append(result, vs(n.Description, "Description"))
 	result = append(result, vs(n.Name, "Name"))
 	for i, node := range n.Interfaces {
 		result = append(result, vsi(node, "Interfaces", i))
 	}
+	result = appendDirectives(result, n.Directives)
 	for i, node := range n.Fields {
 		result = append(result, vsi(node, "Fields", i))
 	}
@@ -233,26 +252,35 @@ func (n ObjectTypeDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
 }
 
 func (n FieldDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
-	result := make([]_VisitNode, 2+len(n.Arguments))
+	result := make([]_VisitNode, 3+len(n.Arguments)+len(n.Directives))[:0]
+	result = append(result, vs(n.Description, "Description"))
 	result = append(result, vs(n.Name, "Name"))
 	for i, node := range n.Arguments {
 		result = append(result, vsi(node, "Arguments", i))
 	}
 	result = append(result, vs(n.Type, "IType"))
-	return result
+	return appendDirectives(result, n.Directives)
 }
 
 func (n InputValueDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
-	return []_VisitNode{
+	result := make([]_VisitNode, 4+len(n.Directives))[:0]
+	result = append(result,
+		vs(n.Description, "Description"),
 		vs(n.Name, "Name"),
 		vs(n.Type, "IType"),
 		vs(n.DefaultValue, "DefaultValue"),
-	}
+	)
+	return appendDirectives(result, n.Directives)
 }
 
 func (n InterfaceTypeDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
-	result := make([]_VisitNode, 1+len(n.Fields))[:0]
+	result := make([]_VisitNode, 2+len(n.Interfaces)+len(n.Directives)+len(n.Fields))[:0]
+	result = append(result, vs(n.Description, "Description"))
 	result = append(result, vs(n.Name, "Name"))
+	for i, node := range n.Interfaces {
+		result = append(result, vsi(node, "Interfaces", i))
+	}
+	result = appendDirectives(result, n.Directives)
 	for i, node := range n.Fields {
 		result = append(result, vsi(node, "Fields", i))
 	}
@@ -260,8 +288,10 @@ func (n InterfaceTypeDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
 }
 
 func (n UnionTypeDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
-	result := make([]_VisitNode, 1+len(n.Types))[:0]
+	result := make([]_VisitNode, 2+len(n.Directives)+len(n.Types))[:0]
+	result = append(result, vs(n.Description, "Description"))
 	result = append(result, vs(n.Name, "Name"))
+	result = appendDirectives(result, n.Directives)
 	for i, node := range n.Types {
 		result = append(result, vsi(node, "Types", i))
 	}
@@ -269,12 +299,17 @@ func (n UnionTypeDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
 }
 
 func (n ScalarTypeDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
-	return []_VisitNode{vs(n.Name, "Name")}
+	result := make([]_VisitNode, 2+len(n.Directives))[:0]
+	result = append(result, vs(n.Description, "Description"))
+	result = append(result, vs(n.Name, "Name"))
+	return appendDirectives(result, n.Directives)
 }
 
 func (n EnumTypeDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
-	result := make([]_VisitNode, 1+len(n.Values))[:0]
+	result := make([]_VisitNode, 2+len(n.Directives)+len(n.Values))[:0]
+	result = append(result, vs(n.Description, "Description"))
 	result = append(result, vs(n.Name, "Name"))
+	result = appendDirectives(result, n.Directives)
 	for i, node := range n.Values {
 		result = append(result, vsi(node, "Values", i))
 	}
@@ -282,12 +317,17 @@ func (n EnumTypeDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
 }
 
 func (n EnumValueDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
-	return []_VisitNode{vs(n.Name, "Name")}
+	result := make([]_VisitNode, 2+len(n.Directives))[:0]
+	result = append(result, vs(n.Description, "Description"))
+	result = append(result, vs(n.Name, "Name"))
+	return appendDirectives(result, n.Directives)
 }
 
 func (n InputObjectTypeDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
-	result := make([]_VisitNode, 1+len(n.Fields))[:0]
+	result := make([]_VisitNode, 2+len(n.Directives)+len(n.Fields))[:0]
+	result = append(result, vs(n.Description, "Description"))
 	result = append(result, vs(n.Name, "Name"))
+	result = appendDirectives(result, n.Directives)
 	for i, node := range n.Fields {
 		result = append(result, vsi(node, "Fields", i))
 	}
@@ -298,6 +338,98 @@ func (n TypeExtensionDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
 	return []_VisitNode{vs(n.Definition, "IDefinition")}
 }
 
+func (n SchemaDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
+	result := make([]_VisitNode, 1+len(n.Directives)+len(n.OperationTypes))[:0]
+	result = append(result, vs(n.Description, "Description"))
+	result = appendDirectives(result, n.Directives)
+	for i, node := range n.OperationTypes {
+		result = append(result, vsi(node, "OperationTypes", i))
+	}
+	return result
+}
+
+func (n OperationTypeDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
+	return []_VisitNode{vs(n.Type, "IType")}
+}
+
+func (n DirectiveDefinition) visit(keyMap *QueryKeyMap) []_VisitNode {
+	result := make([]_VisitNode, 2+len(n.Arguments)+len(n.Locations))[:0]
+	result = append(result, vs(n.Description, "Description"))
+	result = append(result, vs(n.Name, "Name"))
+	for i, node := range n.Arguments {
+		result = append(result, vsi(node, "Arguments", i))
+	}
+	for i, node := range n.Locations {
+		result = append(result, vsi(node, "Locations", i))
+	}
+	return result
+}
+
+func (n SchemaExtension) visit(keyMap *QueryKeyMap) []_VisitNode {
+	result := make([]_VisitNode, len(n.Directives)+len(n.OperationTypes))[:0]
+	result = appendDirectives(result, n.Directives)
+	for i, node := range n.OperationTypes {
+		result = append(result, vsi(node, "OperationTypes", i))
+	}
+	return result
+}
+
+func (n ScalarTypeExtension) visit(keyMap *QueryKeyMap) []_VisitNode {
+	result := make([]_VisitNode, 1+len(n.Directives))[:0]
+	result = append(result, vs(n.Name, "Name"))
+	return appendDirectives(result, n.Directives)
+}
+
+func (n InterfaceTypeExtension) visit(keyMap *QueryKeyMap) []_VisitNode {
+	result := make([]_VisitNode, 1+len(n.Interfaces)+len(n.Directives)+len(n.Fields))[:0]
+	result = append(result, vs(n.Name, "Name"))
+	for i, node := range n.Interfaces {
+		result = append(result, vsi(node, "Interfaces", i))
+	}
+	result = appendDirectives(result, n.Directives)
+	for i, node := range n.Fields {
+		result = append(result, vsi(node, "Fields", i))
+	}
+	return result
+}
+
+func (n UnionTypeExtension) visit(keyMap *QueryKeyMap) []_VisitNode {
+	result := make([]_VisitNode, 1+len(n.Directives)+len(n.Types))[:0]
+	result = append(result, vs(n.Name, "Name"))
+	result = appendDirectives(result, n.Directives)
+	for i, node := range n.Types {
+		result = append(result, vsi(node, "Types", i))
+	}
+	return result
+}
+
+func (n EnumTypeExtension) visit(keyMap *QueryKeyMap) []_VisitNode {
+	result := make([]_VisitNode, 1+len(n.Directives)+len(n.Values))[:0]
+	result = append(result, vs(n.Name, "Name"))
+	result = appendDirectives(result, n.Directives)
+	for i, node := range n.Values {
+		result = append(result, vsi(node, "Values", i))
+	}
+	return result
+}
+
+func (n InputObjectTypeExtension) visit(keyMap *QueryKeyMap) []_VisitNode {
+	result := make([]_VisitNode, 1+len(n.Directives)+len(n.Fields))[:0]
+	result = append(result, vs(n.Name, "Name"))
+	result = appendDirectives(result, n.Directives)
+	for i, node := range n.Fields {
+		result = append(result, vsi(node, "Fields", i))
+	}
+	return result
+}
+
+func appendDirectives(result []_VisitNode, directives []*Directive) []_VisitNode {
+	for i, node := range directives {
+		result = append(result, vsi(node, "Directives", i))
+	}
+	return result
+}
+
 type ManualVisitor interface {
 	Visit(INode, func(INode))
 }
@@ -319,15 +451,15 @@ type VisitorFunc struct {
 }
 
 func (v VisitorFunc) Enter(node INode, info VisitInfo) VisitAction {
-	if v.Enter != nil {
-		return v.Enter(node, info)
+	if v.EnterFunc != nil {
+		return v.EnterFunc(node, info)
 	}
 	return nil
 }
 
 func (v VisitorFunc) Leave(node INode, info VisitInfo) VisitAction {
-	if v.Leave != nil {
-		return v.Leave(node, info)
+	if v.LeaveFunc != nil {
+		return v.LeaveFunc(node, info)
 	}
 	return nil
 }
@@ -361,18 +493,6 @@ func VISIT_REPLACE(node INode) VisitAction {
 	return &_VisitAction{node}
 }
 
-type _VisitStack struct {
-	index      int
-	visitNodes []_VisitNode
-	edits      []_VisitEdit
-	prev       *_VisitStack
-}
-
-type _VisitEdit struct {
-	key  string
-	node INode
-}
-
 type _VisitNode struct {
 	node  INode
 	name  string
@@ -399,81 +519,69 @@ func ManualVisit(root INode, visitor ManualVisitor) {
 
 }
 
+/**
+ * Visits the tree rooted at the given node in depth-first order, calling
+ * Enter on the way down and Leave on the way up. Returning VISIT_SKIP from
+ * Enter skips the children of the node (and its Leave call), while returning
+ * VISIT_BREAK from either function stops the traversal.
+ */
 func Visit(root INode, visitor Visitor, keyMap *QueryKeyMap) {
 	if keyMap == nil {
 		keyMap = &QueryDocumentKeys
 	}
 
-	var parent INode
-	var path []string
-	var ancestors []INode
-	var stack = &_VisitStack{
-		visitNodes: []_VisitNode{{node: root}},
-	}
-	for ; ; stack.index++ {
-		var visitNode _VisitNode
-		var node INode
-
-		var isEntering = stack.index < len(stack.visitNodes)
-		if isEntering {
-			visitNode = stack.visitNodes[stack.index]
-			node = visitNode.node
-
-		} else {
-			path = path[:len(path)-1]
-			node = ancestors[len(ancestors)-1]
-			ancestors = ancestors[:len(ancestors)-1]
-			stack = stack.prev
-		}
+	w := &_Walker{
+		visitor: visitor,
+		keyMap:  keyMap,
+	}
+	w.walk(_VisitNode{node: root})
+}
 
-		info := VisitInfo{
-			Parent:    parent,
-			Ancestors: ancestors,
-			Key:       visitNode.name,
-			Path:      path,
-		}
+type _Walker struct {
+	visitor   Visitor
+	keyMap    *QueryKeyMap
+	path      []string
+	ancestors []INode
+}
 
-		var result VisitAction
-		if isEntering {
-			if visitor.Enter != nil {
-				result = visitor.Enter(node, info)
-			}
-		} else {
-			if visitor.Leave != nil {
-				result = visitor.Leave(node, info)
-			}
-		}
+/**
+ * Returns false when the traversal must stop.
+ */
+func (w *_Walker) walk(visitNode _VisitNode) bool {
+	node := visitNode.node
+	if node == nil || reflect.ValueOf(node).IsNil() {
+		return true
+	}
 
-		if result == VISIT_BREAK {
-			break
-		}
-		if result == VISIT_SKIP {
-			// skip the node
-		}
-		if result == VISIT_DELETE {
-			// delete the node and stop digging deeper
-		}
-		if result != nil && result.newNode() != nil {
-			// replace the node with a new one
-		}
+	var parent INode
+	if l := len(w.ancestors); l > 0 {
+		parent = w.ancestors[l-1]
+	}
+	info := VisitInfo{
+		Parent:    parent,
+		Ancestors: w.ancestors,
+		Key:       visitNode.name,
+		Path:      w.path,
+	}
 
-		// if not skip or delete
-		if isEntering {
-			nextNodes := visitNode.node.visit(keyMap)
-			if len(nextNodes) > 0 {
-				stack = &_VisitStack{
-					visitNodes: nextNodes,
-					prev:       stack,
-				}
-			}
-
-			parent = node
-			path = append(path, visitNode.name)
-			ancestors = append(ancestors, node)
-		}
+	result := w.visitor.Enter(node, info)
+	if result == VISIT_BREAK {
+		return false
+	}
+	if result == VISIT_SKIP || result == VISIT_DELETE {
+		// editing the tree is not supported yet
+		return true
+	}
 
-		if !isEntering && len(ancestors) == 0 {
-			break
+	w.path = append(w.path, visitNode.name)
+	w.ancestors = append(w.ancestors, node)
+	for _, next := range node.visit(w.keyMap) {
+		if !w.walk(next) {
+			return false
 		}
 	}
+	w.path = w.path[:len(w.path)-1]
+	w.ancestors = w.ancestors[:len(w.ancestors)-1]
+
+	return w.visitor.Leave(node, info) != VISIT_BREAK
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"strings"
 
 	lang "github.com/ng-vu/graphql-go/internal/language"
 	"github.com/ng-vu/graphql-go/ql"
@@ -118,91 +119,299 @@ type QLObjectInterface interface {
 }
 
 func NewQLType(config ql.Type) QLType {
+	return newTypeRegistry().newType(config)
+}
+
+func NewQLInputType(config ql.InputType) QLInputType {
+	return newTypeRegistry().newInputType(config)
+}
+
+func NewQLOutputType(config ql.OutputType) QLOutputType {
+	return newTypeRegistry().newOutputType(config)
+}
+
+/**
+ * Types created from the configs reachable from the same root share a
+ * registry, so that each named config becomes a single type instance, no
+ * matter how many fields refer to it. This is what allows a type to refer to
+ * itself, directly or through other types.
+ */
+type _TypeRegistry struct {
+	types map[string]QLNamedType
+
+	// Reports whether the elements with the given visibility tags belong to
+	// the schema. Untagged elements always do.
+	visible func(tags []string) bool
+
+	// Directives which may be applied to field definitions, by name.
+	directives map[string]*QLDirective
+}
+
+func newTypeRegistry() *_TypeRegistry {
+	return &_TypeRegistry{
+		types: make(map[string]QLNamedType),
+	}
+}
+
+func (r *_TypeRegistry) namedType(name string, create func() QLNamedType) QLNamedType {
+	if typ, ok := r.types[name]; ok {
+		return typ
+	}
+	typ := create()
+	r.types[name] = typ
+	return typ
+}
+
+func (r *_TypeRegistry) isVisible(tags []string) bool {
+	return len(tags) == 0 || r.visible == nil || r.visible(tags)
+}
+
+/**
+ * Reports whether the named type of a type config is visible. Fields and
+ * arguments referring to a hidden type are hidden along with it.
+ */
+func (r *_TypeRegistry) isTypeVisible(config interface{}) bool {
 	switch config := config.(type) {
 	case ql.Scalar:
-		return NewQLScalar(config)
+		return r.isVisible(config.Visibility)
+	case ql.Object:
+		return r.isVisible(config.Visibility)
+	case ql.Interface:
+		return r.isVisible(config.Visibility)
+	case ql.Union:
+		return r.isVisible(config.Visibility)
+	case ql.Enum:
+		return r.isVisible(config.Visibility)
+	case ql.InputObject:
+		return r.isVisible(config.Visibility)
+	case ql.List:
+		return r.isTypeVisible(config.OfType)
+	case ql.NonNull:
+		return r.isTypeVisible(config.OfType)
+	}
+	return true
+}
+
+func (r *_TypeRegistry) newType(config ql.Type) QLType {
+	switch config := config.(type) {
+	case ql.Scalar:
+		return r.newScalar(config)
 	case ql.Object:
-		return NewQLObject(config)
+		return r.newObject(config)
 	case ql.Interface:
-		return NewQLInterface(config)
+		return r.newInterface(config)
 	case ql.Union:
-		return NewQLUnion(config)
+		return r.newUnion(config)
 	case ql.Enum:
-		return NewQLEnum(config)
+		return r.newEnum(config)
 	case ql.InputObject:
-		return NewQLInputObject(config)
+		return r.newInputObject(config)
 	case ql.List:
-		return NewQLList(NewQLType(config.OfType))
+		return NewQLList(r.newType(config.OfType))
 	case ql.NonNull:
-		return NewQLNonNull(NewQLType(config.OfType))
+		return NewQLNonNull(r.newType(config.OfType))
 	default:
 		panic("graphql-go/types: unreachable")
 	}
 }
 
-func NewQLInputType(config ql.InputType) QLInputType {
+func (r *_TypeRegistry) newInputType(config ql.InputType) QLInputType {
 	switch config := config.(type) {
 	case ql.Scalar:
-		return NewQLScalar(config)
+		return r.newScalar(config)
 	case ql.Enum:
-		return NewQLEnum(config)
+		return r.newEnum(config)
 	case ql.InputObject:
-		return NewQLInputObject(config)
+		return r.newInputObject(config)
 	case ql.List:
-		return NewQLList(NewQLType(config.OfType))
+		return NewQLList(r.newType(config.OfType))
 	case ql.NonNull:
-		return NewQLNonNull(NewQLType(config.OfType))
+		return NewQLNonNull(r.newType(config.OfType))
 	default:
 		panic("graphql-go/types: unreachable")
 	}
 }
 
-func NewQLOutputType(config ql.OutputType) QLOutputType {
+func (r *_TypeRegistry) newOutputType(config ql.OutputType) QLOutputType {
 	switch config := config.(type) {
 	case ql.Scalar:
-		return NewQLScalar(config)
+		return r.newScalar(config)
 	case ql.Object:
-		return NewQLObject(config)
+		return r.newObject(config)
 	case ql.Interface:
-		return NewQLInterface(config)
+		return r.newInterface(config)
 	case ql.Union:
-		return NewQLUnion(config)
+		return r.newUnion(config)
 	case ql.Enum:
-		return NewQLEnum(config)
+		return r.newEnum(config)
 	case ql.List:
-		return NewQLList(NewQLType(config.OfType))
+		return NewQLList(r.newType(config.OfType))
 	case ql.NonNull:
-		return NewQLNonNull(NewQLType(config.OfType))
+		return NewQLNonNull(r.newType(config.OfType))
 	default:
 		panic("graphql-go/types: unreachable")
 	}
 }
 
+func (r *_TypeRegistry) newScalar(config ql.Scalar) *QLScalar {
+	typ, ok := r.namedType(config.Name, func() QLNamedType {
+		return NewQLScalar(config)
+	}).(*QLScalar)
+	if !ok {
+		throw(`Schema must contain unique named types but contains multiple types named %v`, config.Name)
+	}
+	return typ
+}
+
+func (r *_TypeRegistry) newObject(config ql.Object) *QLObject {
+	typ, ok := r.namedType(config.Name, func() QLNamedType {
+		return newQLObject(config, r)
+	}).(*QLObject)
+	if !ok {
+		throw(`Schema must contain unique named types but contains multiple types named %v`, config.Name)
+	}
+	return typ
+}
+
+func (r *_TypeRegistry) newInterface(config ql.Interface) *QLInterface {
+	typ, ok := r.namedType(config.Name, func() QLNamedType {
+		return newQLInterface(config, r)
+	}).(*QLInterface)
+	if !ok {
+		throw(`Schema must contain unique named types but contains multiple types named %v`, config.Name)
+	}
+	return typ
+}
+
+func (r *_TypeRegistry) newUnion(config ql.Union) *QLUnion {
+	typ, ok := r.namedType(config.Name, func() QLNamedType {
+		return newQLUnion(config, r)
+	}).(*QLUnion)
+	if !ok {
+		throw(`Schema must contain unique named types but contains multiple types named %v`, config.Name)
+	}
+	return typ
+}
+
+func (r *_TypeRegistry) newEnum(config ql.Enum) *QLEnum {
+	typ, ok := r.namedType(config.Name, func() QLNamedType {
+		return newQLEnum(config, r)
+	}).(*QLEnum)
+	if !ok {
+		throw(`Schema must contain unique named types but contains multiple types named %v`, config.Name)
+	}
+	return typ
+}
+
+func (r *_TypeRegistry) newInputObject(config ql.InputObject) *QLInputObject {
+	typ, ok := r.namedType(config.Name, func() QLNamedType {
+		return newQLInputObject(config, r)
+	}).(*QLInputObject)
+	if !ok {
+		throw(`Schema must contain unique named types but contains multiple types named %v`, config.Name)
+	}
+	return typ
+}
+
+/**
+ * Adapts a resolve function given in a ql.Field config. The function either
+ * receives the arguments of the field, or the source value followed by the
+ * arguments, and returns one value. The arguments are given as a struct whose
+ * fields are filled from the argument named by their `graphql` tag, or by
+ * their own name starting with a lower case letter.
+ *
+ * Returns nil when fn is nil, in which case the executor resolves the field
+ * from the source value.
+ */
 func NewQLResolveFunc(fn interface{}) QLFieldResolveFunc {
+	if fn == nil {
+		return nil
+	}
 	v := reflect.ValueOf(fn)
 	t := v.Type()
 	if t.Kind() != reflect.Func {
 		panic("graphql: expect resolve function")
 	}
-	if t.NumIn() != 1 {
-		panic("graphql: resolve function must receive one struct argument")
+	if t.NumIn() != 1 && t.NumIn() != 2 {
+		panic("graphql: resolve function must receive one struct argument, optionally preceded by the source")
 	}
 	if t.NumOut() != 1 {
 		panic("graphql: resolve function must return one result")
 	}
 
-	in0 := t.In(0)
-	if in0.Kind() != reflect.Struct {
+	argsType := t.In(t.NumIn() - 1)
+	if argsType.Kind() != reflect.Struct {
 		panic("graphql: resolve function must receive struct as argument")
 	}
+	var sourceType reflect.Type
+	if t.NumIn() == 2 {
+		sourceType = t.In(0)
+	}
 
 	return func(source interface{}, args map[string]interface{}, info QLResolveInfo) interface{} {
-		arg := reflect.New(in0).Elem()
-		result := v.Call([]reflect.Value{arg})
+		in := make([]reflect.Value, 0, 2)
+		if sourceType != nil {
+			in = append(in, sourceValue(sourceType, source))
+		}
+		in = append(in, argsValue(argsType, args))
+		result := v.Call(in)
 		return result[0].Interface()
 	}
 }
 
+func sourceValue(t reflect.Type, source interface{}) reflect.Value {
+	if source == nil {
+		return reflect.Zero(t)
+	}
+	v := reflect.ValueOf(source)
+	if v.Type().AssignableTo(t) {
+		return v
+	}
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Type().AssignableTo(t) {
+		return v.Elem()
+	}
+	panic(fmt.Sprintf("graphql: resolve function expects source of type %v but got %v", t, v.Type()))
+}
+
+func argsValue(t reflect.Type, args map[string]interface{}) reflect.Value {
+	result := reflect.New(t).Elem()
+	for i, n := 0, t.NumField(); i < n; i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Tag.Get("graphql")
+		if name == "" {
+			name = strings.ToLower(field.Name[:1]) + field.Name[1:]
+		}
+		value, ok := args[name]
+		if !ok || value == nil {
+			continue
+		}
+		v := reflect.ValueOf(value)
+		switch {
+		case v.Type().AssignableTo(field.Type):
+			result.Field(i).Set(v)
+		case isNumberKind(v.Kind()) && isNumberKind(field.Type.Kind()):
+			result.Field(i).Set(v.Convert(field.Type))
+		default:
+			panic(fmt.Sprintf("graphql: argument %v of type %v can not be assigned to field %v of type %v",
+				name, v.Type(), field.Name, field.Type))
+		}
+	}
+	return result
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
 /*
 Scalar Type Definition
 
@@ -316,11 +525,16 @@ type QLObject struct {
 	IsTypeOf    func(v interface{}, info *QLResolveInfo) bool
 
 	config     ql.Object
+	types      *_TypeRegistry
 	fields     map[string]*QLFieldDefinition
 	interfaces []*QLInterface
 }
 
 func NewQLObject(config ql.Object) *QLObject {
+	return newTypeRegistry().newObject(config)
+}
+
+func newQLObject(config ql.Object, types *_TypeRegistry) *QLObject {
 	if config.Name == "" {
 		throw("Type must be named.")
 	}
@@ -340,8 +554,8 @@ func NewQLObject(config ql.Object) *QLObject {
 		// TODO(qv): IsTypeOf
 		// IsTypeOf:    config.IsTypeOf,
 		config: config,
+		types:  types,
 	}
-	addImplementationToInterfaces(g)
 	return g
 }
 
@@ -351,31 +565,37 @@ func (g *QLObject) String() string {
 
 func (g *QLObject) GetFields() map[string]*QLFieldDefinition {
 	if g.fields == nil {
-		g.fields = defineFieldMap(g, g.config.Fields, g.config.FieldsFunc)
+		g.fields = defineFieldMap(g.types, g, g.config.Fields, g.config.FieldsFunc)
 	}
 	return g.fields
 }
 
 func (g *QLObject) GetInterfaces() []*QLInterface {
 	if g.interfaces == nil {
-		g.interfaces = g.defineInterfaces()
+		g.interfaces = defineInterfaces(g.types, g.config.Interfaces, g.config.InterfacesFunc)
 	}
 	return g.interfaces
 }
 
-func (g *QLObject) defineInterfaces() []*QLInterface {
-	interfaces := g.config.Interfaces
-	if fn := g.config.InterfacesFunc; fn != nil {
-		interfaces = fn()
+func defineInterfaces(
+	types *_TypeRegistry,
+	interfaces ql.Interfaces,
+	interfacesFunc func() ql.Interfaces,
+) []*QLInterface {
+	if interfacesFunc != nil {
+		interfaces = interfacesFunc()
 	}
-	result := make([]*QLInterface, len(interfaces))
-	for i, iface := range interfaces {
-		result[i] = NewQLInterface(iface)
+	result := make([]*QLInterface, len(interfaces))[:0]
+	for _, iface := range interfaces {
+		if types.isVisible(iface.Visibility) {
+			result = append(result, types.newInterface(iface))
+		}
 	}
 	return result
 }
 
 func defineFieldMap(
+	types *_TypeRegistry,
 	typ QLNamedType,
 	fieldMap ql.FieldMap,
 	fieldMapFunc func() ql.FieldMap,
@@ -386,13 +606,18 @@ func defineFieldMap(
 	}
 	result := make(map[string]*QLFieldDefinition)
 	for fieldName, fieldConfig := range fieldMap {
+		if !types.isVisible(fieldConfig.Visibility) || !types.isTypeVisible(fieldConfig.Type) {
+			continue
+		}
 		args := make([]*QLArgument, len(fieldConfig.Args))[:0]
 		for argName, argConfig := range fieldConfig.Args {
+			if !types.isVisible(argConfig.Visibility) || !types.isTypeVisible(argConfig.Type) {
+				continue
+			}
 			arg := &QLArgument{
-				Name:        argName,
-				Description: argConfig.Description,
-				// TODO(qv): convert ql.Input to QLInput
-				// Type:         argConfig.Type,
+				Name:         argName,
+				Description:  argConfig.Description,
+				Type:         types.newInputType(argConfig.Type),
 				DefaultValue: argConfig.DefaultValue,
 			}
 			args = append(args, arg)
@@ -401,20 +626,18 @@ func defineFieldMap(
 		field := &QLFieldDefinition{
 			Name:              fieldName,
 			Description:       fieldConfig.Description,
-			Type:              NewQLOutputType(fieldConfig.Type),
+			Type:              types.newOutputType(fieldConfig.Type),
 			Args:              args,
 			Resolve:           NewQLResolveFunc(fieldConfig.Resolve),
 			DeprecationReason: fieldConfig.DeprecationReason,
+			Cost:              fieldCost(typ, fieldName, fieldConfig),
+			Directives:        types.newAppliedDirectives(typ, fieldName, fieldConfig.Directives),
 		}
 		result[fieldName] = field
 	}
 	return result
 }
 
-func addImplementationToInterfaces(impl *QLObject) {
-	// TODO(qv)
-}
-
 type QLFieldResolveFunc func(
 	source interface{},
 	args map[string]interface{},
@@ -440,6 +663,8 @@ type QLFieldDefinition struct {
 	Args              []*QLArgument
 	Resolve           QLFieldResolveFunc
 	DeprecationReason string
+	Cost              int
+	Directives        []*QLAppliedDirective
 }
 
 type QLArgument struct {
@@ -475,22 +700,33 @@ type QLInterface struct {
 	ResolveType func(v interface{}, info *QLResolveInfo) *QLObject
 
 	config          ql.Interface
+	types           *_TypeRegistry
 	fields          map[string]*QLFieldDefinition
+	interfaces      []*QLInterface
 	implementations []*QLObject
 	positionTypes   map[string]*QLObject
 }
 
 func NewQLInterface(config ql.Interface) *QLInterface {
+	return newTypeRegistry().newInterface(config)
+}
+
+func newQLInterface(config ql.Interface, types *_TypeRegistry) *QLInterface {
 	if config.Name == "" {
 		throw("Type must be named.")
 	}
 	assertValidName(config.Name)
+
+	if config.InterfacesFunc != nil && config.Interfaces != nil {
+		throw(`%v must provide either "Interfaces" or "InterfacesFunc", not both.`, config.Name)
+	}
 	return &QLInterface{
 		Name:        config.Name,
 		Description: config.Description,
 		// TODO(qv): resolve type
 		// ResolveType:     config.ResolveType,
 		config:          config,
+		types:           types,
 		implementations: make([]*QLObject, 4)[:0],
 	}
 }
@@ -501,11 +737,22 @@ func (g *QLInterface) String() string {
 
 func (g *QLInterface) GetFields() map[string]*QLFieldDefinition {
 	if g.fields == nil {
-		g.fields = defineFieldMap(g, g.config.Fields, g.config.FieldsFunc)
+		g.fields = defineFieldMap(g.types, g, g.config.Fields, g.config.FieldsFunc)
 	}
 	return g.fields
 }
 
+/**
+ * Returns the interfaces the interface implements. The interfaces implemented
+ * by them must be listed too.
+ */
+func (g *QLInterface) GetInterfaces() []*QLInterface {
+	if g.interfaces == nil {
+		g.interfaces = defineInterfaces(g.types, g.config.Interfaces, g.config.InterfacesFunc)
+	}
+	return g.interfaces
+}
+
 func (g *QLInterface) GetPossibleTypes() []*QLObject {
 	return g.implementations
 }
@@ -574,6 +821,10 @@ type QLUnion struct {
 }
 
 func NewQLUnion(config ql.Union) *QLUnion {
+	return newTypeRegistry().newUnion(config)
+}
+
+func newQLUnion(config ql.Union, types *_TypeRegistry) *QLUnion {
 	if config.Name == "" {
 		throw("Type must be named.")
 	}
@@ -581,13 +832,13 @@ func NewQLUnion(config ql.Union) *QLUnion {
 	if len(config.Types) == 0 {
 		throw("Must provide Array of types for Union %v", config.Name)
 	}
-	types := make([]*QLObject, len(config.Types))
-	if config.ResolveType == nil {
-		for i, typ := range config.Types {
-			if typ.IsTypeOf == nil {
-				throw(`Union Type %v does not provide a "ResolveType" function and possible Type %v does not provide a "IsTypeOf" function. There is no way to resolve this possible type during execution.`, config.Name, typ.Name)
-			}
-			types[i] = NewQLObject(config.Types[i])
+	possibleTypes := make([]*QLObject, len(config.Types))[:0]
+	for _, typ := range config.Types {
+		if config.ResolveType == nil && typ.IsTypeOf == nil {
+			throw(`Union Type %v does not provide a "ResolveType" function and possible Type %v does not provide a "IsTypeOf" function. There is no way to resolve this possible type during execution.`, config.Name, typ.Name)
+		}
+		if types.isVisible(typ.Visibility) {
+			possibleTypes = append(possibleTypes, types.newObject(typ))
 		}
 	}
 	return &QLUnion{
@@ -596,7 +847,7 @@ func NewQLUnion(config ql.Union) *QLUnion {
 		// TODO(qv): resolveType
 		// ResolveType: config.ResolveType,
 		config: config,
-		types:  types,
+		types:  possibleTypes,
 	}
 }
 
@@ -667,13 +918,17 @@ type QLEnumValueDefinition struct {
 }
 
 func NewQLEnum(config ql.Enum) *QLEnum {
+	return newTypeRegistry().newEnum(config)
+}
+
+func newQLEnum(config ql.Enum, types *_TypeRegistry) *QLEnum {
 	assertValidName(config.Name)
 	g := &QLEnum{
 		Name:        config.Name,
 		Description: config.Description,
 		config:      config,
 	}
-	g.values = g.defineEnumValues(config.Values)
+	g.values = g.defineEnumValues(types, config.Values)
 	return g
 }
 
@@ -737,11 +992,15 @@ func (g *QLEnum) getNameLookup() map[string]*QLEnumValueDefinition {
 }
 
 func (g *QLEnum) defineEnumValues(
+	types *_TypeRegistry,
 	valueMap ql.EnumValueMap,
 ) []*QLEnumValueDefinition {
 	values := make([]*QLEnumValueDefinition, len(valueMap))[:0]
 	for name, v := range valueMap {
 		assertValidName(name)
+		if !types.isVisible(v.Visibility) {
+			continue
+		}
 		value := &QLEnumValueDefinition{
 			Name:              name,
 			Value:             v.Value,
@@ -781,6 +1040,7 @@ type QLInputObject struct {
 	Description string
 
 	config ql.InputObject
+	types  *_TypeRegistry
 	fields map[string]*InputObjectField
 }
 
@@ -792,6 +1052,10 @@ type InputObjectField struct {
 }
 
 func NewQLInputObject(config ql.InputObject) *QLInputObject {
+	return newTypeRegistry().newInputObject(config)
+}
+
+func newQLInputObject(config ql.InputObject, types *_TypeRegistry) *QLInputObject {
 	if config.Name == "" {
 		throw("Type must be named.")
 	}
@@ -800,6 +1064,7 @@ func NewQLInputObject(config ql.InputObject) *QLInputObject {
 		Name:        config.Name,
 		Description: config.Description,
 		config:      config,
+		types:       types,
 	}
 }
 
@@ -829,9 +1094,12 @@ func (g *QLInputObject) defineFieldMap() map[string]*InputObjectField {
 	result := make(map[string]*InputObjectField)
 	for name, fieldConfig := range fieldsConfig {
 		assertValidName(name)
+		if !g.types.isVisible(fieldConfig.Visibility) || !g.types.isTypeVisible(fieldConfig.Type) {
+			continue
+		}
 		field := &InputObjectField{
 			Name:         name,
-			Type:         NewQLInputType(fieldConfig.Type),
+			Type:         g.types.newInputType(fieldConfig.Type),
 			Description:  fieldConfig.Description,
 			DefaultValue: fieldConfig.DefaultValue,
 		}
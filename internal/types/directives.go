@@ -1,44 +1,172 @@
 package types
 
 import (
+	"reflect"
+
 	"github.com/ng-vu/graphql-go/ql"
 )
 
+/**
+ * Directives are used by the QL runtime as a way of modifying execution
+ * behavior. Type system creators will usually not create these directly.
+ */
 type QLDirective struct {
 	Name        string
 	Description string
 	Args        []*QLArgument
-	OnOperation bool
-	OnFragment  bool
-	OnField     bool
-}
-
-var QLIncludeDirective = &QLDirective{
-	Name:        "include",
-	Description: `Directs the executor to include this field or fragment only when the "if" argument is true.`,
-	Args: []*QLArgument{
-		{
-			Name:        "if",
-			Type:        NewQLNonNull(NewQLType(ql.Boolean)),
-			Description: "Included when true.",
-		},
-	},
-	OnOperation: false,
-	OnFragment:  true,
-	OnField:     true,
-}
-
-var QLSkipDirective = &QLDirective{
-	Name:        "skip",
-	Description: `Directs the executor to skip this field or fragment when the "if" argument is true.`,
-	Args: []*QLArgument{
-		{
-			Name:        "if",
-			Type:        NewQLNonNull(NewQLType(ql.Boolean)),
-			Description: "Included when true.",
-		},
-	},
-	OnOperation: false,
-	OnFragment:  true,
-	OnField:     true,
+	Locations   []ql.DirectiveLocation
+	Resolve     ql.DirectiveResolveFunc
+}
+
+/**
+ * A directive applied to a field definition in the schema, with its
+ * arguments, including the default values of the ones not given.
+ */
+type QLAppliedDirective struct {
+	Directive *QLDirective
+	Args      map[string]interface{}
+}
+
+func NewQLDirective(config ql.Directive) *QLDirective {
+	return newTypeRegistry().newDirective(config)
+}
+
+func (r *_TypeRegistry) newDirective(config ql.Directive) *QLDirective {
+	if config.Name == "" {
+		throw("Directive must be named.")
+	}
+	assertValidName(config.Name)
+	if len(config.Locations) == 0 {
+		throw(`Must provide locations for directive %v.`, config.Name)
+	}
+
+	args := make([]*QLArgument, len(config.Args))[:0]
+	for argName, argConfig := range config.Args {
+		assertValidName(argName)
+		args = append(args, &QLArgument{
+			Name:         argName,
+			Description:  argConfig.Description,
+			Type:         r.newInputType(argConfig.Type),
+			DefaultValue: argConfig.DefaultValue,
+		})
+	}
+	return &QLDirective{
+		Name:        config.Name,
+		Description: config.Description,
+		Args:        args,
+		Locations:   config.Locations,
+		Resolve:     config.Resolve,
+	}
+}
+
+func (r *_TypeRegistry) newAppliedDirectives(
+	typ QLNamedType,
+	fieldName string,
+	configs []ql.AppliedDirective,
+) []*QLAppliedDirective {
+	if len(configs) == 0 {
+		return nil
+	}
+	result := make([]*QLAppliedDirective, 0, len(configs))
+	for _, config := range configs {
+		if config.Name == CostDirectiveName {
+			continue
+		}
+		directive := r.directives[config.Name]
+		if directive == nil {
+			throw(`Unknown directive "%v" applied to %v.%v.`, config.Name, typ, fieldName)
+		}
+		if !directive.HasLocation(ql.LocationFieldDefinition) {
+			throw(`Directive "%v" may not be used on %v, but is applied to %v.%v.`,
+				config.Name, ql.LocationFieldDefinition, typ, fieldName)
+		}
+		args := make(map[string]interface{}, len(directive.Args))
+		for _, arg := range directive.Args {
+			if value, ok := config.Args[arg.Name]; ok {
+				args[arg.Name] = value
+			} else if arg.DefaultValue != nil {
+				args[arg.Name] = arg.DefaultValue
+			}
+		}
+		for name := range config.Args {
+			if _, ok := args[name]; !ok {
+				throw(`Unknown argument "%v" of directive "%v" applied to %v.%v.`,
+					name, config.Name, typ, fieldName)
+			}
+		}
+		result = append(result, &QLAppliedDirective{
+			Directive: directive,
+			Args:      args,
+		})
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
 }
+
+/**
+ * The name of the directive giving the cost of a field, applied as
+ * ql.AppliedDirective{Name: "cost", Args: {"value": n}} in the same way as
+ * @cost(value: n) in SDL. It is not a directive of the schema, so it has no
+ * effect on execution and is not listed by introspection.
+ */
+const CostDirectiveName = "cost"
+
+/**
+ * Returns the cost of a field: its Cost, or else the value of the cost
+ * directive applied to it, a zero value making the field free.
+ */
+func fieldCost(typ QLNamedType, fieldName string, config ql.Field) int {
+	if config.Cost != 0 {
+		return config.Cost
+	}
+	for _, directive := range config.Directives {
+		if directive.Name != CostDirectiveName {
+			continue
+		}
+		v := reflect.ValueOf(directive.Args["value"])
+		var cost int64 = -1
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			cost = v.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+			cost = int64(v.Uint())
+		}
+		switch {
+		case cost < 0 || len(directive.Args) != 1:
+			throw(`The cost of %v.%v must be given as @cost(value:) with a non-negative Int.`,
+				typ, fieldName)
+		case cost == 0:
+			return ql.FreeCost
+		}
+		return int(cost)
+	}
+	return 0
+}
+
+func (d *QLDirective) String() string {
+	return "@" + d.Name
+}
+
+/**
+ * Reports whether the directive may be used at the given location.
+ */
+func (d *QLDirective) HasLocation(location ql.DirectiveLocation) bool {
+	for _, l := range d.Locations {
+		if l == location {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * Used to conditionally include fields or fragments
+ */
+var QLIncludeDirective = NewQLDirective(ql.IncludeDirective)
+
+/**
+ * Used to conditionally skip (exclude) fields or fragments
+ */
+var QLSkipDirective = NewQLDirective(ql.SkipDirective)
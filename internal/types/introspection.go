@@ -1,10 +1,15 @@
 package types
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
 	"github.com/ng-vu/graphql-go/ql"
 )
 
-var __Schema = NewQLObject(__SchemaConfig)
 var __SchemaConfig = ql.Object{
 	Name:        "__Schema",
 	Description: "A QL Schema defines the capabilities of a QL server. It exposes all available types and directives on the server, as well as the entry points for query and mutation operations.",
@@ -12,34 +17,39 @@ var __SchemaConfig = ql.Object{
 		return ql.FieldMap{
 			"types": {
 				Description: "A list of all types supported by this server.",
-				Type:        ql.NonNull{ql.List{ql.NonNull{__TypeConfig}}},
-				Resolve: func(schema QLSchema) interface{} {
+				Type:        ql.NonNull{OfType: ql.List{OfType: ql.NonNull{OfType: __TypeConfig}}},
+				Resolve: func(schema QLSchema, args struct{}) interface{} {
 					typeMap := schema.GetTypeMap()
-					result := make(map[string]QLType)
-					for key, value := range typeMap {
-						result[key] = value
+					names := make([]string, len(typeMap))[:0]
+					for name := range typeMap {
+						names = append(names, name)
+					}
+					sort.Strings(names)
+					result := make([]QLType, len(names))
+					for i, name := range names {
+						result[i] = typeMap[name]
 					}
 					return result
 				},
 			},
 			"queryType": {
 				Description: "The type that query operations will be rooted at.",
-				Type:        ql.NonNull{__TypeConfig},
-				Resolve: func(schema QLSchema) interface{} {
+				Type:        ql.NonNull{OfType: __TypeConfig},
+				Resolve: func(schema QLSchema, args struct{}) interface{} {
 					return schema.GetQueryType()
 				},
 			},
 			"mutationType": {
 				Description: "If this server supports mutation, the type that mutation operations will be rooted at.",
 				Type:        __TypeConfig,
-				Resolve: func(schema QLSchema) interface{} {
+				Resolve: func(schema QLSchema, args struct{}) interface{} {
 					return schema.GetMutationType()
 				},
 			},
 			"directives": {
 				Description: "A list of all directives supported by this server.",
-				Type:        ql.NonNull{ql.List{ql.NonNull{__DirectiveConfig}}},
-				Resolve: func(schema QLSchema) interface{} {
+				Type:        ql.NonNull{OfType: ql.List{OfType: ql.NonNull{OfType: __DirectiveConfig}}},
+				Resolve: func(schema QLSchema, args struct{}) interface{} {
 					return schema.GetDirectives()
 				},
 			},
@@ -47,43 +57,149 @@ var __SchemaConfig = ql.Object{
 	},
 }
 
-var __Directive = NewQLObject(__DirectiveConfig)
 var __DirectiveConfig = ql.Object{
 	Name: "__Directive",
 	FieldsFunc: func() ql.FieldMap {
 		return ql.FieldMap{
-			"name":        {Type: ql.NonNull{ql.String}},
+			"name":        {Type: ql.NonNull{OfType: ql.String}},
 			"description": {Type: ql.String},
+			"locations": {
+				Type: ql.NonNull{OfType: ql.List{OfType: ql.NonNull{OfType: __DirectiveLocationConfig}}},
+			},
 			"args": {
-				Type: ql.NonNull{ql.List{ql.NonNull{__InputValueConfig}}},
-				Resolve: func(directive QLDirective) interface{} {
-					return directive.Args
+				Type: ql.NonNull{OfType: ql.List{OfType: ql.NonNull{OfType: __InputValueConfig}}},
+				Resolve: func(directive *QLDirective, args struct{}) interface{} {
+					return sortArguments(directive.Args)
+				},
+			},
+			"onOperation": {
+				Type:              ql.NonNull{OfType: ql.Boolean},
+				DeprecationReason: "Use `locations`.",
+				Resolve: func(directive *QLDirective, args struct{}) interface{} {
+					return directive.HasLocation(ql.LocationQuery) ||
+						directive.HasLocation(ql.LocationMutation) ||
+						directive.HasLocation(ql.LocationSubscription)
+				},
+			},
+			"onFragment": {
+				Type:              ql.NonNull{OfType: ql.Boolean},
+				DeprecationReason: "Use `locations`.",
+				Resolve: func(directive *QLDirective, args struct{}) interface{} {
+					return directive.HasLocation(ql.LocationFragmentSpread) ||
+						directive.HasLocation(ql.LocationInlineFragment) ||
+						directive.HasLocation(ql.LocationFragmentDefinition)
+				},
+			},
+			"onField": {
+				Type:              ql.NonNull{OfType: ql.Boolean},
+				DeprecationReason: "Use `locations`.",
+				Resolve: func(directive *QLDirective, args struct{}) interface{} {
+					return directive.HasLocation(ql.LocationField)
 				},
 			},
-			"onOperation": {Type: ql.NonNull{ql.Boolean}},
-			"onFragment":  {Type: ql.NonNull{ql.Boolean}},
-			"onField":     {Type: ql.NonNull{ql.Boolean}},
 		}
 	},
 }
 
-var __Field = NewQLObject(__FieldConfig)
+var __DirectiveLocationConfig = ql.Enum{
+	Name:        "__DirectiveLocation",
+	Description: "A Directive can be adjacent to many parts of the GraphQL language, a __DirectiveLocation describes one such possible adjacencies.",
+	Values: ql.EnumValueMap{
+		"QUERY": {
+			Value:       ql.LocationQuery,
+			Description: "Location adjacent to a query operation.",
+		},
+		"MUTATION": {
+			Value:       ql.LocationMutation,
+			Description: "Location adjacent to a mutation operation.",
+		},
+		"SUBSCRIPTION": {
+			Value:       ql.LocationSubscription,
+			Description: "Location adjacent to a subscription operation.",
+		},
+		"FIELD": {
+			Value:       ql.LocationField,
+			Description: "Location adjacent to a field.",
+		},
+		"FRAGMENT_DEFINITION": {
+			Value:       ql.LocationFragmentDefinition,
+			Description: "Location adjacent to a fragment definition.",
+		},
+		"FRAGMENT_SPREAD": {
+			Value:       ql.LocationFragmentSpread,
+			Description: "Location adjacent to a fragment spread.",
+		},
+		"INLINE_FRAGMENT": {
+			Value:       ql.LocationInlineFragment,
+			Description: "Location adjacent to an inline fragment.",
+		},
+		"VARIABLE_DEFINITION": {
+			Value:       ql.LocationVariableDefinition,
+			Description: "Location adjacent to a variable definition.",
+		},
+		"SCHEMA": {
+			Value:       ql.LocationSchema,
+			Description: "Location adjacent to a schema definition.",
+		},
+		"SCALAR": {
+			Value:       ql.LocationScalar,
+			Description: "Location adjacent to a scalar definition.",
+		},
+		"OBJECT": {
+			Value:       ql.LocationObject,
+			Description: "Location adjacent to an object type definition.",
+		},
+		"FIELD_DEFINITION": {
+			Value:       ql.LocationFieldDefinition,
+			Description: "Location adjacent to a field definition.",
+		},
+		"ARGUMENT_DEFINITION": {
+			Value:       ql.LocationArgumentDefinition,
+			Description: "Location adjacent to an argument definition.",
+		},
+		"INTERFACE": {
+			Value:       ql.LocationInterface,
+			Description: "Location adjacent to an interface definition.",
+		},
+		"UNION": {
+			Value:       ql.LocationUnion,
+			Description: "Location adjacent to a union definition.",
+		},
+		"ENUM": {
+			Value:       ql.LocationEnum,
+			Description: "Location adjacent to an enum definition.",
+		},
+		"ENUM_VALUE": {
+			Value:       ql.LocationEnumValue,
+			Description: "Location adjacent to an enum value definition.",
+		},
+		"INPUT_OBJECT": {
+			Value:       ql.LocationInputObject,
+			Description: "Location adjacent to an input object type definition.",
+		},
+		"INPUT_FIELD_DEFINITION": {
+			Value:       ql.LocationInputFieldDefinition,
+			Description: "Location adjacent to an input object field definition.",
+		},
+	},
+}
+
 var __FieldConfig = ql.Object{
 	Name: "__Field",
 	FieldsFunc: func() ql.FieldMap {
 		return ql.FieldMap{
-			"name":        {Type: ql.NonNull{ql.String}},
+			"name":        {Type: ql.NonNull{OfType: ql.String}},
 			"description": {Type: ql.String},
 			"args": {
-				Type: ql.NonNull{ql.List{ql.NonNull{__InputValueConfig}}},
-				Resolve: func(field QLFieldDefinition) interface{} {
-					return field.Args
+				Type: ql.NonNull{OfType: ql.List{OfType: ql.NonNull{OfType: __InputValueConfig}}},
+				Resolve: func(field *QLFieldDefinition, args struct{}) interface{} {
+					return sortArguments(field.Args)
 				},
 			},
-			"type": {Type: ql.NonNull{__TypeConfig}},
+			"type": {Type: ql.NonNull{OfType: __TypeConfig}},
 			"isDeprecated": {
-				Type: ql.NonNull{ql.Boolean},
-				Resolve: func(field QLFieldDefinition) interface{} {
+				Type: ql.NonNull{OfType: ql.Boolean},
+				Resolve: func(field *QLFieldDefinition, args struct{}) interface{} {
 					return field.DeprecationReason != ""
 				},
 			},
@@ -92,19 +208,22 @@ var __FieldConfig = ql.Object{
 	},
 }
 
-var __InputValue = NewQLObject(__InputValueConfig)
 var __InputValueConfig = ql.Object{
 	Name: "__InputValue",
 	FieldsFunc: func() ql.FieldMap {
 		return ql.FieldMap{
-			"name":        {Type: ql.NonNull{ql.String}},
+			"name":        {Type: ql.NonNull{OfType: ql.String}},
 			"description": {Type: ql.String},
-			"type":        {Type: ql.NonNull{__TypeConfig}},
+			"type":        {Type: ql.NonNull{OfType: __TypeConfig}},
 			"defaultValue": {
 				Type: ql.String,
-				Resolve: func(inputVal *QLInputObject) interface{} {
-					// return inputVal.DefaultValue
-					// TODO
+				Resolve: func(inputVal interface{}, args struct{}) interface{} {
+					switch inputVal := inputVal.(type) {
+					case *QLArgument:
+						return printDefaultValue(inputVal.DefaultValue, inputVal.Type)
+					case *InputObjectField:
+						return printDefaultValue(inputVal.DefaultValue, inputVal.Type)
+					}
 					return nil
 				},
 			},
@@ -112,19 +231,19 @@ var __InputValueConfig = ql.Object{
 	},
 }
 
-var __EnumValue = NewQLObject(__EnumValueConfig)
 var __EnumValueConfig = ql.Object{
 	Name: "__EnumValue",
 	FieldsFunc: func() ql.FieldMap {
 		return ql.FieldMap{
-			"name":        {Type: ql.NonNull{ql.String}},
+			"name":        {Type: ql.NonNull{OfType: ql.String}},
 			"description": {Type: ql.String},
 			"isDeprecated": {
-				Type: ql.NonNull{ql.Boolean},
-				Resolve: func(enumValue QLEnumValueDefinition) interface{} {
-					return enumValue.DeprecationReason
+				Type: ql.NonNull{OfType: ql.Boolean},
+				Resolve: func(enumValue *QLEnumValueDefinition, args struct{}) interface{} {
+					return enumValue.DeprecationReason != ""
 				},
 			},
+			"deprecationReason": {Type: ql.String},
 		}
 	},
 }
@@ -140,7 +259,6 @@ const (
 	TYPE_NON_NULL     = "NON_NULL"
 )
 
-var __TypeKind = NewQLEnum(__TypeKindConfig)
 var __TypeKindConfig = ql.Enum{
 	Name:        "__TypeKind",
 	Description: "An enum describing what kind of type a given __Type is",
@@ -180,7 +298,6 @@ var __TypeKindConfig = ql.Enum{
 	},
 }
 
-var __Type *QLObject
 var __TypeConfig ql.Object
 var SchemaMetaFieldDef, TypeMetaFieldDef, TypeNameMetaFieldDef *QLFieldDefinition
 
@@ -190,66 +307,120 @@ func init() {
 		FieldsFunc: func() ql.FieldMap {
 			return ql.FieldMap{
 				"kind": {
-					Type: ql.NonNull{__TypeKindConfig},
+					Type: ql.NonNull{OfType: __TypeKindConfig},
+					Resolve: func(typ QLType, args struct{}) interface{} {
+						switch typ.(type) {
+						case *QLScalar:
+							return TYPE_SCALAR
+						case *QLObject:
+							return TYPE_OBJECT
+						case *QLInterface:
+							return TYPE_INTERFACE
+						case *QLUnion:
+							return TYPE_UNION
+						case *QLEnum:
+							return TYPE_ENUM
+						case *QLInputObject:
+							return TYPE_INPUT_OBJECT
+						case *QLList:
+							return TYPE_LIST
+						case *QLNonNull:
+							return TYPE_NON_NULL
+						}
+						throw("Unknown kind of type: %v", typ)
+						return nil
+					},
+				},
+				"name": {
+					Type: ql.String,
+					Resolve: func(typ QLType, args struct{}) interface{} {
+						if name := typ.GetName(); name != "" {
+							return name
+						}
+						return nil
+					},
 				},
-				"name":        {Type: ql.String},
 				"description": {Type: ql.String},
 				"fields": {
-					Type: ql.List{ql.NonNull{__FieldConfig}},
+					Type: ql.List{OfType: ql.NonNull{OfType: __FieldConfig}},
 					Args: ql.ArgumentMap{
 						"includeDeprecated": {Type: ql.Boolean, DefaultValue: false},
 					},
 					Resolve: func(typ QLType, args struct{ IncludeDeprecated bool }) interface{} {
-						if typ, ok := typ.(QLObjectInterface); ok {
-							fieldMap := typ.GetFields()
-							fields := make([]QLFieldDefinition, len(fieldMap))[:0]
-							for _, field := range fieldMap {
-								if args.IncludeDeprecated || field.DeprecationReason == "" {
-									fields = append(fields, field)
-								}
+						var fieldMap map[string]*QLFieldDefinition
+						switch typ := typ.(type) {
+						case *QLObject:
+							fieldMap = typ.GetFields()
+						case *QLInterface:
+							fieldMap = typ.GetFields()
+						default:
+							return nil
+						}
+						fields := make([]*QLFieldDefinition, len(fieldMap))[:0]
+						for _, field := range fieldMap {
+							if args.IncludeDeprecated || field.DeprecationReason == "" {
+								fields = append(fields, field)
 							}
-							return fields
 						}
-						return nil
+						sort.Sort(fieldsByName(fields))
+						return fields
 					},
 				},
 				"interfaces": {
-					Type: ql.List{ql.NonNull{__TypeConfig}},
-					Resolve: func(typ QLObject) interface{} {
-						return typ.GetInterfaces()
+					Type: ql.List{OfType: ql.NonNull{OfType: __TypeConfig}},
+					Resolve: func(typ QLType, args struct{}) interface{} {
+						switch typ := typ.(type) {
+						case *QLObject:
+							return typ.GetInterfaces()
+						case *QLInterface:
+							return typ.GetInterfaces()
+						}
+						return nil
 					},
 				},
 				"possibleTypes": {
-					Type: ql.List{ql.NonNull{__TypeConfig}},
-					Resolve: func(typ QLAbstractType) interface{} {
-						return typ.GetPossibleTypes()
+					Type: ql.List{OfType: ql.NonNull{OfType: __TypeConfig}},
+					Resolve: func(typ QLType, args struct{}) interface{} {
+						if typ, ok := typ.(QLAbstractType); ok {
+							return typ.GetPossibleTypes()
+						}
+						return nil
 					},
 				},
 				"enumValues": {
-					Type: ql.List{ql.NonNull{__EnumValueConfig}},
+					Type: ql.List{OfType: ql.NonNull{OfType: __EnumValueConfig}},
 					Args: ql.ArgumentMap{
 						"includeDeprecated": {Type: ql.Boolean, DefaultValue: false},
 					},
-					// TODO(qv): Resolve
-					Resolve: func(typ QLEnum, args struct{ IncludeDeprecated bool }) interface{} {
-						vs := typ.GetValues()
+					Resolve: func(typ QLType, args struct{ IncludeDeprecated bool }) interface{} {
+						enum, ok := typ.(*QLEnum)
+						if !ok {
+							return nil
+						}
+						vs := enum.GetValues()
 						values := make([]*QLEnumValueDefinition, len(vs))[:0]
 						for _, v := range vs {
 							if args.IncludeDeprecated || v.DeprecationReason == "" {
 								values = append(values, v)
 							}
 						}
+						sort.Sort(enumValuesByName(values))
 						return values
 					},
 				},
 				"inputFields": {
-					Type: ql.List{ql.NonNull{__InputValueConfig}},
-					Resolve: func(typ *QLInputObject) interface{} {
-						fieldMap := typ.GetFields()
+					Type: ql.List{OfType: ql.NonNull{OfType: __InputValueConfig}},
+					Resolve: func(typ QLType, args struct{}) interface{} {
+						inputObject, ok := typ.(*QLInputObject)
+						if !ok {
+							return nil
+						}
+						fieldMap := inputObject.GetFields()
 						result := make([]*InputObjectField, len(fieldMap))[:0]
 						for _, field := range fieldMap {
 							result = append(result, field)
 						}
+						sort.Sort(inputFieldsByName(result))
 						return result
 					},
 				},
@@ -258,22 +429,34 @@ func init() {
 		},
 	}
 
-	__Type = NewQLObject(__TypeConfig)
+	SchemaMetaFieldDef, TypeMetaFieldDef, TypeNameMetaFieldDef =
+		newMetaFieldDefs(newTypeRegistry())
+}
 
-	SchemaMetaFieldDef = &QLFieldDefinition{
+/**
+ * Creates the introspection fields along with the introspection types in the
+ * registry of a schema, so that they share its scalars.
+ */
+func newMetaFieldDefs(types *_TypeRegistry) (schemaMetaFieldDef, typeMetaFieldDef, typeNameMetaFieldDef *QLFieldDefinition) {
+	schemaMetaFieldDef = &QLFieldDefinition{
 		Name:        "__schema",
-		Type:        NewQLOutputType(ql.NonNull{__SchemaConfig}),
+		Type:        types.newOutputType(ql.NonNull{OfType: __SchemaConfig}),
 		Description: "Access the current type schema of this server.",
 		Resolve: func(source interface{}, args map[string]interface{}, info QLResolveInfo) interface{} {
 			return info.Schema
 		},
 	}
 
-	TypeMetaFieldDef = &QLFieldDefinition{
+	typeMetaFieldDef = &QLFieldDefinition{
 		Name:        "__type",
-		Type:        NewQLOutputType(__TypeConfig),
+		Type:        types.newOutputType(__TypeConfig),
 		Description: "Request the type information of a single type.",
-		Args:        []*QLArgument{},
+		Args: []*QLArgument{
+			{
+				Name: "name",
+				Type: types.newInputType(ql.NonNull{OfType: ql.String}),
+			},
+		},
 		Resolve: func(source interface{}, args map[string]interface{}, info QLResolveInfo) interface{} {
 			if name, ok := args["name"].(string); ok {
 				return info.Schema.GetType(name)
@@ -282,12 +465,103 @@ func init() {
 		},
 	}
 
-	TypeNameMetaFieldDef = &QLFieldDefinition{
+	typeNameMetaFieldDef = &QLFieldDefinition{
 		Name:        "__typename",
-		Type:        NewQLOutputType(ql.NonNull{ql.String}),
+		Type:        types.newOutputType(ql.NonNull{OfType: ql.String}),
 		Description: "The name of the current Object type at runtime.",
 		Resolve: func(source interface{}, args map[string]interface{}, info QLResolveInfo) interface{} {
 			return info.ParentType.GetName()
 		},
 	}
+	return
+}
+
+type fieldsByName []*QLFieldDefinition
+
+func (s fieldsByName) Len() int           { return len(s) }
+func (s fieldsByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s fieldsByName) Less(i, j int) bool { return s[i].Name < s[j].Name }
+
+type enumValuesByName []*QLEnumValueDefinition
+
+func (s enumValuesByName) Len() int           { return len(s) }
+func (s enumValuesByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s enumValuesByName) Less(i, j int) bool { return s[i].Name < s[j].Name }
+
+type inputFieldsByName []*InputObjectField
+
+func (s inputFieldsByName) Len() int           { return len(s) }
+func (s inputFieldsByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s inputFieldsByName) Less(i, j int) bool { return s[i].Name < s[j].Name }
+
+type argumentsByName []*QLArgument
+
+func (s argumentsByName) Len() int           { return len(s) }
+func (s argumentsByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s argumentsByName) Less(i, j int) bool { return s[i].Name < s[j].Name }
+
+func sortArguments(args []*QLArgument) []*QLArgument {
+	result := make([]*QLArgument, len(args))
+	copy(result, args)
+	sort.Sort(argumentsByName(result))
+	return result
+}
+
+/**
+ * Prints a default value as a QL literal of the given type, or returns nil
+ * when there is no default value.
+ */
+func printDefaultValue(value interface{}, typ QLInputType) interface{} {
+	if value == nil {
+		return nil
+	}
+	return printValue(value, typ)
+}
+
+func printValue(value interface{}, typ QLType) string {
+	if typ, ok := typ.(*QLNonNull); ok {
+		return printValue(value, typ.OfType)
+	}
+	v := reflect.ValueOf(value)
+	switch typ := typ.(type) {
+	case *QLList:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return printValue(value, typ.OfType)
+		}
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = printValue(v.Index(i).Interface(), typ.OfType)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+
+	case *QLInputObject:
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			break
+		}
+		names := make([]string, len(fields))[:0]
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		items := make([]string, len(names))
+		for i, name := range names {
+			var fieldType QLType
+			if field := typ.GetFields()[name]; field != nil {
+				fieldType = field.Type
+			}
+			items[i] = name + ": " + printValue(fields[name], fieldType)
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+
+	case *QLEnum:
+		if name := typ.Serialize(value); name != "" {
+			return name
+		}
+	}
+	if s, ok := value.(string); ok {
+		data, _ := json.Marshal(s)
+		return string(data)
+	}
+	return fmt.Sprint(value)
 }
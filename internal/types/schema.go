@@ -1,6 +1,9 @@
 package types
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/ng-vu/graphql-go/ql"
 )
 
@@ -10,41 +13,128 @@ type QLSchema struct {
 	directives   []*QLDirective
 
 	typeMap map[string]QLType
+
+	schemaMetaFieldDef   *QLFieldDefinition
+	typeMetaFieldDef     *QLFieldDefinition
+	typeNameMetaFieldDef *QLFieldDefinition
+
+	queryConfig      ql.Object
+	mutationConfig   *ql.Object
+	directiveConfigs []ql.Directive
+	visible          func(tags []string) bool
 }
 
-func NewQLSchema(query ql.Object, mutation *ql.Object) QLSchema {
-	queryType := NewQLObject(query)
+/**
+ * Creates a schema from its root types. The given directives are supported in
+ * addition to the built-in @include and @skip.
+ */
+func NewQLSchema(query ql.Object, mutation *ql.Object, directives ...ql.Directive) QLSchema {
+	return newQLSchema(query, mutation, directives, nil)
+}
+
+func newQLSchema(
+	query ql.Object,
+	mutation *ql.Object,
+	directiveConfigs []ql.Directive,
+	visible func(tags []string) bool,
+) QLSchema {
+	types := newTypeRegistry()
+	types.visible = visible
+	if !types.isVisible(query.Visibility) {
+		throw(`Query type %v must be visible.`, query.Name)
+	}
+	queryType := types.newObject(query)
 	var mutationType *QLObject
-	if mutation != nil {
-		mutationType = NewQLObject(*mutation)
+	if mutation != nil && types.isVisible(mutation.Visibility) {
+		mutationType = types.newObject(*mutation)
 	}
 
+	directives := make([]*QLDirective, 0, len(directiveConfigs)+2)
+	directives = append(directives,
+		types.newDirective(ql.IncludeDirective),
+		types.newDirective(ql.SkipDirective))
+	for _, config := range directiveConfigs {
+		for _, directive := range directives {
+			if directive.Name == config.Name {
+				throw(`Schema must contain unique named directives but contains multiple directives named %v`, config.Name)
+			}
+		}
+		directives = append(directives, types.newDirective(config))
+	}
+	types.directives = make(map[string]*QLDirective, len(directives))
+	for _, directive := range directives {
+		types.directives[directive.Name] = directive
+	}
+
+	schemaMetaFieldDef, typeMetaFieldDef, typeNameMetaFieldDef := newMetaFieldDefs(types)
+
 	typeMap := make(map[string]QLType)
 	if mutationType == nil {
 		typeMapReducer(typeMap, queryType)
 	} else {
 		typeMapReducer(typeMap, queryType, mutationType)
 	}
+	for _, directive := range directives {
+		for _, arg := range directive.Args {
+			typeMapReducer(typeMap, arg.Type)
+		}
+	}
+	typeMapReducer(typeMap, schemaMetaFieldDef.Type)
 
-	for _, typ := range typeMap {
-		if typ, ok := typ.(*QLObject); ok {
+	typeNames := make([]string, len(typeMap))[:0]
+	for name := range typeMap {
+		typeNames = append(typeNames, name)
+	}
+	sort.Strings(typeNames)
+	for _, name := range typeNames {
+		switch typ := typeMap[name].(type) {
+		case *QLObject:
 			for _, iface := range typ.GetInterfaces() {
-				assertObjectImplementsInterface(typ, iface)
+				assertImplementsInterface(typ, iface)
+				iface.implementations = append(iface.implementations, typ)
+			}
+		case *QLInterface:
+			assertNoImplementationCycle(typ)
+			for _, iface := range typ.GetInterfaces() {
+				assertImplementsInterface(typ, iface)
 			}
 		}
 	}
 
-	directives := []*QLDirective{
-		QLIncludeDirective,
-		QLSkipDirective,
-	}
-
 	return QLSchema{
 		queryType:    queryType,
 		mutationType: mutationType,
 		directives:   directives,
 		typeMap:      typeMap,
+
+		schemaMetaFieldDef:   schemaMetaFieldDef,
+		typeMetaFieldDef:     typeMetaFieldDef,
+		typeNameMetaFieldDef: typeNameMetaFieldDef,
+
+		queryConfig:      query,
+		mutationConfig:   mutation,
+		directiveConfigs: directiveConfigs,
+		visible:          visible,
+	}
+}
+
+/**
+ * Returns a view of the schema built again from the same configs, but
+ * without the types, fields, arguments, input fields and enum values whose
+ * visibility tags are rejected by visible. Untagged elements are always kept.
+ * Fields and arguments of a hidden type are hidden too, and the types no
+ * longer reachable from the root types are left out of the type map.
+ *
+ * Filtering a view keeps the elements accepted by both filters.
+ */
+func (g QLSchema) Filter(visible func(tags []string) bool) QLSchema {
+	if prev := g.visible; prev != nil {
+		next := visible
+		visible = func(tags []string) bool {
+			return prev(tags) && next(tags)
+		}
 	}
+	return newQLSchema(g.queryConfig, g.mutationConfig, g.directiveConfigs, visible)
 }
 
 func (g QLSchema) GetQueryType() *QLObject {
@@ -71,6 +161,27 @@ func (g QLSchema) GetDirectives() []*QLDirective {
 	return g.directives
 }
 
+/**
+ * Returns the introspection field with the given name if it may be selected
+ * on parentType, or nil. The __schema and __type fields are only available
+ * on the query type, while __typename is available on every composite type.
+ */
+func (g QLSchema) GetMetaFieldDef(parentType QLCompositeType, name string) *QLFieldDefinition {
+	switch name {
+	case g.schemaMetaFieldDef.Name:
+		if parentType == QLCompositeType(g.queryType) {
+			return g.schemaMetaFieldDef
+		}
+	case g.typeMetaFieldDef.Name:
+		if parentType == QLCompositeType(g.queryType) {
+			return g.typeMetaFieldDef
+		}
+	case g.typeNameMetaFieldDef.Name:
+		return g.typeNameMetaFieldDef
+	}
+	return nil
+}
+
 func (g QLSchema) GetDirective(name string) *QLDirective {
 	for _, directive := range g.directives {
 		if directive.Name == name {
@@ -120,13 +231,22 @@ func typeMapReducer(typeMap map[string]QLType, types ...QLType) {
 			typeMapReducer(typeMap, innerTypes...)
 		}
 
+		if typ, ok := typ.(*QLInterface); ok {
+			interfaces := typ.GetInterfaces()
+			innerTypes := make([]QLType, len(interfaces))
+			for i, t := range interfaces {
+				innerTypes[i] = t
+			}
+			typeMapReducer(typeMap, innerTypes...)
+		}
+
 		switch typ := typ.(type) {
 		case *QLObject:
 			for _, field := range typ.GetFields() {
 				argTypes := field.Args
 				innerTypes := make([]QLType, len(argTypes))
 				for i, t := range argTypes {
-					innerTypes[i] = t
+					innerTypes[i] = t.Type
 				}
 				typeMapReducer(typeMap, innerTypes...)
 				typeMapReducer(typeMap, field.Type)
@@ -137,7 +257,7 @@ func typeMapReducer(typeMap map[string]QLType, types ...QLType) {
 				argTypes := field.Args
 				innerTypes := make([]QLType, len(argTypes))
 				for i, t := range argTypes {
-					innerTypes[i] = t
+					innerTypes[i] = t.Type
 				}
 				typeMapReducer(typeMap, innerTypes...)
 				typeMapReducer(typeMap, field.Type)
@@ -151,7 +271,55 @@ func typeMapReducer(typeMap map[string]QLType, types ...QLType) {
 	}
 }
 
-func assertObjectImplementsInterface(object *QLObject, iface *QLInterface) {
+/**
+ * An object or an interface type, which may implement interfaces.
+ */
+type _ImplementingType interface {
+	QLNamedType
+	GetFields() map[string]*QLFieldDefinition
+	GetInterfaces() []*QLInterface
+}
+
+/**
+ * Panics when the interfaces implemented by iface lead back to iface.
+ */
+func assertNoImplementationCycle(iface *QLInterface) {
+	visited := make(map[string]bool)
+	var visit func(path []string, typ *QLInterface)
+	visit = func(path []string, typ *QLInterface) {
+		for _, next := range typ.GetInterfaces() {
+			if next == iface {
+				throw(`Interface %v cannot implement itself: %v.`,
+					iface, strings.Join(append(path, next.Name), " -> "))
+			}
+			if !visited[next.Name] {
+				visited[next.Name] = true
+				visit(append(path, next.Name), next)
+			}
+		}
+	}
+	visit([]string{iface.Name}, iface)
+}
+
+/**
+ * Panics when object, an object or an interface type, does not provide the
+ * fields of iface or does not also implement the interfaces of iface.
+ */
+func assertImplementsInterface(object _ImplementingType, iface *QLInterface) {
+	for _, transitive := range iface.GetInterfaces() {
+		ok := false
+		for _, other := range object.GetInterfaces() {
+			if other == transitive {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			throw(`Type %v must implement %v because it is implemented by %v.`,
+				object, transitive, iface)
+		}
+	}
+
 	objectFieldMap := object.GetFields()
 	ifaceFieldMap := iface.GetFields()
 
@@ -107,12 +107,6 @@ func ASTFromValue(value interface{}, typ typs.QLType) lang.IValue {
 	return &lang.ObjectValue{
 		Fields: fields,
 	}
-
-	return nil
-}
-
-func BuildASTSchema() *typs.QLSchema {
-	return nil
 }
 
 func BuildClientSchema() *typs.QLSchema {
@@ -157,7 +151,10 @@ func TypeFromAST(schema typs.QLSchema, inputTypeAST lang.IType) typs.QLType {
  * | Boolean              | Boolean       |
  * | String / Enum Value  | String        |
  * | Int / Float          | Number        |
+ * | Null                 | null          |
  *
+ * The fields of an input object which are not provided take their default
+ * value, while the ones explicitly given null are null.
  */
 func ValueFromAST(
 	valueAST lang.IValue,
@@ -169,7 +166,7 @@ func ValueFromAST(
 		return ValueFromAST(valueAST, nullableType, variables)
 	}
 
-	if IsNil(valueAST) {
+	if isNullValue(valueAST) {
 		return nil
 	}
 
@@ -188,7 +185,8 @@ func ValueFromAST(
 			}
 			return result
 		}
-		return []interface{}{ValueFromAST(valueAST, typ, variables)}
+		itemType := typ.OfType.(typs.QLInputType)
+		return []interface{}{ValueFromAST(valueAST, itemType, variables)}
 
 	case *typs.QLInputObject:
 		valueAST, ok := valueAST.(*lang.ObjectValue)
@@ -204,20 +202,23 @@ func ValueFromAST(
 		result := make(map[string]interface{})
 		fields := typ.GetFields()
 		for fieldName, field := range fields {
-			fieldAST := fieldASTMap[fieldName]
-			var fieldValue interface{}
-			if fieldAST != nil {
-				fieldValue = ValueFromAST(fieldAST.Value, field.Type, variables)
+			var fieldValueAST lang.IValue
+			if fieldAST := fieldASTMap[fieldName]; fieldAST != nil {
+				fieldValueAST = fieldAST.Value
 			}
-			if IsNil(fieldValue) {
-				fieldValue = field.DefaultValue
-			}
-			if !IsNil(fieldValue) {
-				result[fieldName] = fieldValue
+			if !IsValueProvided(fieldValueAST, variables) {
+				if !IsNil(field.DefaultValue) {
+					result[fieldName] = field.DefaultValue
+				}
+				continue
 			}
+			result[fieldName] = ValueFromAST(fieldValueAST, field.Type, variables)
 		}
 		return result
 
+	case *typs.QLEnum:
+		return typ.ParseLiteral(valueAST)
+
 	case *typs.QLScalar:
 		parsed := typ.ParseLiteral(valueAST)
 		if IsNil(parsed) {
@@ -230,3 +231,19 @@ func ValueFromAST(
 		return nil
 	}
 }
+
+/**
+ * Reports whether a value is provided by the value AST, even if it is null,
+ * rather than left to the default value: a missing value or a variable which
+ * is not given does not provide one.
+ */
+func IsValueProvided(valueAST lang.IValue, variables map[string]interface{}) bool {
+	if IsNil(valueAST) {
+		return false
+	}
+	if variable, ok := valueAST.(*lang.Variable); ok {
+		_, ok := variables[variable.Name.Value]
+		return ok
+	}
+	return true
+}
@@ -0,0 +1,624 @@
+package utilities
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+/**
+ * The Go values implementing a schema written in SDL.
+ */
+type BuildASTSchemaConfig struct {
+	// Resolvers maps the name of an object type to the resolve functions of
+	// its fields, as given to ql.Field.Resolve. The fields without resolver
+	// are resolved from the source value.
+	Resolvers map[string]map[string]interface{}
+
+	// Scalars are the configs of the custom scalars, by name. The values of a
+	// custom scalar without config are given as they are, and its literals as
+	// their string value.
+	Scalars map[string]ql.Scalar
+
+	// ResolveTypes are the ResolveType functions of the interfaces and the
+	// unions, by name.
+	ResolveTypes map[string]func(v interface{}, info interface{}) interface{}
+
+	// Directives are added to the schema, and replace the directive
+	// definitions of the same name in the SDL.
+	Directives []ql.Directive
+}
+
+/**
+ * The configs of a schema built from SDL, to be given to NewQLSchema.
+ */
+type ASTSchema struct {
+	Query      ql.Object
+	Mutation   *ql.Object
+	Directives []ql.Directive
+}
+
+var builtInScalars = map[string]ql.Scalar{
+	"Int":     ql.Int,
+	"Float":   ql.Float,
+	"String":  ql.String,
+	"Boolean": ql.Boolean,
+	"ID":      ql.ID,
+}
+
+/**
+ * Builds the configs of a schema from the type system definitions of a
+ * document. The extensions are merged into the types they extend, so that a
+ * schema can be split into several documents appended together.
+ *
+ * The root types are given by the schema definition, or are the types named
+ * Query and Mutation. The field definitions marked @deprecated are
+ * deprecated, the ones marked @cost(value: Int!) have the given cost, and the
+ * other directives applied to them are applied to the fields of the schema.
+ */
+func BuildASTSchema(document *lang.Document, config BuildASTSchemaConfig) (ASTSchema, []error) {
+	definitions, errors := mergeExtensions(document)
+	if len(errors) > 0 {
+		return ASTSchema{}, errors
+	}
+
+	b := &_SchemaBuilder{
+		config:      config,
+		definitions: definitions,
+		types:       make(map[string]ql.Type),
+		directives:  make(map[string]ql.Directive),
+	}
+	b.checkTypes()
+	b.checkResolvers()
+	query := b.rootType(lang.OperationQuery, "Query", true)
+	mutation := b.rootType(lang.OperationMutation, "Mutation", false)
+	if schema := definitions.schema; schema != nil {
+		for _, operationType := range schema.OperationTypes {
+			if operationType.Operation == lang.OperationSubscription {
+				b.errorf([]lang.INode{operationType}, "Subscriptions are not supported.")
+			}
+		}
+	}
+	if len(b.errors) > 0 {
+		return ASTSchema{}, b.errors
+	}
+
+	return ASTSchema{
+		Query:      *query,
+		Mutation:   mutation,
+		Directives: b.buildDirectives(),
+	}, nil
+}
+
+type _SchemaBuilder struct {
+	config      BuildASTSchemaConfig
+	definitions *_Definitions
+	errors      []error
+
+	types      map[string]ql.Type
+	directives map[string]ql.Directive
+}
+
+func (b *_SchemaBuilder) errorf(nodes []lang.INode, format string, args ...interface{}) {
+	b.errors = append(b.errors, lang.NewQLError(fmt.Sprintf(format, args...), nodes))
+}
+
+/**
+ * Reports the references to unknown types, and the types used where they are
+ * not allowed.
+ */
+func (b *_SchemaBuilder) checkTypes() {
+	if schema := b.definitions.schema; schema != nil {
+		for _, operationType := range schema.OperationTypes {
+			b.checkNamedType(operationType.Type)
+		}
+	}
+
+	for _, name := range b.definitions.typeNames {
+		switch definition := b.definitions.types[name].(type) {
+		case *lang.ObjectTypeDefinition:
+			b.checkInterfaces(name, definition.Interfaces)
+			b.checkFields(name, definition.Fields)
+
+		case *lang.InterfaceTypeDefinition:
+			b.checkInterfaces(name, definition.Interfaces)
+			b.checkFields(name, definition.Fields)
+
+		case *lang.UnionTypeDefinition:
+			for _, member := range definition.Types {
+				definition := b.checkNamedType(member)
+				if _, ok := definition.(*lang.ObjectTypeDefinition); definition != nil && !ok {
+					b.errorf([]lang.INode{member},
+						`Union type "%v" can only include Object types, it cannot include "%v".`,
+						name, member.Name.Value)
+				}
+			}
+
+		case *lang.InputObjectTypeDefinition:
+			for _, field := range definition.Fields {
+				b.checkInputType(name+"."+field.Name.Value, field.Type)
+			}
+		}
+	}
+
+	for _, directive := range b.definitions.directives {
+		for _, arg := range directive.Arguments {
+			b.checkInputType(fmt.Sprintf("@%v(%v:)", directive.Name.Value, arg.Name.Value), arg.Type)
+		}
+	}
+}
+
+func (b *_SchemaBuilder) checkInterfaces(typeName string, interfaces []*lang.NamedType) {
+	for _, iface := range interfaces {
+		definition := b.checkNamedType(iface)
+		if _, ok := definition.(*lang.InterfaceTypeDefinition); definition != nil && !ok {
+			b.errorf([]lang.INode{iface},
+				`Type "%v" must only implement Interface types, it cannot implement "%v".`,
+				typeName, iface.Name.Value)
+		}
+	}
+}
+
+func (b *_SchemaBuilder) checkFields(typeName string, fields []*lang.FieldDefinition) {
+	for _, field := range fields {
+		coordinate := typeName + "." + field.Name.Value
+		switch b.checkNamedType(namedTypeOf(field.Type)).(type) {
+		case *lang.InputObjectTypeDefinition:
+			b.errorf([]lang.INode{field.Type},
+				`The type of %v must be Output Type but got: %v.`, coordinate, lang.Print(field.Type))
+		}
+		for _, arg := range field.Arguments {
+			b.checkInputType(fmt.Sprintf("%v(%v:)", coordinate, arg.Name.Value), arg.Type)
+		}
+		if _, ok := fieldCost(field.Directives); !ok {
+			b.errorf([]lang.INode{field},
+				`The cost of %v must be given as @cost(value:) with a non-negative Int.`, coordinate)
+		}
+	}
+}
+
+func (b *_SchemaBuilder) checkInputType(coordinate string, typ lang.IType) {
+	switch b.checkNamedType(namedTypeOf(typ)).(type) {
+	case *lang.ObjectTypeDefinition, *lang.InterfaceTypeDefinition, *lang.UnionTypeDefinition:
+		b.errorf([]lang.INode{typ},
+			`The type of %v must be Input Type but got: %v.`, coordinate, lang.Print(typ))
+	}
+}
+
+/**
+ * Returns the definition of a named type, or nil for the built-in scalars and
+ * the unknown types, which are reported.
+ */
+func (b *_SchemaBuilder) checkNamedType(node *lang.NamedType) lang.ITypeDefinition {
+	name := node.Name.Value
+	if _, ok := builtInScalars[name]; ok {
+		return nil
+	}
+	definition := b.definitions.types[name]
+	if definition == nil {
+		b.errorf([]lang.INode{node}, `Unknown type "%v".`, name)
+	}
+	return definition
+}
+
+/**
+ * Reports the resolvers given for undefined fields, which are likely typos.
+ */
+func (b *_SchemaBuilder) checkResolvers() {
+	typeNames := make([]string, 0, len(b.config.Resolvers))
+	for typeName := range b.config.Resolvers {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	for _, typeName := range typeNames {
+		object, ok := b.definitions.types[typeName].(*lang.ObjectTypeDefinition)
+		if !ok {
+			b.errorf(nil, `Resolvers are given for "%v", which is not an object type.`, typeName)
+			continue
+		}
+		fieldNames := make([]string, 0, len(b.config.Resolvers[typeName]))
+		for fieldName := range b.config.Resolvers[typeName] {
+			fieldNames = append(fieldNames, fieldName)
+		}
+		sort.Strings(fieldNames)
+	FIELDS:
+		for _, fieldName := range fieldNames {
+			for _, field := range object.Fields {
+				if field.Name.Value == fieldName {
+					continue FIELDS
+				}
+			}
+			b.errorf(nil, `A resolver is given for "%v.%v", which is not defined.`, typeName, fieldName)
+		}
+	}
+}
+
+func (b *_SchemaBuilder) rootType(operation lang.OperationType, defaultName string, required bool) *ql.Object {
+	name := defaultName
+	var node lang.INode
+	if schema := b.definitions.schema; schema != nil {
+		for _, operationType := range schema.OperationTypes {
+			if operationType.Operation == operation {
+				name = operationType.Type.Name.Value
+				node = operationType
+			}
+		}
+	}
+
+	definition := b.definitions.types[name]
+	switch definition.(type) {
+	case *lang.ObjectTypeDefinition:
+		object := b.namedType(name).(ql.Object)
+		return &object
+	case nil:
+		// An unknown type given by the schema definition is already reported.
+		if required && node == nil {
+			b.errorf(nil, `%v root type must be provided.`, defaultName)
+		}
+	default:
+		if node == nil {
+			node = typeDefinitionName(definition)
+		}
+		b.errorf([]lang.INode{node},
+			`%v root type must be Object type, it cannot be "%v".`, defaultName, name)
+	}
+	return nil
+}
+
+func (b *_SchemaBuilder) typeRef(node lang.IType) ql.Type {
+	switch node := node.(type) {
+	case *lang.NonNullType:
+		return ql.NonNull{OfType: b.typeRef(node.Type)}
+	case *lang.ListType:
+		return ql.List{OfType: b.typeRef(node.Type)}
+	case *lang.NamedType:
+		return b.namedType(node.Name.Value)
+	}
+	return nil
+}
+
+func (b *_SchemaBuilder) namedType(name string) ql.Type {
+	if scalar, ok := builtInScalars[name]; ok {
+		return scalar
+	}
+	if typ, ok := b.types[name]; ok {
+		return typ
+	}
+	typ := b.buildType(name, b.definitions.types[name])
+	b.types[name] = typ
+	return typ
+}
+
+/**
+ * Builds the config of a type. The fields are built when the schema asks for
+ * them, so that the types can refer to each other.
+ */
+func (b *_SchemaBuilder) buildType(name string, definition lang.ITypeDefinition) ql.Type {
+	switch definition := definition.(type) {
+	case *lang.ObjectTypeDefinition:
+		return ql.Object{
+			Name:           name,
+			Description:    description(definition.Description),
+			InterfacesFunc: b.buildInterfaces(definition.Interfaces),
+			FieldsFunc: func() ql.FieldMap {
+				return b.buildFields(definition.Fields, b.config.Resolvers[name])
+			},
+		}
+
+	case *lang.InterfaceTypeDefinition:
+		return ql.Interface{
+			Name:           name,
+			Description:    description(definition.Description),
+			InterfacesFunc: b.buildInterfaces(definition.Interfaces),
+			FieldsFunc: func() ql.FieldMap {
+				return b.buildFields(definition.Fields, nil)
+			},
+			ResolveType: b.config.ResolveTypes[name],
+		}
+
+	case *lang.UnionTypeDefinition:
+		types := make(ql.Objects, len(definition.Types))
+		for i, member := range definition.Types {
+			types[i] = b.namedType(member.Name.Value).(ql.Object)
+		}
+		return ql.Union{
+			Name:        name,
+			Description: description(definition.Description),
+			Types:       types,
+			ResolveType: b.config.ResolveTypes[name],
+		}
+
+	case *lang.ScalarTypeDefinition:
+		scalar, ok := b.config.Scalars[name]
+		if !ok {
+			scalar = ql.Scalar{
+				Serialize:    func(v interface{}) interface{} { return v },
+				ParseValue:   func(v interface{}) interface{} { return v },
+				ParseLiteral: func(kind, value string) interface{} { return value },
+			}
+		}
+		scalar.Name = name
+		if scalar.Description == "" {
+			scalar.Description = description(definition.Description)
+		}
+		return scalar
+
+	case *lang.EnumTypeDefinition:
+		values := make(ql.EnumValueMap, len(definition.Values))
+		for _, value := range definition.Values {
+			values[value.Name.Value] = ql.EnumValue{
+				Value:             value.Name.Value,
+				Description:       description(value.Description),
+				DeprecationReason: deprecationReason(value.Directives),
+			}
+		}
+		return ql.Enum{
+			Name:        name,
+			Description: description(definition.Description),
+			Values:      values,
+		}
+
+	case *lang.InputObjectTypeDefinition:
+		return ql.InputObject{
+			Name:        name,
+			Description: description(definition.Description),
+			FieldsFunc: func() ql.InputObjectFieldMap {
+				fields := make(ql.InputObjectFieldMap, len(definition.Fields))
+				for _, field := range definition.Fields {
+					typ := b.typeRef(field.Type)
+					fields[field.Name.Value] = ql.InputObjectField{
+						Type:         typ.(ql.InputType),
+						DefaultValue: b.valueFromAST(field.DefaultValue, typ),
+						Description:  description(field.Description),
+					}
+				}
+				return fields
+			},
+		}
+	}
+	return nil
+}
+
+func (b *_SchemaBuilder) buildInterfaces(definitions []*lang.NamedType) func() ql.Interfaces {
+	return func() ql.Interfaces {
+		interfaces := make(ql.Interfaces, len(definitions))
+		for i, iface := range definitions {
+			interfaces[i] = b.namedType(iface.Name.Value).(ql.Interface)
+		}
+		return interfaces
+	}
+}
+
+func (b *_SchemaBuilder) buildFields(definitions []*lang.FieldDefinition, resolvers map[string]interface{}) ql.FieldMap {
+	fields := make(ql.FieldMap, len(definitions))
+	for _, definition := range definitions {
+		cost, _ := fieldCost(definition.Directives)
+		field := ql.Field{
+			Type:              b.typeRef(definition.Type).(ql.OutputType),
+			Args:              b.buildArgs(definition.Arguments),
+			Resolve:           resolvers[definition.Name.Value],
+			Description:       description(definition.Description),
+			DeprecationReason: deprecationReason(definition.Directives),
+			Cost:              cost,
+		}
+		for _, directive := range definition.Directives {
+			switch directive.Name.Value {
+			case "deprecated", "cost":
+			default:
+				field.Directives = append(field.Directives, b.appliedDirective(directive))
+			}
+		}
+		fields[definition.Name.Value] = field
+	}
+	return fields
+}
+
+func (b *_SchemaBuilder) buildArgs(definitions []*lang.InputValueDefinition) ql.ArgumentMap {
+	if len(definitions) == 0 {
+		return nil
+	}
+	args := make(ql.ArgumentMap, len(definitions))
+	for _, definition := range definitions {
+		typ := b.typeRef(definition.Type)
+		args[definition.Name.Value] = ql.Argument{
+			Type:         typ.(ql.InputType),
+			DefaultValue: b.valueFromAST(definition.DefaultValue, typ),
+			Description:  description(definition.Description),
+		}
+	}
+	return args
+}
+
+/**
+ * Builds the directives of the SDL, except the built-in ones, followed by the
+ * directives of the config.
+ */
+func (b *_SchemaBuilder) buildDirectives() []ql.Directive {
+	for _, directive := range b.config.Directives {
+		b.directives[directive.Name] = directive
+	}
+
+	var result []ql.Directive
+	for _, definition := range b.definitions.directives {
+		name := definition.Name.Value
+		if _, ok := b.directives[name]; ok {
+			continue
+		}
+		switch name {
+		case ql.IncludeDirective.Name, ql.SkipDirective.Name, "deprecated", "cost":
+			continue
+		}
+
+		directive := ql.Directive{
+			Name:        name,
+			Description: description(definition.Description),
+			Locations:   make([]ql.DirectiveLocation, len(definition.Locations)),
+			Args:        b.buildArgs(definition.Arguments),
+		}
+		for i, location := range definition.Locations {
+			directive.Locations[i] = ql.DirectiveLocation(location.Value)
+		}
+		b.directives[name] = directive
+		result = append(result, directive)
+	}
+	return append(result, b.config.Directives...)
+}
+
+/**
+ * Converts a directive applied in the SDL. Its arguments are converted to
+ * the types of the arguments of the directive when it is known, otherwise the
+ * schema reports the unknown directive.
+ */
+func (b *_SchemaBuilder) appliedDirective(node *lang.Directive) ql.AppliedDirective {
+	applied := ql.AppliedDirective{Name: node.Name.Value}
+	directive, ok := b.directives[node.Name.Value]
+	if !ok || len(node.Arguments) == 0 {
+		return applied
+	}
+	applied.Args = make(map[string]interface{}, len(node.Arguments))
+	for _, arg := range node.Arguments {
+		var typ ql.InputType
+		if argDef, ok := directive.Args[arg.Name.Value]; ok {
+			typ = argDef.Type
+		}
+		applied.Args[arg.Name.Value] = b.valueFromAST(arg.Value, typ)
+	}
+	return applied
+}
+
+/**
+ * Produces the Go value of a constant value literal of the given type, as
+ * ValueFromAST does with the types of a schema. Returns nil for the values
+ * which are not valid.
+ */
+func (b *_SchemaBuilder) valueFromAST(valueAST lang.IValue, typ interface{}) interface{} {
+	if isNullValue(valueAST) {
+		return nil
+	}
+	switch typ := typ.(type) {
+	case ql.NonNull:
+		return b.valueFromAST(valueAST, typ.OfType)
+
+	case ql.List:
+		if valueAST, ok := valueAST.(*lang.ListValue); ok {
+			result := make([]interface{}, len(valueAST.Values))
+			for i, itemAST := range valueAST.Values {
+				result[i] = b.valueFromAST(itemAST, typ.OfType)
+			}
+			return result
+		}
+		return []interface{}{b.valueFromAST(valueAST, typ.OfType)}
+
+	case ql.InputObject:
+		valueAST, ok := valueAST.(*lang.ObjectValue)
+		if !ok {
+			return nil
+		}
+		fields := typ.Fields
+		if typ.FieldsFunc != nil {
+			fields = typ.FieldsFunc()
+		}
+		result := make(map[string]interface{})
+		for _, fieldAST := range valueAST.Fields {
+			if field, ok := fields[fieldAST.Name.Value]; ok {
+				if value := b.valueFromAST(fieldAST.Value, field.Type); value != nil {
+					result[fieldAST.Name.Value] = value
+				}
+			}
+		}
+		for name, field := range fields {
+			if _, ok := result[name]; !ok && field.DefaultValue != nil {
+				result[name] = field.DefaultValue
+			}
+		}
+		return result
+
+	case ql.Enum:
+		if valueAST, ok := valueAST.(*lang.EnumValue); ok {
+			if value, ok := typ.Values[valueAST.Value]; ok {
+				return value.Value
+			}
+		}
+		return nil
+
+	case ql.Scalar:
+		if valueAST, ok := valueAST.(lang.IScalarValue); ok && typ.ParseLiteral != nil {
+			return typ.ParseLiteral(string(valueAST.Kind()), valueAST.GetValue())
+		}
+		return nil
+	}
+	return nil
+}
+
+func namedTypeOf(typ lang.IType) *lang.NamedType {
+	for {
+		switch node := typ.(type) {
+		case *lang.NonNullType:
+			typ = node.Type
+		case *lang.ListType:
+			typ = node.Type
+		case *lang.NamedType:
+			return node
+		default:
+			return nil
+		}
+	}
+}
+
+func description(node *lang.StringValue) string {
+	if node == nil {
+		return ""
+	}
+	return node.Value
+}
+
+/**
+ * Returns the cost given by @cost, as ql.Field.Cost, or 0 when the directive
+ * is not applied. Reports false when its value is not a non-negative Int.
+ */
+func fieldCost(directives []*lang.Directive) (int, bool) {
+	for _, directive := range directives {
+		if directive.Name.Value != "cost" {
+			continue
+		}
+		for _, arg := range directive.Arguments {
+			value, ok := arg.Value.(*lang.IntValue)
+			if arg.Name.Value != "value" || !ok {
+				continue
+			}
+			cost, err := strconv.Atoi(value.Value)
+			switch {
+			case err != nil || cost < 0:
+				return 0, false
+			case cost == 0:
+				return ql.FreeCost, true
+			}
+			return cost, true
+		}
+		return 0, false
+	}
+	return 0, true
+}
+
+/**
+ * Returns the reason given by @deprecated, or the default reason when it has
+ * none, or "" when the directive is not applied.
+ */
+func deprecationReason(directives []*lang.Directive) string {
+	for _, directive := range directives {
+		if directive.Name.Value != "deprecated" {
+			continue
+		}
+		for _, arg := range directive.Arguments {
+			if reason, ok := arg.Value.(*lang.StringValue); ok && arg.Name.Value == "reason" {
+				return reason.Value
+			}
+		}
+		return "No longer supported"
+	}
+	return ""
+}
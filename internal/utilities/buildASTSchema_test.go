@@ -0,0 +1,283 @@
+package utilities
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+func buildSchema(T *testing.T, sources ...string) (ASTSchema, []error) {
+	document := &lang.Document{}
+	for _, source := range sources {
+		sourceDocument, err := lang.Parse(lang.NewSource(source, ""))
+		if err != nil {
+			T.Fatal(err)
+		}
+		document.Definitions = append(document.Definitions, sourceDocument.Definitions...)
+	}
+	return BuildASTSchema(document, BuildASTSchemaConfig{})
+}
+
+func objectFields(object ql.Object) ql.FieldMap {
+	if object.FieldsFunc != nil {
+		return object.FieldsFunc()
+	}
+	return object.Fields
+}
+
+/**
+ * Expects the schema to fail to build with exactly the given messages, in
+ * any order.
+ */
+func expectBuildErrors(T *testing.T, sources []string, messages ...string) {
+	_, errs := buildSchema(T, sources...)
+	actual := make([]string, len(errs))
+	for i, err := range errs {
+		actual[i] = err.(lang.QLError).Message
+	}
+	remaining := append([]string(nil), actual...)
+	for _, message := range messages {
+		found := false
+		for i, msg := range remaining {
+			if msg == message {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			T.Errorf("Expect error:\n%v\nbut got:\n%v", message, strings.Join(actual, "\n"))
+		}
+	}
+	if len(remaining) > 0 {
+		T.Errorf("Unexpected errors:\n%v", strings.Join(remaining, "\n"))
+	}
+}
+
+func TestBuildASTSchema_Cost(T *testing.T) {
+	schema, errs := buildSchema(T, `
+		directive @cost(value: Int!) on FIELD_DEFINITION
+
+		type Query {
+			default: String
+			expensive: String @cost(value: 5)
+			free: String @cost(value: 0)
+			deprecated: String @deprecated @cost(value: 2)
+		}
+	`)
+	if len(errs) != 0 {
+		T.Fatal(errs)
+	}
+	fields := objectFields(schema.Query)
+	expected := map[string]int{
+		"default":    0,
+		"expensive":  5,
+		"free":       ql.FreeCost,
+		"deprecated": 2,
+	}
+	for name, cost := range expected {
+		if fields[name].Cost != cost {
+			T.Errorf("Expect cost of %v to be %v but got %v", name, cost, fields[name].Cost)
+		}
+		if len(fields[name].Directives) != 0 {
+			T.Errorf("Expect @cost not to be applied to %v as a directive", name)
+		}
+	}
+	if len(schema.Directives) != 0 {
+		T.Errorf("Expect @cost not to be added to the schema directives but got %v", schema.Directives)
+	}
+
+	expectBuildErrors(T, []string{`
+		type Query {
+			negative: String @cost(value: -1)
+			missing: String @cost
+			wrongType: String @cost(value: "5")
+		}
+	`},
+		`The cost of Query.negative must be given as @cost(value:) with a non-negative Int.`,
+		`The cost of Query.missing must be given as @cost(value:) with a non-negative Int.`,
+		`The cost of Query.wrongType must be given as @cost(value:) with a non-negative Int.`)
+}
+
+func TestBuildASTSchema_Extensions(T *testing.T) {
+	schema, errs := buildSchema(T, `
+		extend type Query implements Node {
+			id: ID
+			search: Result
+		}
+		type Query {
+			name: String
+		}
+	`, `
+		interface Node { id: ID }
+		extend interface Named { nickname: String }
+		interface Named { name: String }
+		extend type Query implements Named { nickname: String color(c: Color, i: Input): String }
+
+		type A { a: String }
+		type B { b: String }
+		union Result = A
+		extend union Result = B
+
+		enum Color { RED }
+		extend enum Color { GREEN BLUE }
+
+		input Input { a: Int }
+		extend input Input { b: Int = 2 }
+
+		scalar Date
+		extend scalar Date @deprecated
+
+		type Mutation { set: String }
+		extend schema { mutation: Mutation }
+	`)
+	if len(errs) != 0 {
+		T.Fatal(errs)
+	}
+
+	fields := objectFields(schema.Query)
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, " ") != "color id name nickname search" {
+		T.Errorf("Expect the fields of the extensions to be merged but got %v", names)
+	}
+	interfaces := schema.Query.InterfacesFunc()
+	if len(interfaces) != 2 || interfaces[0].Name != "Node" || interfaces[1].Name != "Named" {
+		T.Errorf("Expect the interfaces of the extensions to be merged but got %v", interfaces)
+	}
+	if named := interfaces[1].FieldsFunc(); len(named) != 2 {
+		T.Errorf("Expect the fields of the interface extension to be merged but got %v", named)
+	}
+
+	union := fields["search"].Type.(ql.Union)
+	if len(union.Types) != 2 || union.Types[0].Name != "A" || union.Types[1].Name != "B" {
+		T.Errorf("Expect the union members to be merged but got %v", union.Types)
+	}
+	color := fields["color"].Args["c"].Type.(ql.Enum)
+	if len(color.Values) != 3 || color.Values["GREEN"].Value != "GREEN" {
+		T.Errorf("Expect the enum values to be merged but got %v", color.Values)
+	}
+	input := fields["color"].Args["i"].Type.(ql.InputObject).FieldsFunc()
+	if len(input) != 2 || input["b"].DefaultValue != int64(2) {
+		T.Errorf("Expect the input fields to be merged but got %v", input)
+	}
+	if schema.Mutation == nil || schema.Mutation.Name != "Mutation" {
+		T.Errorf("Expect the mutation type to be added by the schema extension but got %v", schema.Mutation)
+	}
+}
+
+func TestMergeExtensions_DoesNotChangeTheDocument(T *testing.T) {
+	document, err := lang.Parse(lang.NewSource(`
+		type Query { a: String }
+		extend type Query { b: String }
+		scalar Date
+		extend scalar Date @deprecated
+	`, ""))
+	if err != nil {
+		T.Fatal(err)
+	}
+	printed := lang.Print(document)
+	definitions, errs := mergeExtensions(document)
+	if len(errs) != 0 {
+		T.Fatal(errs)
+	}
+	if lang.Print(document) != printed {
+		T.Errorf("Expect the document not to change but got:\n%v", lang.Print(document))
+	}
+	if query := definitions.types["Query"].(*lang.ObjectTypeDefinition); len(query.Fields) != 2 {
+		T.Errorf("Expect Query to have 2 fields but got %v", len(query.Fields))
+	}
+	if date := definitions.types["Date"].(*lang.ScalarTypeDefinition); len(date.Directives) != 1 {
+		T.Errorf("Expect the directives of the scalar extension to be merged but got %v", date.Directives)
+	}
+	if strings.Join(definitions.typeNames, " ") != "Query Date" {
+		T.Errorf("Expect the types in the order of the document but got %v", definitions.typeNames)
+	}
+}
+
+func TestBuildASTSchema_ExtensionErrors(T *testing.T) {
+	tests := []struct {
+		sources  []string
+		messages []string
+	}{
+		{
+			[]string{`type Query { a: String }`, `type Query { b: String }`},
+			[]string{`There can be only one type named "Query".`},
+		},
+		{
+			[]string{`type Query { a: String } extend type Nope { b: String } extend enum Other { A }`},
+			[]string{
+				`Cannot extend type "Nope" because it is not defined.`,
+				`Cannot extend type "Other" because it is not defined.`,
+			},
+		},
+		{
+			[]string{`type Query { a: String } scalar S extend enum Query { A } extend input S { a: Int }`},
+			[]string{
+				`Cannot extend non-enum type "Query".`,
+				`Cannot extend non-input object type "S".`,
+			},
+		},
+		{
+			[]string{`type Query { a: String a: Int }`, `extend type Query { a: String }`},
+			[]string{
+				`Field "Query.a" can only be defined once.`,
+				`Field "Query.a" already exists in the schema. It cannot also be defined in this type extension.`,
+			},
+		},
+		{
+			[]string{`type Query { a: String } input I { a: Int } extend input I { a: Int }`},
+			[]string{`Field "I.a" already exists in the schema. It cannot also be defined in this type extension.`},
+		},
+		{
+			[]string{`type Query { a: String } enum E { A A } extend enum E { B A }`},
+			[]string{
+				`Enum value "E.A" can only be defined once.`,
+				`Enum value "E.A" already exists in the schema. It cannot also be defined in this type extension.`,
+			},
+		},
+		{
+			[]string{`
+				interface Node { id: ID }
+				type Query implements Node { id: ID }
+				extend type Query implements Node
+				type A { a: String }
+				union U = A
+				extend union U = A
+			`},
+			[]string{
+				`Type "Query" can only implement "Node" once.`,
+				`Union type "U" can only include type "A" once.`,
+			},
+		},
+		{
+			[]string{`
+				type Query { a: String }
+				schema { query: Query query: Query }
+				extend schema { query: Query }
+				schema { query: Query }
+			`},
+			[]string{
+				`There can be only one query type in schema.`,
+				`Type for query already defined in the schema. It cannot be redefined.`,
+				`Must provide only one schema definition.`,
+			},
+		},
+		{
+			[]string{`type Query { a: String } directive @d on FIELD directive @d on FIELD { a }`},
+			[]string{
+				`There can be only one directive named "@d".`,
+				`The SDL must only contain type system definitions.`,
+			},
+		},
+	}
+	for _, test := range tests {
+		expectBuildErrors(T, test.sources, test.messages...)
+	}
+}
@@ -0,0 +1,409 @@
+package utilities
+
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+)
+
+/**
+ * The type system definitions of a document, with the extensions merged into
+ * the schema and the types they extend.
+ */
+type _Definitions struct {
+	schema *lang.SchemaDefinition
+
+	// types are copies of the type definitions of the document, so that
+	// merging extensions does not change the document.
+	types      map[string]lang.ITypeDefinition
+	typeNames  []string
+	directives []*lang.DirectiveDefinition
+}
+
+/**
+ * Collects the type system definitions of a document and merges the
+ * extensions into the definitions they extend, in the order of the document.
+ * The extensions may come before or after the definitions.
+ *
+ * Returns an error for each definition given twice, each extension of an
+ * undefined type or of a type of another kind, and each field, enum value,
+ * interface, union member or operation type already defined.
+ */
+func mergeExtensions(document *lang.Document) (*_Definitions, []error) {
+	m := &_Merger{definitions: &_Definitions{
+		types: make(map[string]lang.ITypeDefinition),
+	}}
+	var extensions []lang.IDefinition
+	for _, definition := range document.Definitions {
+		switch definition := definition.(type) {
+		case *lang.OperationDefinition, *lang.FragmentDefinition:
+			m.errorf([]lang.INode{definition},
+				"The SDL must only contain type system definitions.")
+
+		case *lang.SchemaDefinition:
+			if m.definitions.schema != nil {
+				m.errorf([]lang.INode{m.definitions.schema, definition},
+					"Must provide only one schema definition.")
+				continue
+			}
+			schema := *definition
+			schema.Directives = append([]*lang.Directive(nil), definition.Directives...)
+			schema.OperationTypes = nil
+			m.definitions.schema = &schema
+			m.addOperationTypes(definition.OperationTypes, false)
+
+		case *lang.DirectiveDefinition:
+			for _, other := range m.definitions.directives {
+				if other.Name.Value == definition.Name.Value {
+					m.errorf([]lang.INode{other.Name, definition.Name},
+						`There can be only one directive named "@%v".`, definition.Name.Value)
+				}
+			}
+			m.definitions.directives = append(m.definitions.directives, definition)
+
+		case lang.ITypeExtension, *lang.SchemaExtension:
+			extensions = append(extensions, definition)
+
+		case lang.ITypeDefinition:
+			m.addType(definition)
+		}
+	}
+	for _, extension := range extensions {
+		m.extend(extension)
+	}
+	return m.definitions, m.errors
+}
+
+type _Merger struct {
+	definitions *_Definitions
+	errors      []error
+}
+
+func (m *_Merger) errorf(nodes []lang.INode, format string, args ...interface{}) {
+	m.errors = append(m.errors, lang.NewQLError(fmt.Sprintf(format, args...), nodes))
+}
+
+func (m *_Merger) addType(definition lang.ITypeDefinition) {
+	name := typeDefinitionName(definition)
+	if other := m.definitions.types[name.Value]; other != nil {
+		m.errorf([]lang.INode{typeDefinitionName(other), name},
+			`There can be only one type named "%v".`, name.Value)
+		return
+	}
+
+	var result lang.ITypeDefinition
+	switch definition := definition.(type) {
+	case *lang.ObjectTypeDefinition:
+		object := *definition
+		object.Directives = append([]*lang.Directive(nil), definition.Directives...)
+		object.Interfaces = m.addInterfaces(name.Value, nil, definition.Interfaces)
+		object.Fields = m.addFields(name.Value, nil, definition.Fields, false)
+		result = &object
+
+	case *lang.InterfaceTypeDefinition:
+		iface := *definition
+		iface.Directives = append([]*lang.Directive(nil), definition.Directives...)
+		iface.Interfaces = m.addInterfaces(name.Value, nil, definition.Interfaces)
+		iface.Fields = m.addFields(name.Value, nil, definition.Fields, false)
+		result = &iface
+
+	case *lang.UnionTypeDefinition:
+		union := *definition
+		union.Directives = append([]*lang.Directive(nil), definition.Directives...)
+		union.Types = m.addUnionMembers(name.Value, nil, definition.Types)
+		result = &union
+
+	case *lang.ScalarTypeDefinition:
+		scalar := *definition
+		scalar.Directives = append([]*lang.Directive(nil), definition.Directives...)
+		result = &scalar
+
+	case *lang.EnumTypeDefinition:
+		enum := *definition
+		enum.Directives = append([]*lang.Directive(nil), definition.Directives...)
+		enum.Values = m.addEnumValues(name.Value, nil, definition.Values, false)
+		result = &enum
+
+	case *lang.InputObjectTypeDefinition:
+		input := *definition
+		input.Directives = append([]*lang.Directive(nil), definition.Directives...)
+		input.Fields = m.addInputFields(name.Value, nil, definition.Fields, false)
+		result = &input
+
+	default:
+		return
+	}
+	m.definitions.types[name.Value] = result
+	m.definitions.typeNames = append(m.definitions.typeNames, name.Value)
+}
+
+func (m *_Merger) extend(extension lang.IDefinition) {
+	if extension, ok := extension.(*lang.SchemaExtension); ok {
+		if m.definitions.schema == nil {
+			m.definitions.schema = &lang.SchemaDefinition{}
+		}
+		schema := m.definitions.schema
+		schema.Directives = append(schema.Directives, extension.Directives...)
+		m.addOperationTypes(extension.OperationTypes, true)
+		return
+	}
+
+	name := typeExtensionName(extension)
+	definition := m.definitions.types[name.Value]
+	if definition == nil {
+		m.errorf([]lang.INode{name},
+			`Cannot extend type "%v" because it is not defined.`, name.Value)
+		return
+	}
+
+	switch extension := extension.(type) {
+	case *lang.TypeExtensionDefinition:
+		object, ok := definition.(*lang.ObjectTypeDefinition)
+		if !ok {
+			break
+		}
+		object.Interfaces = m.addInterfaces(name.Value, object.Interfaces, extension.Definition.Interfaces)
+		object.Directives = append(object.Directives, extension.Definition.Directives...)
+		object.Fields = m.addFields(name.Value, object.Fields, extension.Definition.Fields, true)
+		return
+
+	case *lang.InterfaceTypeExtension:
+		iface, ok := definition.(*lang.InterfaceTypeDefinition)
+		if !ok {
+			break
+		}
+		iface.Interfaces = m.addInterfaces(name.Value, iface.Interfaces, extension.Interfaces)
+		iface.Directives = append(iface.Directives, extension.Directives...)
+		iface.Fields = m.addFields(name.Value, iface.Fields, extension.Fields, true)
+		return
+
+	case *lang.UnionTypeExtension:
+		union, ok := definition.(*lang.UnionTypeDefinition)
+		if !ok {
+			break
+		}
+		union.Directives = append(union.Directives, extension.Directives...)
+		union.Types = m.addUnionMembers(name.Value, union.Types, extension.Types)
+		return
+
+	case *lang.ScalarTypeExtension:
+		scalar, ok := definition.(*lang.ScalarTypeDefinition)
+		if !ok {
+			break
+		}
+		scalar.Directives = append(scalar.Directives, extension.Directives...)
+		return
+
+	case *lang.EnumTypeExtension:
+		enum, ok := definition.(*lang.EnumTypeDefinition)
+		if !ok {
+			break
+		}
+		enum.Directives = append(enum.Directives, extension.Directives...)
+		enum.Values = m.addEnumValues(name.Value, enum.Values, extension.Values, true)
+		return
+
+	case *lang.InputObjectTypeExtension:
+		input, ok := definition.(*lang.InputObjectTypeDefinition)
+		if !ok {
+			break
+		}
+		input.Directives = append(input.Directives, extension.Directives...)
+		input.Fields = m.addInputFields(name.Value, input.Fields, extension.Fields, true)
+		return
+	}
+	m.errorf([]lang.INode{typeDefinitionName(definition), name},
+		`Cannot extend non-%v type "%v".`, extensionKind(extension), name.Value)
+}
+
+func (m *_Merger) addOperationTypes(operationTypes []*lang.OperationTypeDefinition, isExtension bool) {
+	schema := m.definitions.schema
+	for _, operationType := range operationTypes {
+		var existing *lang.OperationTypeDefinition
+		for _, other := range schema.OperationTypes {
+			if other.Operation == operationType.Operation {
+				existing = other
+			}
+		}
+		switch {
+		case existing == nil:
+			schema.OperationTypes = append(schema.OperationTypes, operationType)
+		case isExtension:
+			m.errorf([]lang.INode{existing, operationType},
+				`Type for %v already defined in the schema. It cannot be redefined.`, operationType.Operation)
+		default:
+			m.errorf([]lang.INode{existing, operationType},
+				`There can be only one %v type in schema.`, operationType.Operation)
+		}
+	}
+}
+
+func (m *_Merger) addInterfaces(typeName string, interfaces []*lang.NamedType, added []*lang.NamedType) []*lang.NamedType {
+	for _, iface := range added {
+		if existing := findNamedType(interfaces, iface.Name.Value); existing != nil {
+			m.errorf([]lang.INode{existing, iface},
+				`Type "%v" can only implement "%v" once.`, typeName, iface.Name.Value)
+			continue
+		}
+		interfaces = append(interfaces, iface)
+	}
+	return interfaces
+}
+
+func (m *_Merger) addUnionMembers(unionName string, types []*lang.NamedType, added []*lang.NamedType) []*lang.NamedType {
+	for _, typ := range added {
+		if existing := findNamedType(types, typ.Name.Value); existing != nil {
+			m.errorf([]lang.INode{existing, typ},
+				`Union type "%v" can only include type "%v" once.`, unionName, typ.Name.Value)
+			continue
+		}
+		types = append(types, typ)
+	}
+	return types
+}
+
+func (m *_Merger) addFields(
+	typeName string,
+	fields []*lang.FieldDefinition,
+	added []*lang.FieldDefinition,
+	isExtension bool,
+) []*lang.FieldDefinition {
+	for _, field := range added {
+		var existing *lang.FieldDefinition
+		for _, other := range fields {
+			if other.Name.Value == field.Name.Value {
+				existing = other
+			}
+		}
+		if existing != nil {
+			m.fieldConflict(typeName, existing.Name, field.Name, isExtension)
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func (m *_Merger) addInputFields(
+	typeName string,
+	fields []*lang.InputValueDefinition,
+	added []*lang.InputValueDefinition,
+	isExtension bool,
+) []*lang.InputValueDefinition {
+	for _, field := range added {
+		var existing *lang.InputValueDefinition
+		for _, other := range fields {
+			if other.Name.Value == field.Name.Value {
+				existing = other
+			}
+		}
+		if existing != nil {
+			m.fieldConflict(typeName, existing.Name, field.Name, isExtension)
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func (m *_Merger) fieldConflict(typeName string, existing, field *lang.Name, isExtension bool) {
+	if isExtension {
+		m.errorf([]lang.INode{existing, field},
+			`Field "%v.%v" already exists in the schema. It cannot also be defined in this type extension.`,
+			typeName, field.Value)
+		return
+	}
+	m.errorf([]lang.INode{existing, field},
+		`Field "%v.%v" can only be defined once.`, typeName, field.Value)
+}
+
+func (m *_Merger) addEnumValues(
+	enumName string,
+	values []*lang.EnumValueDefinition,
+	added []*lang.EnumValueDefinition,
+	isExtension bool,
+) []*lang.EnumValueDefinition {
+	for _, value := range added {
+		var existing *lang.EnumValueDefinition
+		for _, other := range values {
+			if other.Name.Value == value.Name.Value {
+				existing = other
+			}
+		}
+		switch {
+		case existing == nil:
+			values = append(values, value)
+		case isExtension:
+			m.errorf([]lang.INode{existing.Name, value.Name},
+				`Enum value "%v.%v" already exists in the schema. It cannot also be defined in this type extension.`,
+				enumName, value.Name.Value)
+		default:
+			m.errorf([]lang.INode{existing.Name, value.Name},
+				`Enum value "%v.%v" can only be defined once.`, enumName, value.Name.Value)
+		}
+	}
+	return values
+}
+
+func findNamedType(types []*lang.NamedType, name string) *lang.NamedType {
+	for _, typ := range types {
+		if typ.Name.Value == name {
+			return typ
+		}
+	}
+	return nil
+}
+
+func typeDefinitionName(definition lang.ITypeDefinition) *lang.Name {
+	switch definition := definition.(type) {
+	case *lang.ObjectTypeDefinition:
+		return definition.Name
+	case *lang.InterfaceTypeDefinition:
+		return definition.Name
+	case *lang.UnionTypeDefinition:
+		return definition.Name
+	case *lang.ScalarTypeDefinition:
+		return definition.Name
+	case *lang.EnumTypeDefinition:
+		return definition.Name
+	case *lang.InputObjectTypeDefinition:
+		return definition.Name
+	}
+	return nil
+}
+
+func typeExtensionName(extension lang.IDefinition) *lang.Name {
+	switch extension := extension.(type) {
+	case *lang.TypeExtensionDefinition:
+		return extension.Definition.Name
+	case *lang.InterfaceTypeExtension:
+		return extension.Name
+	case *lang.UnionTypeExtension:
+		return extension.Name
+	case *lang.ScalarTypeExtension:
+		return extension.Name
+	case *lang.EnumTypeExtension:
+		return extension.Name
+	case *lang.InputObjectTypeExtension:
+		return extension.Name
+	}
+	return nil
+}
+
+func extensionKind(extension lang.IDefinition) string {
+	switch extension.(type) {
+	case *lang.TypeExtensionDefinition:
+		return "object"
+	case *lang.InterfaceTypeExtension:
+		return "interface"
+	case *lang.UnionTypeExtension:
+		return "union"
+	case *lang.ScalarTypeExtension:
+		return "scalar"
+	case *lang.EnumTypeExtension:
+		return "enum"
+	case *lang.InputObjectTypeExtension:
+		return "input object"
+	}
+	return ""
+}
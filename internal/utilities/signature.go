@@ -0,0 +1,182 @@
+package utilities
+
+import (
+	"sort"
+	"strings"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+)
+
+/**
+ * Returns the signature of an operation, which is the same for the requests
+ * only differing by their literal values, aliases, formatting and the order of
+ * their selections and arguments, so that it identifies the operation without
+ * including any user data.
+ *
+ * The operation and the fragments it uses, sorted by name, are printed on one
+ * line with the aliases removed, the selections, arguments and directives
+ * sorted, and the literals replaced by 0, "", [] or {}. The booleans, enum
+ * values and variables are kept.
+ */
+func OperationSignature(document *lang.Document, operation *lang.OperationDefinition) string {
+	fragments := make(map[string]*lang.FragmentDefinition)
+	for _, definition := range document.Definitions {
+		if fragment, ok := definition.(*lang.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	n := &_Normalizer{fragments: fragments, used: make(map[string]bool)}
+	definitions := []lang.IDefinition{n.operation(operation)}
+	var usedFragments []*lang.FragmentDefinition
+	for len(n.pending) > 0 {
+		fragment := fragments[n.pending[0]]
+		n.pending = n.pending[1:]
+		usedFragments = append(usedFragments, n.fragment(fragment))
+	}
+	sort.Slice(usedFragments, func(i, j int) bool {
+		return usedFragments[i].Name.Value < usedFragments[j].Name.Value
+	})
+	for _, fragment := range usedFragments {
+		definitions = append(definitions, fragment)
+	}
+	printed := lang.Print(&lang.Document{Definitions: definitions})
+	return strings.Join(strings.Fields(printed), " ")
+}
+
+type _Normalizer struct {
+	fragments map[string]*lang.FragmentDefinition
+	used      map[string]bool
+
+	// The fragments used but not normalized yet.
+	pending []string
+}
+
+func (n *_Normalizer) operation(node *lang.OperationDefinition) *lang.OperationDefinition {
+	variables := make([]*lang.VariableDefinition, len(node.VariableDefinitions))
+	for i, variable := range node.VariableDefinitions {
+		variables[i] = &lang.VariableDefinition{
+			Variable:     variable.Variable,
+			Type:         variable.Type,
+			DefaultValue: hideLiterals(variable.DefaultValue),
+		}
+	}
+	sort.SliceStable(variables, func(i, j int) bool {
+		return variables[i].Variable.Name.Value < variables[j].Variable.Name.Value
+	})
+	return &lang.OperationDefinition{
+		Operation:           node.Operation,
+		Name:                node.Name,
+		VariableDefinitions: variables,
+		Directives:          n.directives(node.Directives),
+		SelectionSet:        n.selectionSet(node.SelectionSet),
+	}
+}
+
+func (n *_Normalizer) fragment(node *lang.FragmentDefinition) *lang.FragmentDefinition {
+	return &lang.FragmentDefinition{
+		Name:          node.Name,
+		TypeCondition: node.TypeCondition,
+		Directives:    n.directives(node.Directives),
+		SelectionSet:  n.selectionSet(node.SelectionSet),
+	}
+}
+
+func (n *_Normalizer) selectionSet(node *lang.SelectionSet) *lang.SelectionSet {
+	if node == nil {
+		return nil
+	}
+	selections := make([]lang.ISelection, len(node.Selections))
+	keys := make([]string, len(node.Selections))
+	for i, selection := range node.Selections {
+		selections[i] = n.selection(selection)
+		keys[i] = lang.Print(selections[i])
+	}
+	sort.Sort(_SelectionsByKey{selections, keys})
+	return &lang.SelectionSet{Selections: selections}
+}
+
+func (n *_Normalizer) selection(node lang.ISelection) lang.ISelection {
+	switch node := node.(type) {
+	case *lang.Field:
+		return &lang.Field{
+			Name:         node.Name,
+			Arguments:    arguments(node.Arguments),
+			Directives:   n.directives(node.Directives),
+			SelectionSet: n.selectionSet(node.SelectionSet),
+		}
+
+	case *lang.FragmentSpread:
+		name := node.Name.Value
+		if _, ok := n.fragments[name]; ok && !n.used[name] {
+			n.used[name] = true
+			n.pending = append(n.pending, name)
+		}
+		return &lang.FragmentSpread{
+			Name:       node.Name,
+			Directives: n.directives(node.Directives),
+		}
+
+	case *lang.InlineFragment:
+		return &lang.InlineFragment{
+			TypeCondition: node.TypeCondition,
+			Directives:    n.directives(node.Directives),
+			SelectionSet:  n.selectionSet(node.SelectionSet),
+		}
+	}
+	return node
+}
+
+func (n *_Normalizer) directives(nodes []*lang.Directive) []*lang.Directive {
+	result := make([]*lang.Directive, len(nodes))
+	for i, directive := range nodes {
+		result[i] = &lang.Directive{
+			Name:      directive.Name,
+			Arguments: arguments(directive.Arguments),
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name.Value < result[j].Name.Value
+	})
+	return result
+}
+
+func arguments(nodes []*lang.Argument) []*lang.Argument {
+	result := make([]*lang.Argument, len(nodes))
+	for i, argument := range nodes {
+		result[i] = &lang.Argument{
+			Name:  argument.Name,
+			Value: hideLiterals(argument.Value),
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name.Value < result[j].Name.Value
+	})
+	return result
+}
+
+func hideLiterals(value lang.IValue) lang.IValue {
+	switch value.(type) {
+	case *lang.IntValue, *lang.FloatValue:
+		return &lang.IntValue{Value: "0"}
+	case *lang.StringValue:
+		return &lang.StringValue{Value: ""}
+	case *lang.ListValue:
+		return &lang.ListValue{}
+	case *lang.ObjectValue:
+		return &lang.ObjectValue{}
+	}
+	return value
+}
+
+type _SelectionsByKey struct {
+	selections []lang.ISelection
+	keys       []string
+}
+
+func (s _SelectionsByKey) Len() int           { return len(s.selections) }
+func (s _SelectionsByKey) Less(i, j int) bool { return s.keys[i] < s.keys[j] }
+func (s _SelectionsByKey) Swap(i, j int) {
+	s.selections[i], s.selections[j] = s.selections[j], s.selections[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
@@ -0,0 +1,67 @@
+package utilities
+
+import (
+	"testing"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+)
+
+func signature(T *testing.T, source string) string {
+	document, err := lang.Parse(lang.NewSource(source, ""))
+	if err != nil {
+		T.Fatal(err)
+	}
+	for _, definition := range document.Definitions {
+		if operation, ok := definition.(*lang.OperationDefinition); ok {
+			return OperationSignature(document, operation)
+		}
+	}
+	T.Fatalf("Expect an operation in %v", source)
+	return ""
+}
+
+func TestOperationSignature(T *testing.T) {
+	expected := `query Q($id: ID, $n: Int = 0) { ...F a b(id: $id, n: 0, s: "") { c d(flag: true, kind: RED) } } fragment F on T { e }`
+	variants := []string{
+		// Aliases, literal values and the order of selections, arguments and
+		// variables are removed
+		`query Q($id: ID, $n: Int = 10) { a b(id: $id, n: 5, s: "x") { c d(flag: true, kind: RED) } ...F }`,
+		`query Q($n: Int = 3, $id: ID) { ...F b(s: "secret", n: 1, id: $id) { d(kind: RED, flag: true) c } a }`,
+		`query Q($id: ID, $n: Int = 10) { first: a second: b(id: $id, n: 5, s: "x") { c alias: d(flag: true, kind: RED) } ...F }`,
+
+		// and so is the formatting
+		"query Q(\n  $id: ID\n  $n: Int = 10\n) {\n  a\n  b(id: $id, n: 5, s: \"x\") {\n    c\n    d(flag: true, kind: RED)\n  }\n  ...F\n}",
+	}
+	for _, source := range variants {
+		source += ` fragment F on T { e } fragment Unused on T { f }`
+		if actual := signature(T, source); actual != expected {
+			T.Errorf("Expect signature of %v:\n%v\nbut got:\n%v", source, expected, actual)
+		}
+	}
+}
+
+func TestOperationSignature_Fragments(T *testing.T) {
+	// Only the fragments used by the operation are kept, sorted by name
+	a := signature(T, `{ ...B } fragment B on T { x ...A } fragment A on T { y } fragment C on T { z }`)
+	b := signature(T, `fragment C on T { z } fragment A on T { y } { ...B } fragment B on T { ...A x }`)
+	expected := `{ ...B } fragment A on T { y } fragment B on T { ...A x }`
+	if a != expected || b != expected {
+		T.Errorf("Expect signature:\n%v\nbut got:\n%v\nand:\n%v", expected, a, b)
+	}
+}
+
+func TestOperationSignature_KeepsDifferences(T *testing.T) {
+	tests := [][2]string{
+		{`{ a(flag: true) }`, `{ a(flag: false) }`},
+		{`{ a(kind: RED) }`, `{ a(kind: BLUE) }`},
+		{`query Q($a: Int) { a(n: $a) }`, `query Q($b: Int) { a(n: $b) }`},
+		{`query A { a }`, `query B { a }`},
+		{`{ a }`, `{ a @skip(if: true) }`},
+		{`{ a { b } }`, `{ a { c } }`},
+	}
+	for _, test := range tests {
+		if a, b := signature(T, test[0]), signature(T, test[1]); a == b {
+			T.Errorf("Expect %v and %v to have different signatures but got %v", test[0], test[1], a)
+		}
+	}
+}
@@ -15,8 +15,13 @@ type TypeInfo struct {
 	argument        *typs.QLArgument
 }
 
-func NewTypeInfo(schema typs.QLSchema) TypeInfo {
-	return TypeInfo{
+/**
+ * TypeInfo is a utility class which, given a QL schema, can keep track
+ * of the current field and type definitions at any point in a QL document
+ * AST during a recursive descent by calling Enter(node) and Leave(node).
+ */
+func NewTypeInfo(schema typs.QLSchema) *TypeInfo {
+	return &TypeInfo{
 		schema:          schema,
 		typeStack:       make([]typs.QLOutputType, 0),
 		parentTypeStack: make([]typs.QLCompositeType, 0),
@@ -27,7 +32,7 @@ func NewTypeInfo(schema typs.QLSchema) TypeInfo {
 	}
 }
 
-func (t TypeInfo) GetType() typs.QLOutputType {
+func (t *TypeInfo) GetType() typs.QLOutputType {
 	s := t.typeStack
 	l := len(s)
 	if l > 0 {
@@ -36,7 +41,7 @@ func (t TypeInfo) GetType() typs.QLOutputType {
 	return nil
 }
 
-func (t TypeInfo) GetParentType() typs.QLCompositeType {
+func (t *TypeInfo) GetParentType() typs.QLCompositeType {
 	s := t.parentTypeStack
 	l := len(s)
 	if l > 0 {
@@ -45,7 +50,7 @@ func (t TypeInfo) GetParentType() typs.QLCompositeType {
 	return nil
 }
 
-func (t TypeInfo) GetInputType() typs.QLInputType {
+func (t *TypeInfo) GetInputType() typs.QLInputType {
 	s := t.inputTypeStack
 	l := len(s)
 	if l > 0 {
@@ -54,7 +59,7 @@ func (t TypeInfo) GetInputType() typs.QLInputType {
 	return nil
 }
 
-func (t TypeInfo) GetFieldDef() *typs.QLFieldDefinition {
+func (t *TypeInfo) GetFieldDef() *typs.QLFieldDefinition {
 	s := t.fieldDefStack
 	l := len(s)
 	if l > 0 {
@@ -63,18 +68,176 @@ func (t TypeInfo) GetFieldDef() *typs.QLFieldDefinition {
 	return nil
 }
 
-func (t TypeInfo) GetDirective() *typs.QLDirective {
+func (t *TypeInfo) GetDirective() *typs.QLDirective {
 	return t.directive
 }
 
-func (t TypeInfo) GetArgument() *typs.QLArgument {
+func (t *TypeInfo) GetArgument() *typs.QLArgument {
 	return t.argument
 }
 
-func (t TypeInfo) Enter(node lang.INode) {
+func (t *TypeInfo) Enter(node lang.INode) {
+	switch node := node.(type) {
+	case *lang.SelectionSet:
+		var compositeType typs.QLCompositeType
+		if typ, ok := getNamedType(t.GetType()).(typs.QLCompositeType); ok {
+			compositeType = typ
+		}
+		t.parentTypeStack = append(t.parentTypeStack, compositeType)
 
+	case *lang.Field:
+		var fieldDef *typs.QLFieldDefinition
+		var fieldType typs.QLOutputType
+		if parentType := t.GetParentType(); parentType != nil {
+			fieldDef = getFieldDef(t.schema, parentType, node)
+		}
+		if fieldDef != nil {
+			fieldType = fieldDef.Type
+		}
+		t.fieldDefStack = append(t.fieldDefStack, fieldDef)
+		t.typeStack = append(t.typeStack, fieldType)
+
+	case *lang.Directive:
+		t.directive = t.schema.GetDirective(node.Name.Value)
+
+	case *lang.OperationDefinition:
+		var typ typs.QLOutputType
+		switch node.Operation {
+		case lang.OperationQuery:
+			typ = t.schema.GetQueryType()
+		case lang.OperationMutation:
+			if mutationType := t.schema.GetMutationType(); mutationType != nil {
+				typ = mutationType
+			}
+		}
+		t.typeStack = append(t.typeStack, typ)
+
+	case *lang.InlineFragment:
+		typ := t.GetType()
+		if node.TypeCondition != nil {
+			typ = t.outputTypeFromAST(node.TypeCondition)
+		}
+		t.typeStack = append(t.typeStack, typ)
+
+	case *lang.FragmentDefinition:
+		t.typeStack = append(t.typeStack, t.outputTypeFromAST(node.TypeCondition))
+
+	case *lang.VariableDefinition:
+		var inputType typs.QLInputType
+		if typ, ok := TypeFromAST(t.schema, node.Type).(typs.QLInputType); ok {
+			inputType = typ
+		}
+		t.inputTypeStack = append(t.inputTypeStack, inputType)
+
+	case *lang.Argument:
+		var args []*typs.QLArgument
+		if t.directive != nil {
+			args = t.directive.Args
+		} else if fieldDef := t.GetFieldDef(); fieldDef != nil {
+			args = fieldDef.Args
+		}
+		var argType typs.QLInputType
+		t.argument = nil
+		for _, arg := range args {
+			if arg.Name == node.Name.Value {
+				t.argument = arg
+				argType = arg.Type
+				break
+			}
+		}
+		t.inputTypeStack = append(t.inputTypeStack, argType)
+
+	case *lang.ListValue:
+		var itemType typs.QLInputType
+		if listType, ok := getNullableType(t.GetInputType()).(*typs.QLList); ok {
+			itemType, _ = listType.OfType.(typs.QLInputType)
+		}
+		t.inputTypeStack = append(t.inputTypeStack, itemType)
+
+	case *lang.ObjectField:
+		var fieldType typs.QLInputType
+		if objectType, ok := getNamedType(t.GetInputType()).(*typs.QLInputObject); ok {
+			if field := objectType.GetFields()[node.Name.Value]; field != nil {
+				fieldType = field.Type
+			}
+		}
+		t.inputTypeStack = append(t.inputTypeStack, fieldType)
+	}
+}
+
+func (t *TypeInfo) Leave(node lang.INode) {
+	switch node.(type) {
+	case *lang.SelectionSet:
+		t.parentTypeStack = t.parentTypeStack[:len(t.parentTypeStack)-1]
+
+	case *lang.Field:
+		t.fieldDefStack = t.fieldDefStack[:len(t.fieldDefStack)-1]
+		t.typeStack = t.typeStack[:len(t.typeStack)-1]
+
+	case *lang.Directive:
+		t.directive = nil
+
+	case *lang.OperationDefinition, *lang.InlineFragment, *lang.FragmentDefinition:
+		t.typeStack = t.typeStack[:len(t.typeStack)-1]
+
+	case *lang.VariableDefinition:
+		t.inputTypeStack = t.inputTypeStack[:len(t.inputTypeStack)-1]
+
+	case *lang.Argument:
+		t.argument = nil
+		t.inputTypeStack = t.inputTypeStack[:len(t.inputTypeStack)-1]
+
+	case *lang.ListValue, *lang.ObjectField:
+		t.inputTypeStack = t.inputTypeStack[:len(t.inputTypeStack)-1]
+	}
 }
 
-func (t TypeInfo) Leave(node lang.INode) {
+func (t *TypeInfo) outputTypeFromAST(typeAST *lang.NamedType) typs.QLOutputType {
+	if typ, ok := t.schema.GetType(typeAST.Name.Value).(typs.QLOutputType); ok {
+		return typ
+	}
+	return nil
+}
 
+func getNamedType(typ typs.QLType) typs.QLType {
+	for {
+		switch t := typ.(type) {
+		case *typs.QLList:
+			typ = t.OfType
+		case *typs.QLNonNull:
+			typ = t.OfType
+		default:
+			return typ
+		}
+	}
+}
+
+func getNullableType(typ typs.QLType) typs.QLType {
+	if typ, ok := typ.(*typs.QLNonNull); ok {
+		return typ.OfType
+	}
+	return typ
+}
+
+/**
+ * Not exactly the same as the executor's definition of getFieldDef, in this
+ * statically evaluated environment we do not always have an Object type,
+ * and need to handle Interface and Union types.
+ */
+func getFieldDef(
+	schema typs.QLSchema,
+	parentType typs.QLCompositeType,
+	fieldAST *lang.Field,
+) *typs.QLFieldDefinition {
+	name := fieldAST.Name.Value
+	if fieldDef := schema.GetMetaFieldDef(parentType, name); fieldDef != nil {
+		return fieldDef
+	}
+	switch parentType := parentType.(type) {
+	case *typs.QLObject:
+		return parentType.GetFields()[name]
+	case *typs.QLInterface:
+		return parentType.GetFields()[name]
+	}
+	return nil
 }
@@ -0,0 +1,143 @@
+package utilities
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	typs "github.com/ng-vu/graphql-go/internal/types"
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+var typeInfoColorConfig = ql.Enum{
+	Name:   "Color",
+	Values: ql.EnumValueMap{"RED": {Value: "RED"}, "BLUE": {Value: "BLUE"}},
+}
+
+var typeInfoFilterConfig = ql.InputObject{
+	Name: "Filter",
+	Fields: ql.InputObjectFieldMap{
+		"colors": {Type: ql.List{OfType: typeInfoColorConfig}},
+		"age":    {Type: ql.NonNull{OfType: ql.Int}},
+	},
+}
+
+var typeInfoSchema = func() typs.QLSchema {
+	humanConfig := ql.Object{
+		Name:   "Human",
+		Fields: ql.FieldMap{"name": {Type: ql.String}},
+	}
+	dogConfig := ql.Object{
+		Name: "Dog",
+		Fields: ql.FieldMap{
+			"name":  {Type: ql.String},
+			"owner": {Type: humanConfig},
+		},
+	}
+	return typs.NewQLSchema(ql.Object{
+		Name: "Query",
+		Fields: ql.FieldMap{
+			"dog": {
+				Type: dogConfig,
+				Args: ql.ArgumentMap{
+					"name":   {Type: ql.String},
+					"filter": {Type: typeInfoFilterConfig},
+				},
+			},
+			"dogs": {Type: ql.List{OfType: dogConfig}},
+		},
+	}, nil)
+}()
+
+func TestTypeInfo(T *testing.T) {
+	document, err := lang.Parse(lang.NewSource(`
+		query Q($name: String) {
+			dog(name: $name, filter: { colors: [RED], age: 2 }) { name owner { name } }
+			dogs { ...Names ... on Dog { name } }
+		}
+		fragment Names on Dog { name }
+	`, ""))
+	if err != nil {
+		T.Fatal(err)
+	}
+
+	// Records the types known when entering the fields and the values
+	var actual []string
+	typeInfo := NewTypeInfo(typeInfoSchema)
+	lang.Visit(document, lang.VisitorFunc{
+		EnterFunc: func(node lang.INode, info lang.VisitInfo) lang.VisitAction {
+			typeInfo.Enter(node)
+			switch node := node.(type) {
+			case *lang.Field:
+				actual = append(actual, fmt.Sprintf("%v.%v: %v",
+					typeInfo.GetParentType(), node.Name.Value, typeInfo.GetType()))
+			case *lang.Variable, *lang.EnumValue, *lang.IntValue:
+				actual = append(actual, fmt.Sprintf("%v: %v", lang.Print(node), typeInfo.GetInputType()))
+			}
+			return nil
+		},
+		LeaveFunc: func(node lang.INode, info lang.VisitInfo) lang.VisitAction {
+			typeInfo.Leave(node)
+			return nil
+		},
+	}, nil)
+
+	expected := []string{
+		"$name: String",
+		"Query.dog: Dog",
+		"$name: String",
+		"RED: Color",
+		"2: Int!",
+		"Dog.name: String",
+		"Dog.owner: Human",
+		"Human.name: String",
+		"Query.dogs: [Dog]",
+		"Dog.name: String",
+		"Dog.name: String",
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		T.Errorf("Expect:\n%v\nbut got:\n%v", strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	}
+	if typeInfo.GetType() != nil || typeInfo.GetParentType() != nil || typeInfo.GetInputType() != nil {
+		T.Errorf("Expect the types to be left")
+	}
+}
+
+func TestIsValidLiteralValue(T *testing.T) {
+	filterType := typeInfoSchema.GetType("Filter").(typs.QLInputType)
+	intListType := typs.NewQLList(typeInfoSchema.GetType("Int"))
+	tests := []struct {
+		typ      typs.QLInputType
+		value    string
+		expected bool
+	}{
+		{intListType, `[1, 2]`, true},
+		{intListType, `[1, "2"]`, false},
+
+		// A single item is coerced to a list
+		{intListType, `1`, true},
+		{intListType, `"1"`, false},
+
+		{filterType, `{ age: 1, colors: [RED, BLUE] }`, true},
+		{filterType, `{ age: 1, colors: RED }`, true},
+		{filterType, `{ age: 1, colors: [GREEN] }`, false},
+		{filterType, `{ age: 1, other: 1 }`, false},
+
+		// Every required field must be given
+		{filterType, `{ colors: [RED] }`, false},
+		{filterType, `1`, false},
+
+		// Variables are assumed to have the right type
+		{filterType, `{ age: $age }`, true},
+	}
+	for _, test := range tests {
+		value, err := lang.ParseValue(lang.NewSource(test.value, ""))
+		if err != nil {
+			T.Fatal(err)
+		}
+		if actual := IsValidLiteralValue(test.typ, value); actual != test.expected {
+			T.Errorf("Expect %v to be valid for %v: %v but got %v", test.value, test.typ, test.expected, actual)
+		}
+	}
+}
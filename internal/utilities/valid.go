@@ -7,9 +7,16 @@ import (
 	typs "github.com/ng-vu/graphql-go/internal/types"
 )
 
+/**
+ * Utility for validators which determines if a value literal AST is valid
+ * given an input type.
+ *
+ * Note that this only validates literal values, variables are assumed to
+ * provide values of the correct type.
+ */
 func IsValidLiteralValue(typ typs.QLInputType, valueAST lang.IValue) bool {
 	if typ, ok := typ.(*typs.QLNonNull); ok {
-		if IsNil(valueAST) {
+		if isNullValue(valueAST) {
 			return false
 		}
 
@@ -17,8 +24,8 @@ func IsValidLiteralValue(typ typs.QLInputType, valueAST lang.IValue) bool {
 		return IsValidLiteralValue(ofType, valueAST)
 	}
 
-	if IsNil(valueAST) {
-		return false
+	if isNullValue(valueAST) {
+		return true
 	}
 
 	if _, ok := valueAST.(*lang.Variable); ok {
@@ -28,38 +35,41 @@ func IsValidLiteralValue(typ typs.QLInputType, valueAST lang.IValue) bool {
 	if typ, ok := typ.(*typs.QLList); ok {
 		itemType := typ.OfType.(typs.QLInputType)
 		if valueAST, ok := valueAST.(*lang.ListValue); ok {
-			for i := range valueAST.Values {
-				return IsValidLiteralValue(itemType, valueAST.Values[i])
+			for _, itemAST := range valueAST.Values {
+				if !IsValidLiteralValue(itemType, itemAST) {
+					return false
+				}
 			}
+			return true
 		}
+		return IsValidLiteralValue(itemType, valueAST)
 	}
 
 	if typ, ok := typ.(*typs.QLInputObject); ok {
-		if _, ok := valueAST.(*lang.ObjectValue); !ok {
+		valueAST, ok := valueAST.(*lang.ObjectValue)
+		if !ok {
 			return false
 		}
 
 		fields := typ.GetFields()
-		fieldASTs := valueAST.(*lang.ObjectValue).Fields
-		refinedFieldASTs := []interface{}{}
-
-		for i := range fieldASTs {
-			if fields[fieldASTs[i].Name.Value] == nil {
+		fieldASTMap := make(map[string]*lang.ObjectField)
+		for _, fieldAST := range valueAST.Fields {
+			if fields[fieldAST.Name.Value] == nil {
 				return false
 			}
-
-			refinedFieldASTs = append(refinedFieldASTs, fieldASTs[i])
-		}
-
-		fieldASTMap := make(map[string]interface{})
-		for _, fieldAST := range refinedFieldASTs {
-			fieldASTMap[fieldAST.(lang.ObjectField).Name.Value] = fieldAST
+			fieldASTMap[fieldAST.Name.Value] = fieldAST
 		}
 
-		for k, v := range fields {
-			return IsValidLiteralValue(v.Type, fieldASTMap[k].(*lang.ObjectField).Value)
+		for name, field := range fields {
+			var fieldValue lang.IValue
+			if fieldAST := fieldASTMap[name]; fieldAST != nil {
+				fieldValue = fieldAST.Value
+			}
+			if !IsValidLiteralValue(field.Type, fieldValue) {
+				return false
+			}
 		}
-
+		return true
 	}
 
 	if typ, ok := typ.(*typs.QLScalar); ok {
@@ -73,6 +83,17 @@ func IsValidLiteralValue(typ typs.QLInputType, valueAST lang.IValue) bool {
 	return false
 }
 
+/**
+ * Returns true if the value literal is missing or is the null literal.
+ */
+func isNullValue(valueAST lang.IValue) bool {
+	if IsNil(valueAST) {
+		return true
+	}
+	_, ok := valueAST.(*lang.NullValue)
+	return ok
+}
+
 func IsValidGoValue(value interface{}, typ typs.QLInputType) bool {
 	v := reflect.ValueOf(value)
 	if typ, ok := typ.(*typs.QLNonNull); ok {
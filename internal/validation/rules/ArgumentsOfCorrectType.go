@@ -5,24 +5,30 @@ import (
 
 	lang "github.com/ng-vu/graphql-go/internal/language"
 	util "github.com/ng-vu/graphql-go/internal/utilities"
-	"github.com/ng-vu/graphql-go/validation"
+	"github.com/ng-vu/graphql-go/internal/validation"
 )
 
 func badValueMessage(argName, typ, value interface{}) string {
 	return fmt.Sprintf(
-		`Argument "%v" expected type "%v" but got: ${%v}.`,
+		`Argument "%v" expected type "%v" but got: %v.`,
 		argName, typ, value)
 }
 
-func ArgumentsOfCorrectType(context *validation.Context) RuleVisitor {
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			if argAST, ok := node.(lang.Argument); ok {
+/**
+ * Argument values of correct type
+ *
+ * A GraphQL document is only valid if all field argument literal values are
+ * of the type expected by their position.
+ */
+func ArgumentsOfCorrectType(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			if argAST, ok := node.(*lang.Argument); ok {
 				argDef := context.GetArgument()
 				if argDef != nil && !util.IsValidLiteralValue(argDef.Type, argAST.Value) {
-					return lang.NewGraphQLError(
+					return newError(
 						badValueMessage(argAST.Name.Value, argDef.Type, lang.Print(argAST.Value)),
-						[]lang.INode{argAST.Value})
+						argAST.Value)
 				}
 			}
 			return nil
@@ -0,0 +1,36 @@
+package rules
+
+import "testing"
+
+func TestArgumentsOfCorrectType(T *testing.T) {
+	expectPassesRule(T, ArgumentsOfCorrectType, `
+		query Foo($intArg: Int) {
+			complicatedArgs {
+				intArgField(intArg: 2)
+				stringArgField(stringArg: "foo")
+				booleanArgField(booleanArg: true)
+				enumArgField(enumArg: SIT)
+				idArgField(idArg: 1)
+				stringListArgField(stringListArg: ["one", "two"])
+				nonNullIntArgField(nonNullIntArg: $intArg)
+				complexArgField(complexArg: { requiredField: true, intField: 4 })
+			}
+		}
+	`)
+	expectFailsRule(T, ArgumentsOfCorrectType, `
+		{
+			complicatedArgs {
+				intArgField(intArg: "3")
+				stringArgField(stringArg: 1)
+				enumArgField(enumArg: "SIT")
+				stringListArgField(stringListArg: [1])
+				complexArgField(complexArg: { intField: 4 })
+			}
+		}
+	`,
+		`Argument "intArg" expected type "Int" but got: "3".`,
+		`Argument "stringArg" expected type "String" but got: 1.`,
+		`Argument "enumArg" expected type "DogCommand" but got: "SIT".`,
+		`Argument "stringListArg" expected type "[String]" but got: [1].`,
+		`Argument "complexArg" expected type "ComplexInput" but got: {intField: 4}.`)
+}
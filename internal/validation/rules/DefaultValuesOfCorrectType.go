@@ -4,9 +4,9 @@ import (
 	"fmt"
 
 	lang "github.com/ng-vu/graphql-go/internal/language"
+	typs "github.com/ng-vu/graphql-go/internal/types"
 	util "github.com/ng-vu/graphql-go/internal/utilities"
-	typs "github.com/ng-vu/graphql-go/types"
-	"github.com/ng-vu/graphql-go/validation"
+	"github.com/ng-vu/graphql-go/internal/validation"
 )
 
 func defaultForNonNullArgMessage(varName, typ, guessType interface{}) string {
@@ -27,22 +27,25 @@ func badValueForDefaultArgMessage(varName, typ, value interface{}) string {
  * A GraphQL document is only valid if all variable default values are of the
  * type expected by their definition.
  */
-func DefaultValuesOfCorrectType(context *validation.Context) RuleVisitor {
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			if varDefAST, ok := node.(lang.VariableDefinition); ok {
+func DefaultValuesOfCorrectType(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			if varDefAST, ok := node.(*lang.VariableDefinition); ok {
 				name := varDefAST.Variable.Name.Value
 				defaultValue := varDefAST.DefaultValue
+				if util.IsNil(defaultValue) {
+					return nil
+				}
 				typ := context.GetInputType()
-				if typ, ok := typ.(typs.GraphQLNonNull); ok {
-					return lang.NewGraphQLError(
-						defaultForNonNullArgMessage(name, typ, typ.OfType),
-						[]lang.INode{defaultValue})
+				if typ, ok := typ.(*typs.QLNonNull); ok {
+					return newError(
+						defaultForNonNullArgMessage(name, lang.Print(varDefAST.Type), typ.OfType.GetName()),
+						defaultValue)
 				}
 				if typ != nil && !util.IsValidLiteralValue(typ, defaultValue) {
-					return lang.NewGraphQLError(
-						badValueForDefaultArgMessage(name, typ, lang.Print(defaultValue)),
-						[]lang.INode{defaultValue})
+					return newError(
+						badValueForDefaultArgMessage(name, lang.Print(varDefAST.Type), lang.Print(defaultValue)),
+						defaultValue)
 				}
 			}
 			return nil
@@ -0,0 +1,24 @@
+package rules
+
+import "testing"
+
+func TestDefaultValuesOfCorrectType(T *testing.T) {
+	expectPassesRule(T, DefaultValuesOfCorrectType, `
+		query Foo(
+			$a: Int = 1,
+			$b: String = "ok",
+			$c: ComplexInput = { requiredField: true, intField: 3 }
+			$d: Int!
+		) { dog { name } }
+	`)
+	expectFailsRule(T, DefaultValuesOfCorrectType, `
+		query Foo($a: Int! = 3, $b: String! = "default") { dog { name } }
+	`,
+		`Variable "$a" of type "Int!" is required and will not use the default value. Perhaps you meant to use type "Int".`,
+		`Variable "$b" of type "String!" is required and will not use the default value. Perhaps you meant to use type "String".`)
+	expectFailsRule(T, DefaultValuesOfCorrectType, `
+		query Foo($a: Int = "one", $b: ComplexInput = { intField: 3 }) { dog { name } }
+	`,
+		`Variable "$a" of type "Int" has invalid default value: "one".`,
+		`Variable "$b" of type "ComplexInput" has invalid default value: {intField: 3}.`)
+}
@@ -1,23 +1,33 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
 
 func undefinedFieldMessage(fieldName, typ interface{}) string {
 	return fmt.Sprintf(`Cannot query field "%v" on "%v".`, fieldName, typ)
 }
 
-func FieldsOnCorrectType(context *validation.Context) RuleVisitor {
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			if node, ok := node.(lang.Field); ok {
+/**
+ * Fields on correct type
+ *
+ * A GraphQL document is only valid if all fields selected are defined by the
+ * parent type, or are an allowed meta field such as __typename
+ */
+func FieldsOnCorrectType(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			if node, ok := node.(*lang.Field); ok {
 				typ := context.GetParentType()
-				if typ {
+				if typ != nil {
 					fieldDef := context.GetFieldDef()
-					if !fieldDef {
-						return lang.NewGraphQLError(
-							undefinedFieldMessage(node.Value, typ.name),
-							[]lang.INode{node},
-						)
+					if fieldDef == nil {
+						return newError(
+							undefinedFieldMessage(node.Name.Value, typ.GetName()),
+							node)
 					}
 				}
 			}
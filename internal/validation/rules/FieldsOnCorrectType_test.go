@@ -0,0 +1,28 @@
+package rules
+
+import "testing"
+
+func TestFieldsOnCorrectType(T *testing.T) {
+	expectPassesRule(T, FieldsOnCorrectType, `
+		fragment objectFields on Dog { __typename name barks }
+		fragment interfaceFields on Pet { __typename name }
+		fragment aliased on Dog { tName: name otherName: name }
+		fragment unionTypename on CatOrDog { __typename }
+		{ __schema { queryType { name } } dog { name } }
+	`)
+	expectFailsRule(T, FieldsOnCorrectType, `
+		fragment objectFields on Dog { meowVolume }
+		fragment aliased on Dog { volume: mooVolume }
+		fragment interfaceFields on Pet { nickname }
+		fragment unionFields on CatOrDog { name }
+		fragment nested on Dog { owner { unknown } }
+	`,
+		`Cannot query field "meowVolume" on "Dog".`,
+		`Cannot query field "mooVolume" on "Dog".`,
+		`Cannot query field "nickname" on "Pet".`,
+		`Cannot query field "name" on "CatOrDog".`,
+		`Cannot query field "unknown" on "Human".`)
+	expectFailsRule(T, FieldsOnCorrectType, `
+		{ dog { __schema { queryType { name } } } }
+	`, `Cannot query field "__schema" on "Dog".`)
+}
@@ -1,38 +1,53 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	typs "github.com/ng-vu/graphql-go/internal/types"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
 
 func inlineFragmentOnNonCompositeErrorMessage(typ interface{}) string {
 	return fmt.Sprintf(`Fragment cannot condition on non composite type "%v".`, typ)
 }
 
 func fragmentOnNonCompositeErrorMessage(fragName, typ interface{}) string {
-	return fmt.Sprintf(`Fragment "${fragName}" cannot condition on non composite type "%v".`, typ)
+	return fmt.Sprintf(`Fragment "%v" cannot condition on non composite type "%v".`, fragName, typ)
 }
 
-func FragmentsOnCompositeTypes(context *validation.Context) RuleVisitor {
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			switch node.(type) {
-			case lang.InlineFragment:
+/**
+ * Fragments on composite type
+ *
+ * Fragments use a type condition to determine if they apply, since fragments
+ * can only be spread into a composite type (object, interface, or union), the
+ * type condition must also be a composite type.
+ */
+func FragmentsOnCompositeTypes(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			switch node := node.(type) {
+			case *lang.InlineFragment:
 				typ := context.GetType()
-				if typ && !util.IsCompositeType(typ) {
-					return lang.NewGraphQLError(
-						inlineFragmentOnNonCompositeErrorMessage(printer.Print(node.TypeCondition)),
-						[]lang.INode{node.TypeCondition},
-					)
+				if typ != nil && !isCompositeType(typ) {
+					return newError(
+						inlineFragmentOnNonCompositeErrorMessage(lang.Print(node.TypeCondition)),
+						node.TypeCondition)
 				}
-			case lang.FragmentDefinition:
+			case *lang.FragmentDefinition:
 				typ := context.GetType()
-				if typ && !util.IsCompositeType(typ) {
-					return lang.NewGraphQLError(
-						fragmentOnNonCompositeErrorMessage(node.Name, printer.Print(node.TypeCondition)),
-						[]lang.INode{node.TypeCondition},
-					)
+				if typ != nil && !isCompositeType(typ) {
+					return newError(
+						fragmentOnNonCompositeErrorMessage(node.Name.Value, lang.Print(node.TypeCondition)),
+						node.TypeCondition)
 				}
 			}
-
 			return nil
 		},
 	}
 }
+
+func isCompositeType(typ typs.QLType) bool {
+	_, ok := typ.(typs.QLCompositeType)
+	return ok
+}
@@ -0,0 +1,18 @@
+package rules
+
+import "testing"
+
+func TestFragmentsOnCompositeTypes(T *testing.T) {
+	expectPassesRule(T, FragmentsOnCompositeTypes, `
+		fragment onObject on Dog { barks }
+		fragment onInterface on Pet { name }
+		fragment onUnion on CatOrDog { __typename }
+		fragment onInline on Pet { ... on Dog { barks } }
+	`)
+	expectFailsRule(T, FragmentsOnCompositeTypes, `
+		fragment scalarFragment on Boolean { bad }
+		fragment inlineInvalid on Dog { ... on String { bad } }
+	`,
+		`Fragment "scalarFragment" cannot condition on non composite type "Boolean".`,
+		`Fragment cannot condition on non composite type "String".`)
+}
@@ -1,6 +1,12 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	typs "github.com/ng-vu/graphql-go/internal/types"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
 
 func unknownArgMessage(argName, fieldName, typ interface{}) string {
 	return fmt.Sprintf(`Unknown argument "%v" on field "%v" of type "%v".`, argName, fieldName, typ)
@@ -10,50 +16,46 @@ func unknownDirectiveArgMessage(argName, directiveName interface{}) string {
 	return fmt.Sprintf(`Unknown argument "%v" on directive "@%v".`, argName, directiveName)
 }
 
-func KnownArgumentNames(context *validation.Context) RuleVisitor {
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			if node, ok := node.(lang.Argument); ok {
-				key, parent, path, ancestors := info.Key, info.Parent, info.Path, info.ancestors
-				argumentOf := ancestors[len(ancestors)-1]
-				if argumentOf.Kind() == lang.INodeKind("Field") {
-					fieldDef := context.GetFieldDef()
-					if fieldDef, ok := fieldDef.(lang.FieldDefinition); ok {
-						fieldArgDef := util.Find(fieldDef.Arguments, func(item interface{}) bool {
-							if item, ok := item.(lang.InputValueDefinition); ok {
-								return item.Name == node.Name.Value
-							}
-							return false
-						})
-						if fieldArgDef, ok := fieldArgDef.(lang.FieldArgDef); ok {
-							parentType := context.GetParentType()
-							util.Invariant(parentType)
-							return lang.NewGraphQLError(
-								unknownArgMessage(node.Name.Value, fieldDef.Name, parentType.Name),
-								[]lang.INode{node},
-							)
-						}
-					}
-				} else if argumentOf.Kind() == lang.INodeKind("Directive") {
-					directive := context.GetDirectvie()
-					if directive {
-						directiveArgDef := util.Find(directive.Arg, func(item interface{}) bool {
-							if item.Name == node.Name.Value {
-								return true
-							}
-
-							return false
-						})
-					}
-
-          if !directiveArgDef {
-            return lang.NewGraphQLError(
-              unknownDirectiveArgMessage(node.Name.Value, directive.Name)
-            )
-          }
+/**
+ * Known argument names
+ *
+ * A GraphQL field is only valid if all supplied arguments are defined by
+ * that field.
+ */
+func KnownArgumentNames(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			argAST, ok := node.(*lang.Argument)
+			if !ok {
+				return nil
+			}
+			switch info.Parent.(type) {
+			case *lang.Field:
+				fieldDef := context.GetFieldDef()
+				if fieldDef != nil && findArgument(fieldDef.Args, argAST.Name.Value) == nil {
+					parentType := context.GetParentType()
+					return newError(
+						unknownArgMessage(argAST.Name.Value, fieldDef.Name, parentType.GetName()),
+						argAST)
+				}
+			case *lang.Directive:
+				directive := context.GetDirective()
+				if directive != nil && findArgument(directive.Args, argAST.Name.Value) == nil {
+					return newError(
+						unknownDirectiveArgMessage(argAST.Name.Value, directive.Name),
+						argAST)
 				}
 			}
 			return nil
 		},
 	}
 }
+
+func findArgument(args []*typs.QLArgument, name string) *typs.QLArgument {
+	for _, arg := range args {
+		if arg.Name == name {
+			return arg
+		}
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package rules
+
+import "testing"
+
+func TestKnownArgumentNames(T *testing.T) {
+	expectPassesRule(T, KnownArgumentNames, `
+		fragment argOnField on Dog {
+			doesKnowCommand(dogCommand: SIT)
+			isHousetrained(atOtherHomes: true) @include(if: true)
+		}
+	`)
+	expectFailsRule(T, KnownArgumentNames, `
+		fragment invalidArgName on Dog {
+			doesKnowCommand(unknown: true)
+			name @skip(unless: true)
+		}
+	`,
+		`Unknown argument "unknown" on field "doesKnowCommand" of type "Dog".`,
+		`Unknown argument "unless" on directive "@skip".`)
+}
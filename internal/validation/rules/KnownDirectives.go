@@ -1,56 +1,108 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+	"github.com/ng-vu/graphql-go/ql"
+)
 
 func unknownDirectiveMessage(directiveName interface{}) string {
 	return fmt.Sprintf(`Unknown directive "%v".`, directiveName)
 }
 
-func misplacedDirectiveMessage(directiveName, placement interface{}) {
-	return fmt.Sprintf(`Directive "%v" may not be used on "%v".`, directiveName, placement)
+func misplacedDirectiveMessage(directiveName, location interface{}) string {
+	return fmt.Sprintf(`Directive "%v" may not be used on %v.`, directiveName, location)
 }
 
-func KnownDirectives(context *validation.Context) RuleVisitor {
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			directiveDef := util.Find(
-				context.GetSchema().GetDirectives(),
-				func(def interface{}) bool {
-					return def.Name == node.Name.Value
-				},
-			)
-
-			if !directiveDef {
-				return lang.NewGraphQLError(
-					unknownDirectiveMessage(node.Name.Value),
-					[]lang.INode{node},
-				)
+/**
+ * Known directives
+ *
+ * A GraphQL document is only valid if all `@directives` are known by the
+ * schema and legally positioned.
+ */
+func KnownDirectives(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			directiveAST, ok := node.(*lang.Directive)
+			if !ok {
+				return nil
 			}
 
-			key, parent, path, ancestors := info.Key, info.Parent, info.Path, info.ancestors
-
-			appliedTo := ancestors[len(ancestors)-1]
-
-			if appliedTo.Kind() == lang.OPERATION_DEFINITION && !directiveDef.OnOperation {
-				return lang.NewGraphQLError(
-					misplacedDirectiveMessage(node.Name.Value, "operation"),
-					[]lang.INode{node},
-				)
+			directiveDef := context.GetSchema().GetDirective(directiveAST.Name.Value)
+			if directiveDef == nil {
+				return newError(unknownDirectiveMessage(directiveAST.Name.Value), directiveAST)
 			}
 
-			if appliedTo.Kind == lang.FIELD && directiveDef.OnField {
-				return lang.NewGraphQLError(
-					misplacedDirectiveMessage(node.Name.Value, "field"),
-					[]lang.INode{node},
-				)
+			location := getDirectiveLocation(info.Ancestors)
+			if location == "" {
+				return newError(misplacedDirectiveMessage(directiveAST.Name.Value, "this node"), directiveAST)
 			}
-
-			if (apploed.Kind() == lang.FRAGMENT_SPREAD || applied.Kind() == lang.INLINE_FRAGMENT || appliedTo.Kind() == lang.FRAGMENT_DEFINITION) && !directiveDef.OnFragment {
-				return lang.NewGraphQLError(
-					misplacedDirectiveMessage(node.Name.Value, "fragment"),
-					[]lang.INode{node},
-				)
+			if !directiveDef.HasLocation(location) {
+				return newError(misplacedDirectiveMessage(directiveAST.Name.Value, location), directiveAST)
 			}
+			return nil
 		},
 	}
 }
+
+/**
+ * Returns the location of a directive applied to the last of the given
+ * ancestors.
+ */
+func getDirectiveLocation(ancestors []lang.INode) ql.DirectiveLocation {
+	if len(ancestors) == 0 {
+		return ""
+	}
+	switch appliedTo := ancestors[len(ancestors)-1].(type) {
+	case *lang.OperationDefinition:
+		switch appliedTo.Operation {
+		case lang.OperationQuery:
+			return ql.LocationQuery
+		case lang.OperationMutation:
+			return ql.LocationMutation
+		case lang.OperationSubscription:
+			return ql.LocationSubscription
+		}
+	case *lang.Field:
+		return ql.LocationField
+	case *lang.FragmentSpread:
+		return ql.LocationFragmentSpread
+	case *lang.InlineFragment:
+		return ql.LocationInlineFragment
+	case *lang.FragmentDefinition:
+		return ql.LocationFragmentDefinition
+	case *lang.VariableDefinition:
+		return ql.LocationVariableDefinition
+	case *lang.SchemaDefinition, *lang.SchemaExtension:
+		return ql.LocationSchema
+	case *lang.ObjectTypeDefinition:
+		return ql.LocationObject
+	case *lang.FieldDefinition:
+		return ql.LocationFieldDefinition
+	case *lang.InputValueDefinition:
+		// Input values are both the arguments of fields and directives, and
+		// the fields of input objects.
+		if len(ancestors) > 1 {
+			switch ancestors[len(ancestors)-2].(type) {
+			case *lang.InputObjectTypeDefinition, *lang.InputObjectTypeExtension:
+				return ql.LocationInputFieldDefinition
+			}
+		}
+		return ql.LocationArgumentDefinition
+	case *lang.InterfaceTypeDefinition, *lang.InterfaceTypeExtension:
+		return ql.LocationInterface
+	case *lang.UnionTypeDefinition, *lang.UnionTypeExtension:
+		return ql.LocationUnion
+	case *lang.ScalarTypeDefinition, *lang.ScalarTypeExtension:
+		return ql.LocationScalar
+	case *lang.EnumTypeDefinition, *lang.EnumTypeExtension:
+		return ql.LocationEnum
+	case *lang.EnumValueDefinition:
+		return ql.LocationEnumValue
+	case *lang.InputObjectTypeDefinition, *lang.InputObjectTypeExtension:
+		return ql.LocationInputObject
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package rules
+
+import "testing"
+
+func TestKnownDirectives(T *testing.T) {
+	expectPassesRule(T, KnownDirectives, `
+		{
+			dog @include(if: true) { name }
+			human @skip(if: false) { name }
+			...Frag @skip(if: true)
+			... on QueryRoot @include(if: true) { dog { name } }
+		}
+		fragment Frag on QueryRoot { dog { name } }
+	`)
+	expectFailsRule(T, KnownDirectives, `
+		{ dog @unknown(directive: "value") { name } }
+	`, `Unknown directive "unknown".`)
+	expectFailsRule(T, KnownDirectives, `
+		query Foo @include(if: true) { dog { name } }
+	`, `Directive "include" may not be used on QUERY.`)
+}
+
+func TestKnownDirectives_WithinSchemaLanguage(T *testing.T) {
+	expectPassesRule(T, KnownDirectives, `
+		type Query {
+			field(arg: Int @onArgumentDefinition): String
+		}
+		directive @myDirective(arg: Int @onArgumentDefinition) on FIELD
+		input MyInput {
+			field: Int @onInputFieldDefinition
+		}
+		extend input MyInput {
+			other: Int @onInputFieldDefinition
+		}
+	`)
+	expectFailsRule(T, KnownDirectives, `
+		type Query {
+			field(arg: Int @onInputFieldDefinition): String
+		}
+		input MyInput {
+			field: Int @onArgumentDefinition
+		}
+	`,
+		`Directive "onInputFieldDefinition" may not be used on ARGUMENT_DEFINITION.`,
+		`Directive "onArgumentDefinition" may not be used on INPUT_FIELD_DEFINITION.`)
+}
@@ -1,23 +1,32 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
 
 func unknownFragmentMessage(fragName interface{}) string {
 	return fmt.Sprintf(`Unknown fragment "%v".`, fragName)
 }
 
-func KnownFragmentNames(context *validation.Context) RuleVisitor {
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			fragmentName := node.Name.Value
-			fragment := context.GetFragment(fragmentName)
-
-			if !fragment {
-				return lang.NewGraphQLError(
-					unknownFragmentMessage(fragmentName),
-					[]lang.INode{node.Name},
-				)
+/**
+ * Known fragment names
+ *
+ * A GraphQL document is only valid if all `...Fragment` fragment spreads refer
+ * to fragments defined in the same document.
+ */
+func KnownFragmentNames(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			if node, ok := node.(*lang.FragmentSpread); ok {
+				fragmentName := node.Name.Value
+				if context.GetFragment(fragmentName) == nil {
+					return newError(unknownFragmentMessage(fragmentName), node.Name)
+				}
 			}
+			return nil
 		},
 	}
 }
@@ -0,0 +1,17 @@
+package rules
+
+import "testing"
+
+func TestKnownFragmentNames(T *testing.T) {
+	expectPassesRule(T, KnownFragmentNames, `
+		{ dog { ...DogFields ... on Dog { ...DogFields } } }
+		fragment DogFields on Dog { name }
+	`)
+	expectFailsRule(T, KnownFragmentNames, `
+		{ dog { ...UnknownFragment1 ... on Dog { ...UnknownFragment2 } } }
+		fragment DogFields on Dog { ...UnknownFragment3 }
+	`,
+		`Unknown fragment "UnknownFragment1".`,
+		`Unknown fragment "UnknownFragment2".`,
+		`Unknown fragment "UnknownFragment3".`)
+}
@@ -1,23 +1,32 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
 
 func unknownTypeMessage(typ interface{}) string {
 	return fmt.Sprintf(`Unknown type "%v".`, typ)
 }
 
-func KnownTypeNames(context *validation.Context) RuleVisitor {
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			typeName := node.Name.Value
-			typ := context.GetSchema().GetType(typeName)
-
-			if !typ {
-				return lang.NewGraphQLError{
-					unknownTypeMessage(typeName),
-					[]lang.INode{node},
+/**
+ * Known type names
+ *
+ * A GraphQL document is only valid if referenced types (specifically
+ * variable definitions and fragment conditions) are defined by the type schema.
+ */
+func KnownTypeNames(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			if node, ok := node.(*lang.NamedType); ok {
+				typeName := node.Name.Value
+				if context.GetSchema().GetType(typeName) == nil {
+					return newError(unknownTypeMessage(typeName), node)
 				}
 			}
+			return nil
 		},
 	}
 }
@@ -0,0 +1,21 @@
+package rules
+
+import "testing"
+
+func TestKnownTypeNames(T *testing.T) {
+	expectPassesRule(T, KnownTypeNames, `
+		query Foo($var: String, $required: [String!]!) {
+			dog { ... on Dog { name } ...PetFields }
+		}
+		fragment PetFields on Pet { name }
+	`)
+	expectFailsRule(T, KnownTypeNames, `
+		query Foo($var: JumbledUpLetters) {
+			dog { ... on Badger { name } ...PetFields }
+		}
+		fragment PetFields on Peettt { name }
+	`,
+		`Unknown type "JumbledUpLetters".`,
+		`Unknown type "Badger".`,
+		`Unknown type "Peettt".`)
+}
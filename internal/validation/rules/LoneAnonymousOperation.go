@@ -1,26 +1,38 @@
 package rules
 
+import (
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
+
 func anonOperationNotAloneMessage() string {
-	return fmt.SPrintf(`This anonymous operation must be the only defined operation.`)
+	return `This anonymous operation must be the only defined operation.`
 }
 
-func LoneAnonymousOperation() RuleVisitor {
+/**
+ * Lone anonymous operation
+ *
+ * A GraphQL document is only valid if when it contains an anonymous operation
+ * (the query short-hand) that it contains only that one operation definition.
+ */
+func LoneAnonymousOperation(context *validation.Context) validation.RuleVisitor {
 	operationCount := 0
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GrahpQLError {
-			switch node.(type) {
-			case Document:
-				operationCount = len(node.Defifinitons.Filter(func(definition interface{}) bool {
-					definition.Kind() == "OperationDefinition"
-				}))
-			case OperationDefinition:
-				if !node.Name && operationCount > 1 {
-					return lang.NewGraphQLError(
-						anonOperationNotAloneMessage(),
-						[]lang.INode{node},
-					)
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			switch node := node.(type) {
+			case *lang.Document:
+				operationCount = 0
+				for _, definition := range node.Definitions {
+					if _, ok := definition.(*lang.OperationDefinition); ok {
+						operationCount++
+					}
+				}
+			case *lang.OperationDefinition:
+				if node.Name == nil && operationCount > 1 {
+					return newError(anonOperationNotAloneMessage(), node)
 				}
 			}
+			return nil
 		},
 	}
 }
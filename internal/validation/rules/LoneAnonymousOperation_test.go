@@ -0,0 +1,24 @@
+package rules
+
+import "testing"
+
+func TestLoneAnonymousOperation(T *testing.T) {
+	expectPassesRule(T, LoneAnonymousOperation, `
+		{ dog { name } }
+		fragment F on Dog { name }
+	`)
+	expectPassesRule(T, LoneAnonymousOperation, `
+		query Foo { dog { name } }
+		query Bar { dog { name } }
+	`)
+	expectFailsRule(T, LoneAnonymousOperation, `
+		{ dog { name } }
+		query Foo { dog { name } }
+	`, `This anonymous operation must be the only defined operation.`)
+	expectFailsRule(T, LoneAnonymousOperation, `
+		{ dog { name } }
+		{ dog { nickname } }
+	`,
+		`This anonymous operation must be the only defined operation.`,
+		`This anonymous operation must be the only defined operation.`)
+}
@@ -0,0 +1,156 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
+
+func maxDepthMessage(fieldName interface{}, depth, maxDepth int) string {
+	return fmt.Sprintf(
+		`Field "%v" is nested at depth %v, which exceeds the maximum depth of %v.`,
+		fieldName, depth, maxDepth)
+}
+
+/**
+ * Max depth
+ *
+ * A GraphQL operation is only valid if its fields are not nested deeper than
+ * the given depth, following fragment spreads. Top level fields are at depth
+ * 1. When ignoreIntrospection is set, the introspection fields (the ones
+ * starting with "__") and their selections are not counted.
+ */
+func MaxDepth(maxDepth int, ignoreIntrospection bool) validation.RuleCreator {
+	return func(context *validation.Context) validation.RuleVisitor {
+		checker := &_DepthChecker{
+			context:             context,
+			maxDepth:            maxDepth,
+			ignoreIntrospection: ignoreIntrospection,
+			fragments:           make(map[string]int),
+			spreadPath:          make(map[string]struct{}),
+		}
+		return validation.RuleVisitor{
+			Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+				if operation, ok := node.(*lang.OperationDefinition); ok {
+					return checker.check(operation.SelectionSet, 1)
+				}
+				return nil
+			},
+		}
+	}
+}
+
+/**
+ * Measures the depth of the selection sets of a document. The depth of each
+ * fragment is computed only once, so that fragments spreading other fragments
+ * many times can not make the checking itself expensive.
+ */
+type _DepthChecker struct {
+	context             *validation.Context
+	maxDepth            int
+	ignoreIntrospection bool
+
+	fragments map[string]int
+
+	// Fragments currently being measured or checked, to stop on cycles.
+	// Those are reported by NoFragmentCycles.
+	spreadPath map[string]struct{}
+}
+
+func (c *_DepthChecker) isIgnored(field *lang.Field) bool {
+	return c.ignoreIntrospection && strings.HasPrefix(field.Name.Value, "__")
+}
+
+/**
+ * Returns the depth of the deepest field of the selection set, where its own
+ * fields are at depth 1, or 0 if it has no fields.
+ */
+func (c *_DepthChecker) depth(selectionSet *lang.SelectionSet) int {
+	result := 0
+	if selectionSet == nil {
+		return result
+	}
+	for _, selection := range selectionSet.Selections {
+		depth := 0
+		switch selection := selection.(type) {
+		case *lang.Field:
+			if !c.isIgnored(selection) {
+				depth = 1 + c.depth(selection.SelectionSet)
+			}
+		case *lang.InlineFragment:
+			depth = c.depth(selection.SelectionSet)
+		case *lang.FragmentSpread:
+			depth = c.fragmentDepth(selection.Name.Value)
+		}
+		if depth > result {
+			result = depth
+		}
+	}
+	return result
+}
+
+func (c *_DepthChecker) fragmentDepth(name string) int {
+	if depth, ok := c.fragments[name]; ok {
+		return depth
+	}
+	if _, spreading := c.spreadPath[name]; spreading {
+		return 0
+	}
+	fragment := c.context.GetFragment(name)
+	if fragment == nil {
+		return 0
+	}
+
+	c.spreadPath[name] = struct{}{}
+	depth := c.depth(fragment.SelectionSet)
+	delete(c.spreadPath, name)
+
+	c.fragments[name] = depth
+	return depth
+}
+
+/**
+ * Returns an error for the first field found deeper than the maximum depth,
+ * where the fields of the selection set are at the given depth. Only the
+ * selections which are too deep are walked.
+ */
+func (c *_DepthChecker) check(selectionSet *lang.SelectionSet, depth int) *lang.QLError {
+	if selectionSet == nil || depth-1+c.depth(selectionSet) <= c.maxDepth {
+		return nil
+	}
+	for _, selection := range selectionSet.Selections {
+		var err *lang.QLError
+		switch selection := selection.(type) {
+		case *lang.Field:
+			if c.isIgnored(selection) {
+				continue
+			}
+			if depth > c.maxDepth {
+				return newError(maxDepthMessage(selection.Name.Value, depth, c.maxDepth), selection)
+			}
+			err = c.check(selection.SelectionSet, depth+1)
+
+		case *lang.InlineFragment:
+			err = c.check(selection.SelectionSet, depth)
+
+		case *lang.FragmentSpread:
+			name := selection.Name.Value
+			if _, spreading := c.spreadPath[name]; spreading {
+				continue
+			}
+			fragment := c.context.GetFragment(name)
+			if fragment == nil {
+				continue
+			}
+			c.spreadPath[name] = struct{}{}
+			err = c.check(fragment.SelectionSet, depth)
+			delete(c.spreadPath, name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
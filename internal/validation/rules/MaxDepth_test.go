@@ -0,0 +1,70 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMaxDepth(T *testing.T) {
+	expectPassesRule(T, MaxDepth(3, false), `
+		{ human { relatives { name } } dog { name } }
+	`)
+	expectFailsRule(T, MaxDepth(2, false), `
+		{ human { relatives { name } } dog { name } }
+	`, `Field "name" is nested at depth 3, which exceeds the maximum depth of 2.`)
+	expectFailsRule(T, MaxDepth(1, false), `
+		query A { dog { name } }
+		query B { human { name } }
+	`,
+		`Field "name" is nested at depth 2, which exceeds the maximum depth of 1.`,
+		`Field "name" is nested at depth 2, which exceeds the maximum depth of 1.`)
+}
+
+func TestMaxDepth_FollowsFragments(T *testing.T) {
+	query := `
+		{ human { ...Relatives } }
+		fragment Relatives on Human { relatives { ... on Human { ...Pets } } }
+		fragment Pets on Human { pets { name } }
+	`
+	expectPassesRule(T, MaxDepth(4, false), query)
+	expectFailsRule(T, MaxDepth(3, false), query,
+		`Field "name" is nested at depth 4, which exceeds the maximum depth of 3.`)
+
+	// The same fragment may be spread at different depths
+	expectFailsRule(T, MaxDepth(2, false), `
+		{ human { ...Name } other: human { relatives { ...Name } } }
+		fragment Name on Human { name }
+	`, `Field "name" is nested at depth 3, which exceeds the maximum depth of 2.`)
+}
+
+func TestMaxDepth_IgnoresIntrospection(T *testing.T) {
+	query := `{ __schema { queryType { fields { name } } } dog { name } }`
+	expectPassesRule(T, MaxDepth(2, true), query)
+	expectFailsRule(T, MaxDepth(2, false), query,
+		`Field "fields" is nested at depth 3, which exceeds the maximum depth of 2.`)
+}
+
+func TestMaxDepth_StopsOnCycles(T *testing.T) {
+	expectPassesRule(T, MaxDepth(3, false), `
+		{ human { ...A } }
+		fragment A on Human { name ...B }
+		fragment B on Human { ...A }
+	`)
+}
+
+func TestMaxDepth_FragmentFanOut(T *testing.T) {
+	// Each fragment spreads the next one twice, so walking every spread would
+	// visit 2^40 fields. The depth of each fragment is only computed once.
+	const n = 40
+	var query strings.Builder
+	query.WriteString("{ human { ...F0 } }\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&query, "fragment F%v on Human { relatives { ...F%v } ...F%v }\n", i, i+1, i+1)
+	}
+	fmt.Fprintf(&query, "fragment F%v on Human { name }\n", n)
+
+	expectPassesRule(T, MaxDepth(n+2, false), query.String())
+	expectFailsRule(T, MaxDepth(n+1, false), query.String(),
+		fmt.Sprintf(`Field "name" is nested at depth %v, which exceeds the maximum depth of %v.`, n+2, n+1))
+}
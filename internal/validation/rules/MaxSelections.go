@@ -0,0 +1,229 @@
+package rules
+
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
+
+func maxSelectionsMessage(operation *lang.OperationDefinition, count int, what string, max int) string {
+	name := "Operation"
+	if operation.Name != nil {
+		name = fmt.Sprintf(`Operation "%v"`, operation.Name.Value)
+	}
+	return fmt.Sprintf(`%v has %v %v, which exceeds the maximum of %v.`, name, count, what, max)
+}
+
+/**
+ * Max aliases
+ *
+ * A GraphQL operation is only valid if it does not contain more than the
+ * given number of aliased fields, counting the fields of a fragment each time
+ * it is spread.
+ */
+func MaxAliases(max int) validation.RuleCreator {
+	return maxSelections(max, "aliases", func(count _SelectionCount) int {
+		return count.aliases
+	})
+}
+
+/**
+ * Max fields
+ *
+ * A GraphQL operation is only valid if it does not select more than the given
+ * number of fields in total, counting the fields of a fragment each time it
+ * is spread.
+ */
+func MaxFields(max int) validation.RuleCreator {
+	return maxSelections(max, "fields", func(count _SelectionCount) int {
+		return count.fields
+	})
+}
+
+/**
+ * Max root fields
+ *
+ * A GraphQL operation is only valid if it does not select more than the given
+ * number of fields on its root type, including the ones from fragments.
+ */
+func MaxRootFields(max int) validation.RuleCreator {
+	return maxSelections(max, "root fields", func(count _SelectionCount) int {
+		return count.rootFields
+	})
+}
+
+/**
+ * Max directives
+ *
+ * A GraphQL document is only valid if no directive is applied more than the
+ * given number of times to the same operation, field, fragment or fragment
+ * spread.
+ */
+func MaxDirectives(max int) validation.RuleCreator {
+	return func(context *validation.Context) validation.RuleVisitor {
+		return validation.RuleVisitor{
+			Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+				var directives []*lang.Directive
+				switch node := node.(type) {
+				case *lang.OperationDefinition:
+					directives = node.Directives
+				case *lang.Field:
+					directives = node.Directives
+				case *lang.FragmentSpread:
+					directives = node.Directives
+				case *lang.InlineFragment:
+					directives = node.Directives
+				case *lang.FragmentDefinition:
+					directives = node.Directives
+				}
+				if len(directives) <= max {
+					return nil
+				}
+
+				counts := make(map[string]int)
+				for _, directive := range directives {
+					counts[directive.Name.Value]++
+				}
+				for _, directive := range directives {
+					if count := counts[directive.Name.Value]; count > max {
+						return newError(maxDirectivesMessage(directive.Name.Value, count, node, max), node)
+					}
+				}
+				return nil
+			},
+		}
+	}
+}
+
+func maxDirectivesMessage(directiveName string, count int, node lang.INode, max int) string {
+	var target string
+	switch node := node.(type) {
+	case *lang.OperationDefinition:
+		target = "the operation"
+		if node.Name != nil {
+			target = fmt.Sprintf(`operation "%v"`, node.Name.Value)
+		}
+	case *lang.Field:
+		target = fmt.Sprintf(`field "%v"`, node.Name.Value)
+	case *lang.FragmentSpread:
+		target = fmt.Sprintf(`fragment spread "%v"`, node.Name.Value)
+	case *lang.InlineFragment:
+		target = "an inline fragment"
+	case *lang.FragmentDefinition:
+		target = fmt.Sprintf(`fragment "%v"`, node.Name.Value)
+	}
+	return fmt.Sprintf(`Directive "@%v" is applied %v times to %v, which exceeds the maximum of %v.`,
+		directiveName, count, target, max)
+}
+
+func maxSelections(max int, what string, get func(_SelectionCount) int) validation.RuleCreator {
+	return func(context *validation.Context) validation.RuleVisitor {
+		counter := &_SelectionCounter{
+			context:    context,
+			fragments:  make(map[string]_SelectionCount),
+			spreadPath: make(map[string]struct{}),
+		}
+		return validation.RuleVisitor{
+			Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+				operation, ok := node.(*lang.OperationDefinition)
+				if !ok {
+					return nil
+				}
+				count := counter.countSelectionSet(operation.SelectionSet)
+				count.rootFields = count.fields - count.nestedFields
+				if n := get(count); n > max {
+					return newError(maxSelectionsMessage(operation, n, what, max), operation)
+				}
+				return nil
+			},
+		}
+	}
+}
+
+type _SelectionCount struct {
+	fields       int
+	nestedFields int
+	rootFields   int
+	aliases      int
+}
+
+func (c _SelectionCount) add(other _SelectionCount) _SelectionCount {
+	return _SelectionCount{
+		fields:       addCount(c.fields, other.fields),
+		nestedFields: addCount(c.nestedFields, other.nestedFields),
+		aliases:      addCount(c.aliases, other.aliases),
+	}
+}
+
+/**
+ * Counts the selections of an operation as if all its fragments were
+ * inlined. The count of each fragment is computed only once, so that fragments
+ * spreading other fragments many times can not make the counting itself
+ * expensive.
+ */
+type _SelectionCounter struct {
+	context *validation.Context
+
+	fragments map[string]_SelectionCount
+
+	// Fragments currently being counted, to stop on cycles. Those are
+	// reported by NoFragmentCycles.
+	spreadPath map[string]struct{}
+}
+
+func (c *_SelectionCounter) countSelectionSet(selectionSet *lang.SelectionSet) _SelectionCount {
+	var count _SelectionCount
+	if selectionSet == nil {
+		return count
+	}
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *lang.Field:
+			sub := c.countSelectionSet(selection.SelectionSet)
+			sub.nestedFields = sub.fields
+			count = count.add(sub)
+			count.fields = addCount(count.fields, 1)
+			if selection.Alias != nil {
+				count.aliases = addCount(count.aliases, 1)
+			}
+
+		case *lang.InlineFragment:
+			count = count.add(c.countSelectionSet(selection.SelectionSet))
+
+		case *lang.FragmentSpread:
+			count = count.add(c.countFragment(selection.Name.Value))
+		}
+	}
+	return count
+}
+
+func (c *_SelectionCounter) countFragment(name string) _SelectionCount {
+	if count, ok := c.fragments[name]; ok {
+		return count
+	}
+	if _, counting := c.spreadPath[name]; counting {
+		return _SelectionCount{}
+	}
+	fragment := c.context.GetFragment(name)
+	if fragment == nil {
+		return _SelectionCount{}
+	}
+
+	c.spreadPath[name] = struct{}{}
+	count := c.countSelectionSet(fragment.SelectionSet)
+	delete(c.spreadPath, name)
+
+	c.fragments[name] = count
+	return count
+}
+
+// Counts are saturated at this value instead of overflowing.
+const maxCount = int(^uint(0) >> 2)
+
+func addCount(a, b int) int {
+	if a > maxCount-b {
+		return maxCount
+	}
+	return a + b
+}
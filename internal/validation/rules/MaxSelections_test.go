@@ -0,0 +1,122 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
+
+func TestMaxFields(T *testing.T) {
+	query := `{ dog { name barks } human { name } }`
+	expectPassesRule(T, MaxFields(5), query)
+	expectFailsRule(T, MaxFields(4), query,
+		`Operation has 5 fields, which exceeds the maximum of 4.`)
+
+	expectFailsRule(T, MaxFields(1), `
+		query A { dog { name } }
+		query B { dog }
+	`, `Operation "A" has 2 fields, which exceeds the maximum of 1.`)
+}
+
+func TestMaxFields_CountsFragmentsEachTimeTheyAreSpread(T *testing.T) {
+	query := `
+		{ dog { ...DogFields } other: dog { ...DogFields } }
+		fragment DogFields on Dog { name barks ...Owner }
+		fragment Owner on Dog { owner { name } }
+	`
+	// Each spread of DogFields counts name, barks, owner and owner.name
+	expectPassesRule(T, MaxFields(10), query)
+	expectFailsRule(T, MaxFields(9), query,
+		`Operation has 10 fields, which exceeds the maximum of 9.`)
+
+	expectFailsRule(T, MaxFields(2), `
+		{ dog { ... on Dog { name barks } } }
+	`, `Operation has 3 fields, which exceeds the maximum of 2.`)
+}
+
+func TestMaxFields_FragmentFanOut(T *testing.T) {
+	// Each fragment spreads the next one twice, so the count doubles at every
+	// level while each fragment is only counted once.
+	const n = 20
+	var query strings.Builder
+	query.WriteString(`{ human { ...F0 } }`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&query, " fragment F%v on Human { ...F%v ...F%v }", i, i+1, i+1)
+	}
+	fmt.Fprintf(&query, " fragment F%v on Human { name }", n)
+
+	expectPassesRule(T, MaxFields(1<<n+1), query.String())
+	expectFailsRule(T, MaxFields(1<<n), query.String(),
+		fmt.Sprintf(`Operation has %v fields, which exceeds the maximum of %v.`, 1<<n+1, 1<<n))
+}
+
+func TestMaxFields_StopsOnCycles(T *testing.T) {
+	expectPassesRule(T, MaxFields(2), `
+		{ human { ...A } }
+		fragment A on Human { name ...B }
+		fragment B on Human { ...A }
+	`)
+}
+
+func TestMaxRootFields(T *testing.T) {
+	query := `
+		{ dog { name barks } ...Root }
+		fragment Root on QueryRoot { human { name } cat { name } }
+	`
+	expectPassesRule(T, MaxRootFields(3), query)
+	expectFailsRule(T, MaxRootFields(2), query,
+		`Operation has 3 root fields, which exceeds the maximum of 2.`)
+}
+
+func TestMaxAliases(T *testing.T) {
+	query := `
+		{ a: dog { ...Names } b: dog { name } }
+		fragment Names on Dog { first: name second: name }
+	`
+	expectPassesRule(T, MaxAliases(4), query)
+	expectFailsRule(T, MaxAliases(3), query,
+		`Operation has 4 aliases, which exceeds the maximum of 3.`)
+}
+
+func TestMaxDirectives(T *testing.T) {
+	// Only the repetitions of a directive on the same node are counted
+	expectPassesRule(T, MaxDirectives(2), `
+		query Q($skip: Boolean!) @include(if: true) @include(if: true) {
+			dog @skip(if: $skip) @skip(if: false) @include(if: true) @include(if: true) {
+				...Name @include(if: true) @include(if: true)
+			}
+			... on QueryRoot @skip(if: false) @skip(if: false) { cat { name } }
+		}
+		fragment Name on Dog @skip(if: false) @skip(if: false) { name @include(if: true) }
+	`)
+
+	expectFailsRule(T, MaxDirectives(2), `
+		query Q @include(if: true) @include(if: true) @include(if: true) {
+			dog @skip(if: false) @include(if: true) @skip(if: false) @skip(if: false) {
+				...Name @include(if: true) @include(if: true) @include(if: true)
+			}
+			... on QueryRoot @skip(if: false) @skip(if: false) @skip(if: false) { cat { name } }
+		}
+		fragment Name on Dog @skip(if: false) @skip(if: false) @skip(if: false) { name }
+	`,
+		`Directive "@include" is applied 3 times to operation "Q", which exceeds the maximum of 2.`,
+		`Directive "@skip" is applied 3 times to field "dog", which exceeds the maximum of 2.`,
+		`Directive "@include" is applied 3 times to fragment spread "Name", which exceeds the maximum of 2.`,
+		`Directive "@skip" is applied 3 times to an inline fragment, which exceeds the maximum of 2.`,
+		`Directive "@skip" is applied 3 times to fragment "Name", which exceeds the maximum of 2.`)
+}
+
+func TestMaxDirectives_ReportsTheNode(T *testing.T) {
+	errs := validate(T, []validation.RuleCreator{MaxDirectives(1)}, `
+		{ dog { name @include(if: true) @include(if: true) } }
+	`)
+	if len(errs) != 1 || len(errs[0].Nodes) != 1 {
+		T.Fatalf("Expect one error on one node but got: %v", errs)
+	}
+	if field, ok := errs[0].Nodes[0].(*lang.Field); !ok || field.Name.Value != "name" {
+		T.Errorf("Expect the error on field name but got: %#v", errs[0].Nodes[0])
+	}
+}
@@ -1,73 +1,95 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
 
-func cycleErrorMessage(fragName interface{}, spreadNames []interface{}) string {
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
+
+func cycleErrorMessage(fragName interface{}, spreadNames []string) string {
 	via := ""
 	if len(spreadNames) > 0 {
-		via = strings.join(spreadNames, ",")
+		via = " via " + strings.Join(spreadNames, ", ")
 	}
-
-	return fmt.Sprintf(`Cannot spread fragment "%v" within itself %v.`, fragName)
+	return fmt.Sprintf(`Cannot spread fragment "%v" within itself%v.`, fragName, via)
 }
 
-func NoFragmentCycles(context *validation.Context) RuleVisitor {
-	definitions := context.GetDocument().Definitions
-	spreadsInFragment = definitions.Reduce(func(m, node) map[string]interface{} {
-		if node.Kind() == lang.FRAGMENT_DEFINITION {
-			m[node.Name.Value] = gatherSpreads(node)
+/**
+ * No fragment cycles
+ *
+ * A GraphQL document is only valid if the fragments it defines do not spread
+ * into themselves, directly or through other fragments. Only the first cycle
+ * found from each fragment is reported.
+ */
+func NoFragmentCycles(context *validation.Context) validation.RuleVisitor {
+	// Gather all the fragment spreads ASTs for each fragment definition.
+	spreadsInFragment := make(map[string][]*lang.FragmentSpread)
+	for _, definition := range context.GetDocument().Definitions {
+		if definition, ok := definition.(*lang.FragmentDefinition); ok {
+			name := definition.Name.Value
+			eachFragmentSpread(definition.SelectionSet, func(spread *lang.FragmentSpread) {
+				spreadsInFragment[name] = append(spreadsInFragment[name], spread)
+			})
 		}
+	}
 
-		return m
-	}, o)
-
-	knownToLeadToCycle = Set()
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			errors := []Error{}
-			initialName := noed.Name.Value
+	// Tracks spreads known to lead to cycles to ensure that cycles are not
+	// redundantly reported.
+	knownToLeadToCycle := make(map[*lang.FragmentSpread]struct{})
 
-			spreadPath := []lang.INode{}
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			fragment, ok := node.(*lang.FragmentDefinition)
+			if !ok {
+				return nil
+			}
 
-			detectCycleRecursive := func(fragementName interface{}) {
-				spreadNodes = spreadsInFragement[fragmentName]
-				for i := 0; i < len(spreadNodes); i++ {
-					spreadNode := spreadNodes[i]
-					if knownToLeadToCycle.Has(spreadNode) {
+			initialName := fragment.Name.Value
+			var spreadPath []*lang.FragmentSpread
+			var detectCycleRecursive func(fragmentName string) *lang.QLError
+			detectCycleRecursive = func(fragmentName string) *lang.QLError {
+				for _, spreadNode := range spreadsInFragment[fragmentName] {
+					if _, known := knownToLeadToCycle[spreadNode]; known {
 						continue
 					}
-
 					if spreadNode.Name.Value == initialName {
 						cyclePath := append(spreadPath, spreadNode)
-
-						for j := 0; j < len(cyclePath); j++ {
-							knowToLoadToCyle.Add(cyclePath[j])
-							errors = append(errors, lang.NewGraphQLError(
-								cycleErrorMessage(intitalName, speadPath)),
-								cyclePath)
+						spreadNames := make([]string, len(spreadPath))
+						nodes := make([]lang.INode, len(cyclePath))
+						for i, spread := range cyclePath {
+							knownToLeadToCycle[spread] = struct{}{}
+							if i < len(spreadPath) {
+								spreadNames[i] = spread.Name.Value
+							}
+							nodes[i] = spread
 						}
-						continue
+						return newError(cycleErrorMessage(initialName, spreadNames), nodes...)
 					}
-
-					if spreadPath.Some(spread == spreadNode) {
+					if isSpreadInPath(spreadPath, spreadNode) {
 						continue
 					}
 
 					spreadPath = append(spreadPath, spreadNode)
-
-					detectCycleRecursive(initialName)
-
-					if len(errors) > 0 {
-						return errors
+					err := detectCycleRecursive(spreadNode.Name.Value)
+					spreadPath = spreadPath[:len(spreadPath)-1]
+					if err != nil {
+						return err
 					}
 				}
+				return nil
 			}
+			return detectCycleRecursive(initialName)
 		},
 	}
 }
 
-func gatherSpreads(node []lang.INode) []lang.INode {
-	spreadNodes = []lang.INode{}
-	// visit(node, )
-	return spreadNodes
+func isSpreadInPath(spreadPath []*lang.FragmentSpread, spreadNode *lang.FragmentSpread) bool {
+	for _, spread := range spreadPath {
+		if spread.Name.Value == spreadNode.Name.Value {
+			return true
+		}
+	}
+	return false
 }
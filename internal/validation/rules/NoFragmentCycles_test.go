@@ -0,0 +1,19 @@
+package rules
+
+import "testing"
+
+func TestNoFragmentCycles(T *testing.T) {
+	expectPassesRule(T, NoFragmentCycles, `
+		fragment fragA on Dog { ...fragB ...fragB }
+		fragment fragB on Dog { name ...fragC }
+		fragment fragC on Dog { name }
+	`)
+	expectFailsRule(T, NoFragmentCycles, `
+		fragment fragA on Dog { ...fragA }
+	`, `Cannot spread fragment "fragA" within itself.`)
+	expectFailsRule(T, NoFragmentCycles, `
+		fragment fragA on Dog { ...fragB }
+		fragment fragB on Dog { ... on Dog { ...fragC } }
+		fragment fragC on Dog { ...fragA }
+	`, `Cannot spread fragment "fragA" within itself via fragB, fragC.`)
+}
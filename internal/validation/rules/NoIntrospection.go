@@ -0,0 +1,35 @@
+package rules
+
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	typs "github.com/ng-vu/graphql-go/internal/types"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
+
+func introspectionDisabledMessage(fieldName interface{}) string {
+	return fmt.Sprintf(
+		`GraphQL introspection is not allowed, but the query contained the field "%v".`,
+		fieldName)
+}
+
+/**
+ * No introspection
+ *
+ * A GraphQL document is only valid if it does not select the introspection
+ * fields __schema and __type. The __typename field is still allowed.
+ */
+func NoIntrospection(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			if field, ok := node.(*lang.Field); ok {
+				name := field.Name.Value
+				if name == typs.SchemaMetaFieldDef.Name || name == typs.TypeMetaFieldDef.Name {
+					return newError(introspectionDisabledMessage(name), field)
+				}
+			}
+			return nil
+		},
+	}
+}
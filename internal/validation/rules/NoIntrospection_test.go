@@ -0,0 +1,21 @@
+package rules
+
+import "testing"
+
+func TestNoIntrospection(T *testing.T) {
+	expectPassesRule(T, NoIntrospection, `
+		{ dog { __typename name } pet { __typename ... on Cat { meowVolume } } }
+	`)
+	expectFailsRule(T, NoIntrospection, `
+		{ __schema { queryType { name } } }
+	`, `GraphQL introspection is not allowed, but the query contained the field "__schema".`)
+	expectFailsRule(T, NoIntrospection, `
+		{ __type(name: "Dog") { name } }
+	`, `GraphQL introspection is not allowed, but the query contained the field "__type".`)
+
+	// Introspection hidden in fragments is rejected as well
+	expectFailsRule(T, NoIntrospection, `
+		{ dog { ...Schema } }
+		fragment Schema on Dog { __typename owner { ... on Human { __schema { types { name } } } } }
+	`, `GraphQL introspection is not allowed, but the query contained the field "__schema".`)
+}
@@ -1,6 +1,11 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
 
 func undefinedVarMessage(varName interface{}) string {
 	return fmt.Sprintf(`Variable "$%v" is not defined.`, varName)
@@ -10,44 +15,41 @@ func undefinedVarByOpMessage(varName, opName interface{}) string {
 	return fmt.Sprintf(`Variable "$%v" is not defined by operation "%v".`, varName, opName)
 }
 
-func NoUndefinedVariables() RuleVisitor {
-	// var operation;
-	// var visitedFragmentNames = {};
-	// var definedVariableNames = {};
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			visitSpreadFragments := true
-
-			switch node.(type) {
-			case OperationDefinition:
-				operation = node
-				visitFragmentNames = []lang.INode{}
-				definitionVariableNames = []lang.INode{}
-			case VariableDefinition:
-				definedVaribaleNmaes[node.Variable.Name.Value] = true
-			case Variable:
-				key, parent, path, ancestors := info.Key, info.Parent, info.Path, key.Ancestors
-				varName := variable.Name.Value
-				if definedVariableNames[varName] != true {
-					withinFragment := ancestors.Some(node.kind == FRAGMENT_DEFINITION)
+/**
+ * No undefined variables
+ *
+ * A GraphQL operation is only valid if all variables encountered, both directly
+ * and via fragment spreads, are defined by that operation. Only the first
+ * undefined variable of each operation is reported.
+ */
+func NoUndefinedVariables(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Leave: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			operation, ok := node.(*lang.OperationDefinition)
+			if !ok {
+				return nil
+			}
 
-					if withinFragment && operation && operation.Name {
-						return lang.NewGraphError(
-							undefinedVarByOpMessage(varName, operation.Name.Value), []lang.INode{variable, operation},
-						)
-					}
+			definedVariableNames := make(map[string]struct{})
+			for _, varDef := range operation.VariableDefinitions {
+				definedVariableNames[varDef.Variable.Name.Value] = struct{}{}
+			}
 
-					return lang.NewGraphError(
-						undefinedVarMessage(varName), []lanag.Node{node},
-					)
+			var err *lang.QLError
+			eachVariableUsage(context, operation.SelectionSet, func(variable *lang.Variable) {
+				varName := variable.Name.Value
+				if _, defined := definedVariableNames[varName]; defined || err != nil {
+					return
 				}
-			case FragmentSpread:
-				if visitedFragmentNames[node.Name.Value] == true {
-					return false
+				if operation.Name != nil {
+					err = newError(
+						undefinedVarByOpMessage(varName, operation.Name.Value),
+						variable, operation)
+					return
 				}
-
-				visitedFragmentNames[node.Name.Value] = true
-			}
+				err = newError(undefinedVarMessage(varName), variable)
+			})
+			return err
 		},
 	}
 }
@@ -0,0 +1,19 @@
+package rules
+
+import "testing"
+
+func TestNoUndefinedVariables(T *testing.T) {
+	expectPassesRule(T, NoUndefinedVariables, `
+		query Foo($a: String, $b: String) {
+			dog { name(surname: $a) ...Frag }
+		}
+		fragment Frag on Dog { nickname @include(if: $b) }
+	`)
+	expectFailsRule(T, NoUndefinedVariables, `
+		query Foo($a: String) { dog { name(surname: $b) } }
+	`, `Variable "$b" is not defined by operation "Foo".`)
+	expectFailsRule(T, NoUndefinedVariables, `
+		{ dog { ...Frag } }
+		fragment Frag on Dog { name(surname: $a) }
+	`, `Variable "$a" is not defined.`)
+}
@@ -1,29 +1,59 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
 
 func unusedFragMessage(fragName interface{}) string {
 	return fmt.Sprintf(`Fragment "%v" is never used.`, fragName)
 }
 
-func NoUnusedFragments() RuleVisitor {
-	// var fragmentDefs = [];
-	// var spreadsWithinOperation = [];
-	// var fragAdjacencies = {};
-	// var spreadNames = {};
+/**
+ * No unused fragments
+ *
+ * A GraphQL document is only valid if all fragment definitions are spread
+ * within operations, or spread within other fragments spread within
+ * operations. Only the first unused fragment is reported.
+ */
+func NoUnusedFragments(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Leave: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			document, ok := node.(*lang.Document)
+			if !ok {
+				return nil
+			}
 
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			switch node.(type) {
-			case OperationDefinition:
-			case FragmentDefinition:
-			case FragmentSpread:
+			fragmentNameUsed := make(map[string]struct{})
+			var reduceSpreadFragments func(*lang.SelectionSet)
+			reduceSpreadFragments = func(selectionSet *lang.SelectionSet) {
+				eachFragmentSpread(selectionSet, func(spread *lang.FragmentSpread) {
+					name := spread.Name.Value
+					if _, used := fragmentNameUsed[name]; used {
+						return
+					}
+					fragmentNameUsed[name] = struct{}{}
+					if fragment := context.GetFragment(name); fragment != nil {
+						reduceSpreadFragments(fragment.SelectionSet)
+					}
+				})
 			}
-		},
-		Leave: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			switch node.(type) {
-			case Document:
+			for _, definition := range document.Definitions {
+				if operation, ok := definition.(*lang.OperationDefinition); ok {
+					reduceSpreadFragments(operation.SelectionSet)
+				}
+			}
+
+			for _, definition := range document.Definitions {
+				if fragment, ok := definition.(*lang.FragmentDefinition); ok {
+					if _, used := fragmentNameUsed[fragment.Name.Value]; !used {
+						return newError(unusedFragMessage(fragment.Name.Value), fragment)
+					}
+				}
 			}
+			return nil
 		},
 	}
 }
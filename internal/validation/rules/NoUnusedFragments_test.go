@@ -0,0 +1,19 @@
+package rules
+
+import "testing"
+
+func TestNoUnusedFragments(T *testing.T) {
+	expectPassesRule(T, NoUnusedFragments, `
+		query Foo { dog { ...Frag1 } }
+		query Bar { dog { ...Frag2 } }
+		fragment Frag1 on Dog { name ...Frag3 }
+		fragment Frag2 on Dog { name }
+		fragment Frag3 on Dog { name }
+	`)
+	expectFailsRule(T, NoUnusedFragments, `
+		query Foo { dog { ...Frag1 } }
+		fragment Frag1 on Dog { name }
+		fragment Unused1 on Dog { name ...Unused2 }
+		fragment Unused2 on Dog { name ...Unused1 }
+	`, `Fragment "Unused1" is never used.`)
+}
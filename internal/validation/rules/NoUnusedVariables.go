@@ -1,27 +1,39 @@
 package rules
 
 import (
+	"fmt"
+
 	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
 )
 
-type noUnusedVariables struct {
+func unusedVariableMessage(varName interface{}) string {
+	return fmt.Sprintf(`Variable "$%v" is never used.`, varName)
 }
 
-func NoUnusedVariables() RuleVisitor {
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			switch node.(type) {
-			case lang.OperationDefinition:
-			case lang.VariableDefinition:
-			case lang.Variable:
-			case lang.FragmentSpread:
+/**
+ * No unused variables
+ *
+ * A GraphQL operation is only valid if all variables defined by an operation
+ * are used, either directly or within a spread fragment. Only the first unused
+ * variable of each operation is reported.
+ */
+func NoUnusedVariables(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Leave: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			operation, ok := node.(*lang.OperationDefinition)
+			if !ok {
+				return nil
 			}
-			return nil
-		},
 
-		Leave: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			switch node.(type) {
-			case lang.OperationDefinition:
+			variableNameUsed := make(map[string]struct{})
+			eachVariableUsage(context, operation.SelectionSet, func(variable *lang.Variable) {
+				variableNameUsed[variable.Name.Value] = struct{}{}
+			})
+			for _, varDef := range operation.VariableDefinitions {
+				if _, used := variableNameUsed[varDef.Variable.Name.Value]; !used {
+					return newError(unusedVariableMessage(varDef.Variable.Name.Value), varDef)
+				}
 			}
 			return nil
 		},
@@ -0,0 +1,18 @@
+package rules
+
+import "testing"
+
+func TestNoUnusedVariables(T *testing.T) {
+	expectPassesRule(T, NoUnusedVariables, `
+		query Foo($a: String, $b: String) {
+			dog { name(surname: $a) ...Frag }
+		}
+		fragment Frag on Dog { nickname @include(if: $b) }
+	`)
+	expectFailsRule(T, NoUnusedVariables, `
+		query Foo($a: String, $b: String, $c: String) {
+			dog { name(surname: $a) ...Frag }
+		}
+		fragment Frag on Dog { nickname @include(if: $b) }
+	`, `Variable "$c" is never used.`)
+}
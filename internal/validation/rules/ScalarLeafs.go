@@ -1,6 +1,12 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	typs "github.com/ng-vu/graphql-go/internal/types"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
 
 func noSubselectionAllowedMessage(field, typ interface{}) string {
 	return fmt.Sprintf(`Field "%v" of type "%v" must not have a sub selection.`, field, typ)
@@ -10,25 +16,35 @@ func requiredSubselectionMessage(field, typ interface{}) string {
 	return fmt.Sprintf(`Field "%v" of type "%v" must have a sub selection.`, field, typ)
 }
 
-func ScalarLeafs(context *validation.Context) RuleVisitor {
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
+/**
+ * Scalar leafs
+ *
+ * A GraphQL document is valid only if all leaf fields (fields without
+ * sub selections) are of scalar or enum types.
+ */
+func ScalarLeafs(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			field, ok := node.(*lang.Field)
+			if !ok {
+				return nil
+			}
 			typ := context.GetType()
-			if typ {
-				if isLeafType(typ) {
-					if node.SelectionSet {
-						return lang.NewGraphQLError(
-							noSubselectionAllowedMessage(node.Name.Value, typ),
-							[]lang.INode{node.SelectionSet},
-						)
-					}
-				} else if !node.SecletionSet {
-					return lang.GraphQLError(
-						requiredSubselectionMessage(node.Name.Value, typ),
-						[]lang.INode{node},
-					)
+			if typ == nil {
+				return nil
+			}
+			if _, isLeaf := getNamedType(typ).(typs.QLLeafType); isLeaf {
+				if field.SelectionSet != nil {
+					return newError(
+						noSubselectionAllowedMessage(field.Name.Value, typ),
+						field.SelectionSet)
 				}
+			} else if field.SelectionSet == nil {
+				return newError(
+					requiredSubselectionMessage(field.Name.Value, typ),
+					field)
 			}
+			return nil
 		},
 	}
 }
@@ -0,0 +1,20 @@
+package rules
+
+import "testing"
+
+func TestScalarLeafs(T *testing.T) {
+	expectPassesRule(T, ScalarLeafs, `
+		{ dog { name barks isHousetrained(atOtherHomes: true) } }
+	`)
+	expectFailsRule(T, ScalarLeafs, `
+		{ dog }
+	`, `Field "dog" of type "Dog" must have a sub selection.`)
+	expectFailsRule(T, ScalarLeafs, `
+		{ human { pets } }
+	`, `Field "pets" of type "[Pet]" must have a sub selection.`)
+	expectFailsRule(T, ScalarLeafs, `
+		{ dog { barks { sinceWhen } name(surname: true) { x } } }
+	`,
+		`Field "barks" of type "Boolean" must not have a sub selection.`,
+		`Field "name" of type "String" must not have a sub selection.`)
+}
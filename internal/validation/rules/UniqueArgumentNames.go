@@ -1,31 +1,37 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
 
 func duplicateArgMessage(argName interface{}) string {
 	return fmt.Sprintf(`There can be only one argument named "%v".`, argName)
 }
 
-func UniqueArgumentNames() RuleVisitor {
-	knownArgNames := []lang.INode{}
-	return RuleVisitor{
-		Enter: func(node lang.INode, info *lang.VisitInfo) *lang.GraphGLError {
-			switch node.(type) {
-			case Field:
-				knownArgNames = []lang.INode{}
-			case Directive:
-				knownArgNames = []lang.INode{}
-			case Argument:
+/**
+ * Unique argument names
+ *
+ * A GraphQL field or directive is only valid if all supplied arguments are
+ * uniquely named.
+ */
+func UniqueArgumentNames(context *validation.Context) validation.RuleVisitor {
+	var knownArgNames map[string]*lang.Name
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			switch node := node.(type) {
+			case *lang.Field, *lang.Directive:
+				knownArgNames = make(map[string]*lang.Name)
+			case *lang.Argument:
 				argName := node.Name.Value
-				if knownArgNames[argName] {
-					return lang.GraphQLError{
-						duplicateArgMessage(argName),
-						[]lang.INode{knownArgNames[argName], node.Name},
-					}
+				if knownName, ok := knownArgNames[argName]; ok {
+					return newError(duplicateArgMessage(argName), knownName, node.Name)
 				}
-
 				knownArgNames[argName] = node.Name
 			}
+			return nil
 		},
 	}
 }
@@ -0,0 +1,22 @@
+package rules
+
+import "testing"
+
+func TestUniqueArgumentNames(T *testing.T) {
+	expectPassesRule(T, UniqueArgumentNames, `
+		{
+			dog { isHousetrained(atOtherHomes: true) name(surname: true) @skip(if: false) }
+			complicatedArgs { multipleReqs(req1: 1, req2: 2) }
+		}
+	`)
+	expectFailsRule(T, UniqueArgumentNames, `
+		{
+			dog {
+				isHousetrained(atOtherHomes: true, atOtherHomes: false)
+				name @skip(if: true, if: false)
+			}
+		}
+	`,
+		`There can be only one argument named "atOtherHomes".`,
+		`There can be only one argument named "if".`)
+}
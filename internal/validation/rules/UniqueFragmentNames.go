@@ -1,24 +1,33 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
 
 func duplicateFragmentNameMessage(fragName interface{}) string {
 	return fmt.Sprintf(`There can only be one fragment named "%v".`, fragName)
 }
 
-func UniqueFragmentNames() RuleVisitor {
-	knownFragmentNames := []lang.INode{}
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			fragmentName := node.Name.Value
-			if knownFragmentNames[fragmentName] {
-				return lang.NewGraphQLError{
-					duplicateFragmentNameMessage(fragName),
-					[]lang.INode{knownFragmentNames[fragmentName], node.Name},
+/**
+ * Unique fragment names
+ *
+ * A GraphQL document is only valid if all defined fragments have unique names.
+ */
+func UniqueFragmentNames(context *validation.Context) validation.RuleVisitor {
+	knownFragmentNames := make(map[string]*lang.Name)
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			if node, ok := node.(*lang.FragmentDefinition); ok {
+				fragmentName := node.Name.Value
+				if knownName, ok := knownFragmentNames[fragmentName]; ok {
+					return newError(duplicateFragmentNameMessage(fragmentName), knownName, node.Name)
 				}
+				knownFragmentNames[fragmentName] = node.Name
 			}
-
-			knownFragmentNames[fragmentName] = node.Name
+			return nil
 		},
 	}
 }
@@ -0,0 +1,16 @@
+package rules
+
+import "testing"
+
+func TestUniqueFragmentNames(T *testing.T) {
+	expectPassesRule(T, UniqueFragmentNames, `
+		{ dog { ...fragA ...fragB ... on Dog { name } } }
+		fragment fragA on Dog { name }
+		fragment fragB on Dog { nickname }
+	`)
+	expectFailsRule(T, UniqueFragmentNames, `
+		{ dog { ...fragA } }
+		fragment fragA on Dog { name }
+		fragment fragA on Dog { barks }
+	`, `There can only be one fragment named "fragA".`)
+}
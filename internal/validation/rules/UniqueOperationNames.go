@@ -1,27 +1,33 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
 
 func duplicateOperationNameMessage(operationName interface{}) string {
 	return fmt.Sprintf(`There can only be one operation named "%v".`, operationName)
 }
 
-func UniqueOperationNames() RuleVisitor {
-	knownOperationNames := lang.INode{}
-
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			operationName := node.Name
-			if operationName {
-				if knownOperationNames(operationName.Value) {
-					return lang.NewGraphQLError(
-						duplicateOperationNameMessage(operationName.Value),
-						[]lang.INode{knownOperationNames(operationName.Value), operationName},
-					)
+/**
+ * Unique operation names
+ *
+ * A GraphQL document is only valid if all defined operations have unique names.
+ */
+func UniqueOperationNames(context *validation.Context) validation.RuleVisitor {
+	knownOperationNames := make(map[string]*lang.Name)
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			if node, ok := node.(*lang.OperationDefinition); ok && node.Name != nil {
+				operationName := node.Name.Value
+				if knownName, ok := knownOperationNames[operationName]; ok {
+					return newError(duplicateOperationNameMessage(operationName), knownName, node.Name)
 				}
-
-				knownOperationNames[operationName.Value] = operationName
+				knownOperationNames[operationName] = node.Name
 			}
+			return nil
 		},
 	}
 }
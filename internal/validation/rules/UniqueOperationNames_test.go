@@ -0,0 +1,18 @@
+package rules
+
+import "testing"
+
+func TestUniqueOperationNames(T *testing.T) {
+	expectPassesRule(T, UniqueOperationNames, `
+		{ dog { name } }
+	`)
+	expectPassesRule(T, UniqueOperationNames, `
+		query Foo { dog { name } }
+		query Bar { dog { name } }
+		fragment Foo on Dog { name }
+	`)
+	expectFailsRule(T, UniqueOperationNames, `
+		query Foo { dog { name } }
+		mutation Foo { dog { name } }
+	`, `There can only be one operation named "Foo".`)
+}
@@ -1,23 +1,42 @@
 package rules
 
-import "fmt"
+import (
+	"fmt"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	typs "github.com/ng-vu/graphql-go/internal/types"
+	util "github.com/ng-vu/graphql-go/internal/utilities"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
 
 func nonInputTypeOnVarMessage(variableName, typeName interface{}) string {
 	return fmt.Sprintf(`Variable "$%v" cannot be non-input type "%v".`, variableName, typeName)
 }
 
-func VariablesAreInputTypes(context *validation.Context) RuleVisitor {
-	return RuleVisitor{
-		Enter: func(node lang.INode, info lang.VisitInfo) *lang.GraphQLError {
-			typ := typeFromAST(context.GetSchema(), node.Type)
-
-			if typ && !isInputType(typ) {
-				variableName := node.Variable.Name.Value
-				return lang.GraphQLError(
-					nonInputTypeOnVarMessage(variableName, printer.Print(typeName)),
-					[]lang.INode{node.Type},
-				)
+/**
+ * Variables are input types
+ *
+ * A GraphQL operation is only valid if all the variables it defines are of
+ * input types (scalar, enum, or input object).
+ */
+func VariablesAreInputTypes(context *validation.Context) validation.RuleVisitor {
+	return validation.RuleVisitor{
+		Enter: func(node lang.INode, info lang.VisitInfo) *lang.QLError {
+			varDefAST, ok := node.(*lang.VariableDefinition)
+			if !ok {
+				return nil
+			}
+			typ := util.TypeFromAST(context.GetSchema(), varDefAST.Type)
+			if typ == nil {
+				return nil
+			}
+			if _, isInput := getNamedType(typ).(typs.QLInputType); !isInput {
+				variableName := varDefAST.Variable.Name.Value
+				return newError(
+					nonInputTypeOnVarMessage(variableName, lang.Print(varDefAST.Type)),
+					varDefAST.Type)
 			}
+			return nil
 		},
 	}
 }
@@ -0,0 +1,19 @@
+package rules
+
+import "testing"
+
+func TestVariablesAreInputTypes(T *testing.T) {
+	expectPassesRule(T, VariablesAreInputTypes, `
+		query Foo($a: String, $b: [Boolean!]!, $c: ComplexInput, $d: DogCommand) {
+			dog { name }
+		}
+	`)
+	expectFailsRule(T, VariablesAreInputTypes, `
+		query Foo($a: Dog, $b: [[CatOrDog!]]!, $c: Pet) {
+			dog { name }
+		}
+	`,
+		`Variable "$a" cannot be non-input type "Dog".`,
+		`Variable "$b" cannot be non-input type "[[CatOrDog!]]!".`,
+		`Variable "$c" cannot be non-input type "Pet".`)
+}
@@ -0,0 +1,203 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	typs "github.com/ng-vu/graphql-go/internal/types"
+	"github.com/ng-vu/graphql-go/internal/validation"
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+var petConfig = ql.Interface{
+	Name: "Pet",
+	Fields: ql.FieldMap{
+		"name": {
+			Type: ql.String,
+			Args: ql.ArgumentMap{"surname": {Type: ql.Boolean}},
+		},
+	},
+}
+
+var dogCommandConfig = ql.Enum{
+	Name: "DogCommand",
+	Values: ql.EnumValueMap{
+		"SIT":  {Value: 0},
+		"HEEL": {Value: 1},
+		"DOWN": {Value: 2},
+	},
+}
+
+var dogConfig, catConfig, humanConfig ql.Object
+
+var complexInputConfig = ql.InputObject{
+	Name: "ComplexInput",
+	Fields: ql.InputObjectFieldMap{
+		"requiredField":   {Type: ql.NonNull{OfType: ql.Boolean}},
+		"intField":        {Type: ql.Int},
+		"stringField":     {Type: ql.String},
+		"stringListField": {Type: ql.List{OfType: ql.String}},
+	},
+}
+
+var complicatedArgsConfig = ql.Object{
+	Name: "ComplicatedArgs",
+	Fields: ql.FieldMap{
+		"intArgField": {
+			Type: ql.String,
+			Args: ql.ArgumentMap{"intArg": {Type: ql.Int}},
+		},
+		"nonNullIntArgField": {
+			Type: ql.String,
+			Args: ql.ArgumentMap{"nonNullIntArg": {Type: ql.NonNull{OfType: ql.Int}}},
+		},
+		"stringArgField": {
+			Type: ql.String,
+			Args: ql.ArgumentMap{"stringArg": {Type: ql.String}},
+		},
+		"booleanArgField": {
+			Type: ql.String,
+			Args: ql.ArgumentMap{"booleanArg": {Type: ql.Boolean}},
+		},
+		"enumArgField": {
+			Type: ql.String,
+			Args: ql.ArgumentMap{"enumArg": {Type: dogCommandConfig}},
+		},
+		"idArgField": {
+			Type: ql.String,
+			Args: ql.ArgumentMap{"idArg": {Type: ql.ID}},
+		},
+		"stringListArgField": {
+			Type: ql.String,
+			Args: ql.ArgumentMap{"stringListArg": {Type: ql.List{OfType: ql.String}}},
+		},
+		"complexArgField": {
+			Type: ql.String,
+			Args: ql.ArgumentMap{"complexArg": {Type: complexInputConfig}},
+		},
+		"multipleReqs": {
+			Type: ql.String,
+			Args: ql.ArgumentMap{
+				"req1": {Type: ql.NonNull{OfType: ql.Int}},
+				"req2": {Type: ql.NonNull{OfType: ql.Int}},
+			},
+		},
+	},
+}
+
+var testSchema typs.QLSchema
+
+func init() {
+	dogConfig = ql.Object{
+		Name:       "Dog",
+		Interfaces: ql.Interfaces{petConfig},
+		FieldsFunc: func() ql.FieldMap {
+			return ql.FieldMap{
+				"name": {
+					Type: ql.String,
+					Args: ql.ArgumentMap{"surname": {Type: ql.Boolean}},
+				},
+				"nickname": {Type: ql.String},
+				"barks":    {Type: ql.Boolean},
+				"doesKnowCommand": {
+					Type: ql.Boolean,
+					Args: ql.ArgumentMap{"dogCommand": {Type: dogCommandConfig}},
+				},
+				"isHousetrained": {
+					Type: ql.Boolean,
+					Args: ql.ArgumentMap{"atOtherHomes": {Type: ql.Boolean, DefaultValue: true}},
+				},
+				"owner": {Type: humanConfig},
+			}
+		},
+		IsTypeOf: func(v interface{}, info interface{}) bool { return false },
+	}
+	catConfig = ql.Object{
+		Name:       "Cat",
+		Interfaces: ql.Interfaces{petConfig},
+		Fields: ql.FieldMap{
+			"name": {
+				Type: ql.String,
+				Args: ql.ArgumentMap{"surname": {Type: ql.Boolean}},
+			},
+			"meowVolume": {Type: ql.Int},
+		},
+		IsTypeOf: func(v interface{}, info interface{}) bool { return false },
+	}
+	humanConfig = ql.Object{
+		Name: "Human",
+		FieldsFunc: func() ql.FieldMap {
+			return ql.FieldMap{
+				"name": {
+					Type: ql.String,
+					Args: ql.ArgumentMap{"surname": {Type: ql.Boolean}},
+				},
+				"pets":      {Type: ql.List{OfType: petConfig}},
+				"relatives": {Type: ql.List{OfType: humanConfig}},
+			}
+		},
+		IsTypeOf: func(v interface{}, info interface{}) bool { return false },
+	}
+
+	testSchema = typs.NewQLSchema(ql.Object{
+		Name: "QueryRoot",
+		FieldsFunc: func() ql.FieldMap {
+			return ql.FieldMap{
+				"human": {
+					Type: humanConfig,
+					Args: ql.ArgumentMap{"id": {Type: ql.ID}},
+				},
+				"dog": {Type: dogConfig},
+				"cat": {Type: catConfig},
+				"pet": {Type: petConfig},
+				"catOrDog": {Type: ql.Union{
+					Name:  "CatOrDog",
+					Types: ql.Objects{catConfig, dogConfig},
+				}},
+				"complicatedArgs": {Type: complicatedArgsConfig},
+			}
+		},
+	}, nil, ql.Directive{
+		Name:      "onArgumentDefinition",
+		Locations: []ql.DirectiveLocation{ql.LocationArgumentDefinition},
+	}, ql.Directive{
+		Name:      "onInputFieldDefinition",
+		Locations: []ql.DirectiveLocation{ql.LocationInputFieldDefinition},
+	})
+}
+
+func validate(T *testing.T, rules []validation.RuleCreator, query string) []lang.QLError {
+	documentAST, err := lang.Parse(lang.NewSource(query, ""))
+	if err != nil {
+		T.Fatal(err)
+	}
+	return validation.Validate(testSchema, documentAST, rules)
+}
+
+func expectPassesRule(T *testing.T, rule validation.RuleCreator, query string) {
+	errs := validate(T, []validation.RuleCreator{rule}, query)
+	if len(errs) != 0 {
+		T.Errorf("Expect query to pass:\n%v\nbut got:\n%v", query, errorMessages(errs))
+	}
+}
+
+/**
+ * Expects the rule to report exactly the given messages, in order.
+ */
+func expectFailsRule(T *testing.T, rule validation.RuleCreator, query string, messages ...string) {
+	errs := validate(T, []validation.RuleCreator{rule}, query)
+	actual := errorMessages(errs)
+	if strings.Join(actual, "\n") != strings.Join(messages, "\n") {
+		T.Errorf("Expect query to fail:\n%v\nwith:\n%v\nbut got:\n%v",
+			query, strings.Join(messages, "\n"), strings.Join(actual, "\n"))
+	}
+}
+
+func errorMessages(errs []lang.QLError) []string {
+	result := make([]string, len(errs))
+	for i, err := range errs {
+		result[i] = err.Message
+	}
+	return result
+}
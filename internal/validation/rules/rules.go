@@ -1,8 +1,99 @@
 package rules
 
-import "github.com/ng-vu/graphql-go/validation"
+import (
+	lang "github.com/ng-vu/graphql-go/internal/language"
+	typs "github.com/ng-vu/graphql-go/internal/types"
+	"github.com/ng-vu/graphql-go/internal/validation"
+)
 
-var Rules = []func(*validation.Context) validation.RuleVisitor{
+/**
+ * This set includes all validation rules defined by the GraphQL spec.
+ */
+var Rules = []validation.RuleCreator{
+	UniqueOperationNames,
+	LoneAnonymousOperation,
+	KnownTypeNames,
+	FragmentsOnCompositeTypes,
+	VariablesAreInputTypes,
+	ScalarLeafs,
+	FieldsOnCorrectType,
+	UniqueFragmentNames,
+	KnownFragmentNames,
+	NoUnusedFragments,
+	NoFragmentCycles,
+	NoUndefinedVariables,
+	NoUnusedVariables,
+	KnownDirectives,
+	KnownArgumentNames,
+	UniqueArgumentNames,
 	ArgumentsOfCorrectType,
 	DefaultValuesOfCorrectType,
 }
+
+func newError(message string, nodes ...lang.INode) *lang.QLError {
+	err := lang.NewQLError(message, nodes)
+	return &err
+}
+
+func getNamedType(typ typs.QLType) typs.QLType {
+	for {
+		switch t := typ.(type) {
+		case *typs.QLList:
+			typ = t.OfType
+		case *typs.QLNonNull:
+			typ = t.OfType
+		default:
+			return typ
+		}
+	}
+}
+
+/**
+ * Calls fn for each fragment spread in the selection set, including the ones
+ * nested in fields and inline fragments, but not following the spreads.
+ */
+func eachFragmentSpread(selectionSet *lang.SelectionSet, fn func(*lang.FragmentSpread)) {
+	lang.Visit(selectionSet, lang.VisitorFunc{
+		EnterFunc: func(node lang.INode, info lang.VisitInfo) lang.VisitAction {
+			if node, ok := node.(*lang.FragmentSpread); ok {
+				fn(node)
+			}
+			return nil
+		},
+	}, nil)
+}
+
+/**
+ * Calls fn for each variable used in the selection set, then in each fragment
+ * transitively spread from it. A fragment is visited only once.
+ */
+func eachVariableUsage(
+	context *validation.Context,
+	selectionSet *lang.SelectionSet,
+	fn func(*lang.Variable),
+) {
+
+	visitedFragmentNames := make(map[string]struct{})
+	var visit func(*lang.SelectionSet)
+	visit = func(selectionSet *lang.SelectionSet) {
+		lang.Visit(selectionSet, lang.VisitorFunc{
+			EnterFunc: func(node lang.INode, info lang.VisitInfo) lang.VisitAction {
+				switch node := node.(type) {
+				case *lang.Variable:
+					fn(node)
+				case *lang.FragmentSpread:
+					name := node.Name.Value
+					if _, visited := visitedFragmentNames[name]; visited {
+						return nil
+					}
+					visitedFragmentNames[name] = struct{}{}
+					if fragment := context.GetFragment(name); fragment != nil {
+						visit(fragment.SelectionSet)
+					}
+				}
+				return nil
+			},
+		}, nil)
+	}
+	visit(selectionSet)
+}
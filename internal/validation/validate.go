@@ -13,21 +13,66 @@ type RuleVisitor struct {
 
 type RuleCreator func(*Context) RuleVisitor
 
+/**
+ * Implements the "Validation" section of the spec.
+ *
+ * Validation runs synchronously, returning an array of encountered errors, or
+ * an empty array if no errors were encountered and the document is valid.
+ *
+ * Each validation rule is a function which returns a visitor which visits the
+ * document. All rules visit the document in parallel, in the order they are
+ * given, and a rule reports an error by returning it from its visitor.
+ */
 func Validate(
 	schema typs.QLSchema,
 	ast *lang.Document,
 	rules []RuleCreator) []lang.QLError {
-	return nil
+
+	typeInfo := util.NewTypeInfo(schema)
+	context := NewContext(schema, *ast, typeInfo)
+	visitors := make([]RuleVisitor, len(rules))
+	for i, rule := range rules {
+		visitors[i] = rule(context)
+	}
+
+	var errors []lang.QLError
+	lang.Visit(ast, lang.VisitorFunc{
+		EnterFunc: func(node lang.INode, info lang.VisitInfo) lang.VisitAction {
+			typeInfo.Enter(node)
+			for _, visitor := range visitors {
+				if visitor.Enter == nil {
+					continue
+				}
+				if err := visitor.Enter(node, info); err != nil {
+					errors = append(errors, *err)
+				}
+			}
+			return nil
+		},
+		LeaveFunc: func(node lang.INode, info lang.VisitInfo) lang.VisitAction {
+			for _, visitor := range visitors {
+				if visitor.Leave == nil {
+					continue
+				}
+				if err := visitor.Leave(node, info); err != nil {
+					errors = append(errors, *err)
+				}
+			}
+			typeInfo.Leave(node)
+			return nil
+		},
+	}, nil)
+	return errors
 }
 
 type Context struct {
 	schema    typs.QLSchema
 	ast       lang.Document
-	typeInfo  util.TypeInfo
+	typeInfo  *util.TypeInfo
 	fragments map[string]*lang.FragmentDefinition
 }
 
-func NewContext(schema typs.QLSchema, ast lang.Document, typeInfo util.TypeInfo) *Context {
+func NewContext(schema typs.QLSchema, ast lang.Document, typeInfo *util.TypeInfo) *Context {
 	return &Context{
 		schema:   schema,
 		ast:      ast,
@@ -35,18 +80,18 @@ func NewContext(schema typs.QLSchema, ast lang.Document, typeInfo util.TypeInfo)
 	}
 }
 
-func (v Context) GetSchema() typs.QLSchema {
+func (v *Context) GetSchema() typs.QLSchema {
 	return v.schema
 }
 
-func (v Context) GetDocument() lang.Document {
+func (v *Context) GetDocument() lang.Document {
 	return v.ast
 }
 
-func (v Context) GetFragment(name string) *lang.FragmentDefinition {
+func (v *Context) GetFragment(name string) *lang.FragmentDefinition {
 	fragments := v.fragments
 	if fragments == nil {
-		fragments := make(map[string]*lang.FragmentDefinition)
+		fragments = make(map[string]*lang.FragmentDefinition)
 		for _, statement := range v.GetDocument().Definitions {
 			if statement, ok := statement.(*lang.FragmentDefinition); ok {
 				fragments[statement.Name.Value] = statement
@@ -61,26 +106,26 @@ func (v Context) GetFragment(name string) *lang.FragmentDefinition {
 	return nil
 }
 
-func (v Context) GetType() typs.QLOutputType {
+func (v *Context) GetType() typs.QLOutputType {
 	return v.typeInfo.GetType()
 }
 
-func (v Context) GetParentType() typs.QLCompositeType {
+func (v *Context) GetParentType() typs.QLCompositeType {
 	return v.typeInfo.GetParentType()
 }
 
-func (v Context) GetInputType() typs.QLInputType {
+func (v *Context) GetInputType() typs.QLInputType {
 	return v.typeInfo.GetInputType()
 }
 
-func (v Context) GetFieldDef() *typs.QLFieldDefinition {
+func (v *Context) GetFieldDef() *typs.QLFieldDefinition {
 	return v.typeInfo.GetFieldDef()
 }
 
-func (v Context) GetDirective() *typs.QLDirective {
+func (v *Context) GetDirective() *typs.QLDirective {
 	return v.typeInfo.GetDirective()
 }
 
-func (v Context) GetArgument() *typs.QLArgument {
+func (v *Context) GetArgument() *typs.QLArgument {
 	return v.typeInfo.GetArgument()
 }
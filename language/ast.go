@@ -0,0 +1,171 @@
+package language
+
+import (
+	"github.com/ng-vu/graphql-go/internal/language"
+)
+
+type (
+	Location = language.Location
+	Comments = language.Comments
+	INode    = language.INode
+	Name     = language.Name
+
+	// Document
+
+	Document            = language.Document
+	IDefinition         = language.IDefinition
+	OperationType       = language.OperationType
+	OperationDefinition = language.OperationDefinition
+	VariableDefinition  = language.VariableDefinition
+	Variable            = language.Variable
+	SelectionSet        = language.SelectionSet
+	ISelection          = language.ISelection
+	Field               = language.Field
+	Argument            = language.Argument
+
+	// Fragments
+
+	IFragment          = language.IFragment
+	FragmentSpread     = language.FragmentSpread
+	ITypeCondition     = language.ITypeCondition
+	InlineFragment     = language.InlineFragment
+	FragmentDefinition = language.FragmentDefinition
+
+	// Values
+
+	IValue       = language.IValue
+	IScalarValue = language.IScalarValue
+	IntValue     = language.IntValue
+	FloatValue   = language.FloatValue
+	StringValue  = language.StringValue
+	BooleanValue = language.BooleanValue
+	NullValue    = language.NullValue
+	EnumValue    = language.EnumValue
+	ListValue    = language.ListValue
+	ObjectValue  = language.ObjectValue
+	ObjectField  = language.ObjectField
+
+	// Directives
+
+	Directive = language.Directive
+
+	// Types
+
+	IType        = language.IType
+	INonNullType = language.INonNullType
+	NamedType    = language.NamedType
+	ListType     = language.ListType
+	NonNullType  = language.NonNullType
+
+	// Type System Definitions
+
+	SchemaDefinition          = language.SchemaDefinition
+	OperationTypeDefinition   = language.OperationTypeDefinition
+	DirectiveDefinition       = language.DirectiveDefinition
+	SchemaExtension           = language.SchemaExtension
+	ITypeDefinition           = language.ITypeDefinition
+	ObjectTypeDefinition      = language.ObjectTypeDefinition
+	FieldDefinition           = language.FieldDefinition
+	InputValueDefinition      = language.InputValueDefinition
+	InterfaceTypeDefinition   = language.InterfaceTypeDefinition
+	UnionTypeDefinition       = language.UnionTypeDefinition
+	ScalarTypeDefinition      = language.ScalarTypeDefinition
+	EnumTypeDefinition        = language.EnumTypeDefinition
+	EnumValueDefinition       = language.EnumValueDefinition
+	InputObjectTypeDefinition = language.InputObjectTypeDefinition
+	TypeExtensionDefinition   = language.TypeExtensionDefinition
+
+	// Type Extensions
+
+	ITypeExtension           = language.ITypeExtension
+	ScalarTypeExtension      = language.ScalarTypeExtension
+	InterfaceTypeExtension   = language.InterfaceTypeExtension
+	UnionTypeExtension       = language.UnionTypeExtension
+	EnumTypeExtension        = language.EnumTypeExtension
+	InputObjectTypeExtension = language.InputObjectTypeExtension
+)
+
+const (
+	OperationQuery        = language.OperationQuery
+	OperationMutation     = language.OperationMutation
+	OperationSubscription = language.OperationSubscription
+)
+
+type NodeKind = language.NodeKind
+
+const (
+
+	// Name
+
+	NAME = language.NAME
+
+	// Document
+
+	DOCUMENT             = language.DOCUMENT
+	OPERATION_DEFINITION = language.OPERATION_DEFINITION
+	VARIABLE_DEFINITION  = language.VARIABLE_DEFINITION
+	VARIABLE             = language.VARIABLE
+	SELECTION_SET        = language.SELECTION_SET
+	FIELD                = language.FIELD
+	ARGUMENT             = language.ARGUMENT
+
+	// Fragments
+
+	FRAGMENT_SPREAD     = language.FRAGMENT_SPREAD
+	INLINE_FRAGMENT     = language.INLINE_FRAGMENT
+	FRAGMENT_DEFINITION = language.FRAGMENT_DEFINITION
+
+	// Values
+
+	INT_VALUE     = language.INT_VALUE
+	FLOAT_VALUE   = language.FLOAT_VALUE
+	STRING_VALUE  = language.STRING_VALUE
+	BOOLEAN_VALUE = language.BOOLEAN_VALUE
+	NULL_VALUE    = language.NULL_VALUE
+	ENUM_VALUE    = language.ENUM_VALUE
+	LIST_VALUE    = language.LIST_VALUE
+	OBJECT_VALUE  = language.OBJECT_VALUE
+	OBJECT_FIELD  = language.OBJECT_FIELD
+
+	// Directives
+
+	DIRECTIVE = language.DIRECTIVE
+
+	// Types
+
+	NAMED_TYPE    = language.NAMED_TYPE
+	LIST_TYPE     = language.LIST_TYPE
+	NON_NULL_TYPE = language.NON_NULL_TYPE
+
+	// IType Definitions
+
+	OBJECT_TYPE_DEFINITION       = language.OBJECT_TYPE_DEFINITION
+	FIELD_DEFINITION             = language.FIELD_DEFINITION
+	INPUT_VALUE_DEFINITION       = language.INPUT_VALUE_DEFINITION
+	INTERFACE_TYPE_DEFINITION    = language.INTERFACE_TYPE_DEFINITION
+	UNION_TYPE_DEFINITION        = language.UNION_TYPE_DEFINITION
+	SCALAR_TYPE_DEFINITION       = language.SCALAR_TYPE_DEFINITION
+	ENUM_TYPE_DEFINITION         = language.ENUM_TYPE_DEFINITION
+	ENUM_VALUE_DEFINITION        = language.ENUM_VALUE_DEFINITION
+	INPUT_OBJECT_TYPE_DEFINITION = language.INPUT_OBJECT_TYPE_DEFINITION
+	TYPE_EXTENSION_DEFINITION    = language.TYPE_EXTENSION_DEFINITION
+
+	// Type System Definitions
+
+	SCHEMA_DEFINITION         = language.SCHEMA_DEFINITION
+	OPERATION_TYPE_DEFINITION = language.OPERATION_TYPE_DEFINITION
+	DIRECTIVE_DEFINITION      = language.DIRECTIVE_DEFINITION
+	SCHEMA_EXTENSION          = language.SCHEMA_EXTENSION
+
+	// Type Extensions
+
+	// OBJECT_TYPE_EXTENSION is the kind of TypeExtensionDefinition in the
+	// graphql-js JSON of the AST.
+	OBJECT_TYPE_EXTENSION = language.OBJECT_TYPE_EXTENSION
+
+	SCALAR_TYPE_EXTENSION       = language.SCALAR_TYPE_EXTENSION
+	INTERFACE_TYPE_EXTENSION    = language.INTERFACE_TYPE_EXTENSION
+	UNION_TYPE_EXTENSION        = language.UNION_TYPE_EXTENSION
+	ENUM_TYPE_EXTENSION         = language.ENUM_TYPE_EXTENSION
+	INPUT_OBJECT_TYPE_EXTENSION = language.INPUT_OBJECT_TYPE_EXTENSION
+)
@@ -0,0 +1,22 @@
+package language
+
+import (
+	"github.com/ng-vu/graphql-go/internal/language"
+)
+
+/**
+ * Returns the graphql-js JSON of the node, with its kind, its fields and the
+ * start and end of its location. A Document is marshaled the same way by
+ * encoding/json.
+ */
+func MarshalNode(node INode) ([]byte, error) {
+	return language.MarshalNode(node)
+}
+
+/**
+ * Returns the node of the graphql-js JSON. A Document is unmarshaled the same
+ * way by encoding/json.
+ */
+func UnmarshalNode(data []byte) (INode, error) {
+	return language.UnmarshalNode(data)
+}
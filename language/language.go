@@ -0,0 +1,64 @@
+// Package language parses, visits and prints GraphQL documents.
+//
+// It exposes the tokenizer, parser, AST, visitor and printer used by the
+// graphql package, so that tools such as linters, code generators and syntax
+// highlighters can work on the same documents. The types are aliases of the
+// ones used by the graphql package.
+package language
+
+import (
+	"github.com/ng-vu/graphql-go/internal/language"
+)
+
+type (
+	Source = language.Source
+
+	ParseOptions = language.ParseOptions
+	PrintOptions = language.PrintOptions
+
+	// Error is the type of the errors returned by the parse functions. Its
+	// Locations give the line and column of syntax errors.
+	Error          = language.QLError
+	SourceLocation = language.SourceLocation
+)
+
+func NewSource(body string, name string) Source {
+	return language.NewSource(body, name)
+}
+
+/**
+ * Parses the source as an executable or type system document. The error is
+ * an Error when the source is invalid.
+ */
+func Parse(source Source, options ...ParseOptions) (*Document, error) {
+	return language.Parse(source, options...)
+}
+
+/**
+ * Parses the source like Parse, but reports every syntax error along with
+ * the partial document instead of stopping at the first one.
+ */
+func ParseWithRecovery(source Source, options ...ParseOptions) (*Document, []error) {
+	return language.ParseWithRecovery(source, options...)
+}
+
+/**
+ * Parses the source as a value, such as `{ a: [1, $b] }`.
+ */
+func ParseValue(source Source, options ...ParseOptions) (IValue, error) {
+	return language.ParseValue(source, options...)
+}
+
+/**
+ * Parses the source as a type reference, such as `[String!]!`.
+ */
+func ParseType(source Source, options ...ParseOptions) (IType, error) {
+	return language.ParseType(source, options...)
+}
+
+/**
+ * Prints the node as GraphQL source.
+ */
+func Print(node INode, options ...PrintOptions) string {
+	return language.Print(node, options...)
+}
@@ -0,0 +1,67 @@
+package language
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLanguage_ParseVisitPrint(T *testing.T) {
+	source := "query Q($id: ID) {\n    user(id: $id) {\n        name\n        friends {\n            name\n        }\n    }\n    viewer\n}\n"
+	document, err := Parse(NewSource(source, ""))
+	if err != nil {
+		T.Fatal(err)
+	}
+	if printed := Print(document); printed != source {
+		T.Errorf("Expect printing to give back the source but got:\n%v---", printed)
+	}
+
+	// The fields are visited in order, and VISIT_SKIP leaves out the children
+	// of friends while VISIT_BREAK stops at viewer
+	var entered, left []string
+	Visit(document, VisitorFunc{
+		EnterFunc: func(node INode, info VisitInfo) VisitAction {
+			field, ok := node.(*Field)
+			if !ok {
+				return nil
+			}
+			entered = append(entered, field.Name.Value)
+			switch field.Name.Value {
+			case "friends":
+				return VISIT_SKIP()
+			case "viewer":
+				return VISIT_BREAK()
+			}
+			return nil
+		},
+		LeaveFunc: func(node INode, info VisitInfo) VisitAction {
+			if field, ok := node.(*Field); ok {
+				left = append(left, field.Name.Value)
+			}
+			return nil
+		},
+	}, nil)
+	if expected := []string{"user", "name", "friends", "viewer"}; !reflect.DeepEqual(entered, expected) {
+		T.Errorf("Expect to enter %v but got %v", expected, entered)
+	}
+	if expected := []string{"name", "user"}; !reflect.DeepEqual(left, expected) {
+		T.Errorf("Expect to leave %v but got %v", expected, left)
+	}
+}
+
+func TestLanguage_ReportsSyntaxErrors(T *testing.T) {
+	_, err := Parse(NewSource("{ a(", ""))
+	if _, ok := err.(Error); !ok {
+		T.Fatalf("Expect an Error but got %#v", err)
+	}
+	if locations := err.(Error).Locations; !reflect.DeepEqual(locations, []SourceLocation{{Line: 1, Column: 5}}) {
+		T.Errorf("Unexpected locations: %v", locations)
+	}
+
+	document, errs := ParseWithRecovery(NewSource("{ a( } { b }", ""))
+	if len(errs) != 1 || document == nil {
+		T.Fatalf("Expect one error and a document but got %v", errs)
+	}
+	if printed := Print(document); printed != "{\n    b\n}\n" {
+		T.Errorf("Unexpected recovered document:\n%v---", printed)
+	}
+}
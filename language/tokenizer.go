@@ -0,0 +1,55 @@
+package language
+
+import (
+	"github.com/ng-vu/graphql-go/internal/language"
+)
+
+type (
+	Token       = language.Token
+	TokenKind   = language.TokenKind
+	SourceToken = language.SourceToken
+
+	// Tokenizer reads the tokens of a source one at a time, including the
+	// comments. Invalid text is returned as a TOKEN_INVALID token along with
+	// its syntax error, and reading resumes after it, so that incomplete
+	// sources can be read.
+	Tokenizer = language.Tokenizer
+)
+
+const (
+	TOKEN_EOF          = language.TOKEN_EOF
+	TOKEN_BANG         = language.TOKEN_BANG
+	TOKEN_DOLLAR       = language.TOKEN_DOLLAR
+	TOKEN_PAREN_L      = language.TOKEN_PAREN_L
+	TOKEN_PAREN_R      = language.TOKEN_PAREN_R
+	TOKEN_SPREAD       = language.TOKEN_SPREAD
+	TOKEN_COLON        = language.TOKEN_COLON
+	TOKEN_EQUALS       = language.TOKEN_EQUALS
+	TOKEN_AT           = language.TOKEN_AT
+	TOKEN_BRACKET_L    = language.TOKEN_BRACKET_L
+	TOKEN_BRACKET_R    = language.TOKEN_BRACKET_R
+	TOKEN_BRACE_L      = language.TOKEN_BRACE_L
+	TOKEN_PIPE         = language.TOKEN_PIPE
+	TOKEN_BRACE_R      = language.TOKEN_BRACE_R
+	TOKEN_NAME         = language.TOKEN_NAME
+	TOKEN_VARIABLE     = language.TOKEN_VARIABLE
+	TOKEN_INT          = language.TOKEN_INT
+	TOKEN_FLOAT        = language.TOKEN_FLOAT
+	TOKEN_STRING       = language.TOKEN_STRING
+	TOKEN_AMP          = language.TOKEN_AMP
+	TOKEN_BLOCK_STRING = language.TOKEN_BLOCK_STRING
+	TOKEN_COMMENT      = language.TOKEN_COMMENT
+	TOKEN_INVALID      = language.TOKEN_INVALID
+)
+
+func NewTokenizer(source Source) *Tokenizer {
+	return language.NewTokenizer(source)
+}
+
+/**
+ * Returns all the tokens of the source, without the final TOKEN_EOF, and the
+ * errors of its invalid tokens.
+ */
+func Tokenize(source Source) ([]SourceToken, []error) {
+	return language.Tokenize(source)
+}
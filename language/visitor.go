@@ -0,0 +1,38 @@
+package language
+
+import (
+	"github.com/ng-vu/graphql-go/internal/language"
+)
+
+type (
+	Visitor     = language.Visitor
+	VisitorFunc = language.VisitorFunc
+	VisitInfo   = language.VisitInfo
+	VisitAction = language.VisitAction
+	QueryKeyMap = language.QueryKeyMap
+)
+
+/**
+ * Returns the action which stops the traversal.
+ */
+func VISIT_BREAK() VisitAction {
+	return language.VISIT_BREAK
+}
+
+/**
+ * Returns the action which, returned from Enter, skips the children of the
+ * node and its Leave call.
+ */
+func VISIT_SKIP() VisitAction {
+	return language.VISIT_SKIP
+}
+
+/**
+ * Visits the tree rooted at the given node in depth-first order, calling
+ * Enter on the way down and Leave on the way up. The children of each node
+ * are found with keyMap, which defaults to all the children of the node.
+ * Returning nil from Enter or Leave continues the traversal.
+ */
+func Visit(root INode, visitor Visitor, keyMap *QueryKeyMap) {
+	language.Visit(root, visitor, keyMap)
+}
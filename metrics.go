@@ -0,0 +1,276 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+/**
+ * The metrics reported to Metrics.
+ */
+const (
+	// Counts the executed operations, labeled by "operation": query or
+	// mutation.
+	MetricOperations = "graphql_operations_total"
+
+	// Counts the requests rejected by validation.
+	MetricValidationFailures = "graphql_validation_failures_total"
+
+	// Counts the errors returned by the resolvers, labeled by "field":
+	// Type.field.
+	MetricResolverErrors = "graphql_resolver_errors_total"
+
+	// The time spent in the resolvers in seconds, labeled by "field":
+	// Type.field.
+	MetricResolverDuration = "graphql_resolver_duration_seconds"
+)
+
+/**
+ * Metrics receives the counters and histograms of the requests. It is called
+ * concurrently while resolving fields.
+ */
+type Metrics interface {
+	IncCounter(name string, labels map[string]string)
+	ObserveHistogram(name string, value float64, labels map[string]string)
+}
+
+/**
+ * The middleware recording the latency and errors of the resolvers.
+ */
+func metricsMiddleware(metrics Metrics) ql.Middleware {
+	return func(
+		ctx context.Context,
+		info ql.ResolveInfo,
+		next func(ctx context.Context) (interface{}, error),
+	) (interface{}, error) {
+		start := time.Now()
+		result, err := next(ctx)
+		labels := map[string]string{"field": info.ParentType + "." + info.FieldName}
+		metrics.ObserveHistogram(MetricResolverDuration, time.Since(start).Seconds(), labels)
+		if err != nil {
+			metrics.IncCounter(MetricResolverErrors, labels)
+		}
+		return result, err
+	}
+}
+
+/**
+ * The upper bounds of the histogram buckets of MemoryMetrics when none are
+ * given, in seconds.
+ */
+var DefaultBuckets = []float64{.0001, .0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+/**
+ * Metrics kept in memory, which can be written in the Prometheus text format
+ * to be scraped.
+ */
+type MemoryMetrics struct {
+	buckets []float64
+
+	m          sync.Mutex
+	counters   map[string]map[string]*_Counter
+	histograms map[string]map[string]*_Histogram
+}
+
+type _Counter struct {
+	labels map[string]string
+	value  uint64
+}
+
+type _Histogram struct {
+	labels map[string]string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func NewMemoryMetrics(buckets ...float64) *MemoryMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &MemoryMetrics{
+		buckets:    buckets,
+		counters:   make(map[string]map[string]*_Counter),
+		histograms: make(map[string]map[string]*_Histogram),
+	}
+}
+
+func (m *MemoryMetrics) IncCounter(name string, labels map[string]string) {
+	key := formatLabels(labels, "")
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	series := m.counters[name]
+	if series == nil {
+		series = make(map[string]*_Counter)
+		m.counters[name] = series
+	}
+	counter := series[key]
+	if counter == nil {
+		counter = &_Counter{labels: copyLabels(labels)}
+		series[key] = counter
+	}
+	counter.value++
+}
+
+func (m *MemoryMetrics) ObserveHistogram(name string, value float64, labels map[string]string) {
+	key := formatLabels(labels, "")
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	series := m.histograms[name]
+	if series == nil {
+		series = make(map[string]*_Histogram)
+		m.histograms[name] = series
+	}
+	histogram := series[key]
+	if histogram == nil {
+		histogram = &_Histogram{
+			labels: copyLabels(labels),
+			counts: make([]uint64, len(m.buckets)),
+		}
+		series[key] = histogram
+	}
+	for i, bound := range m.buckets {
+		if value <= bound {
+			histogram.counts[i]++
+		}
+	}
+	histogram.count++
+	histogram.sum += value
+}
+
+/**
+ * Returns the value of a counter, or 0 if it was never incremented.
+ */
+func (m *MemoryMetrics) Counter(name string, labels map[string]string) uint64 {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if counter := m.counters[name][formatLabels(labels, "")]; counter != nil {
+		return counter.value
+	}
+	return 0
+}
+
+/**
+ * Returns the number and the sum of the values observed by a histogram.
+ */
+func (m *MemoryMetrics) Histogram(name string, labels map[string]string) (count uint64, sum float64) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if histogram := m.histograms[name][formatLabels(labels, "")]; histogram != nil {
+		return histogram.count, histogram.sum
+	}
+	return 0, 0
+}
+
+/**
+ * Writes the metrics in the Prometheus text exposition format, sorted by name
+ * and labels.
+ */
+func (m *MemoryMetrics) WritePrometheus(w io.Writer) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	var b strings.Builder
+	names := make([]string, 0, len(m.counters))
+	for name := range m.counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "# TYPE %v counter\n", name)
+		series := m.counters[name]
+		keys := make([]string, 0, len(series))
+		for key := range series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&b, "%v%v %v\n", name, key, series[key].value)
+		}
+	}
+	names = names[:0]
+	for name := range m.histograms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "# TYPE %v histogram\n", name)
+		series := m.histograms[name]
+		keys := make([]string, 0, len(series))
+		for key := range series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			histogram := series[key]
+			for i, bound := range m.buckets {
+				le := formatLabels(histogram.labels, formatFloat(bound))
+				fmt.Fprintf(&b, "%v_bucket%v %v\n", name, le, histogram.counts[i])
+			}
+			le := formatLabels(histogram.labels, "+Inf")
+			fmt.Fprintf(&b, "%v_bucket%v %v\n", name, le, histogram.count)
+			fmt.Fprintf(&b, "%v_sum%v %v\n", name, key, formatFloat(histogram.sum))
+			fmt.Fprintf(&b, "%v_count%v %v\n", name, key, histogram.count)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+/**
+ * Formats the labels as {a="1",b="2"} sorted by name, with the "le" label of
+ * histogram buckets last when given. Returns "" when there are no labels.
+ */
+func formatLabels(labels map[string]string, le string) string {
+	if len(labels) == 0 && le == "" {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(labels)+1)
+	for _, name := range names {
+		parts = append(parts, name+`="`+labelEscaper.Replace(labels[name])+`"`)
+	}
+	if le != "" {
+		parts = append(parts, `le="`+le+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels))
+	for name, value := range labels {
+		result[name] = value
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMemoryMetrics_Record(T *testing.T) {
+	metrics := NewMemoryMetrics(1, 0.1)
+	labels := map[string]string{"field": "Query.a"}
+	metrics.IncCounter("c", labels)
+	metrics.IncCounter("c", map[string]string{"field": "Query.a"})
+	metrics.IncCounter("c", nil)
+	metrics.ObserveHistogram("h", 0.05, labels)
+	metrics.ObserveHistogram("h", 0.5, labels)
+	metrics.ObserveHistogram("h", 2, labels)
+
+	// The labels are copied
+	labels["field"] = "changed"
+
+	if n := metrics.Counter("c", map[string]string{"field": "Query.a"}); n != 2 {
+		T.Errorf("Expect counter to be 2 but got %v", n)
+	}
+	if n := metrics.Counter("c", nil); n != 1 {
+		T.Errorf("Expect counter without labels to be 1 but got %v", n)
+	}
+	if n := metrics.Counter("unknown", nil); n != 0 {
+		T.Errorf("Expect unknown counter to be 0 but got %v", n)
+	}
+	if count, sum := metrics.Histogram("h", map[string]string{"field": "Query.a"}); count != 3 || sum != 2.55 {
+		T.Errorf("Expect histogram count 3 and sum 2.55 but got %v and %v", count, sum)
+	}
+}
+
+func TestMemoryMetrics_WritePrometheus(T *testing.T) {
+	metrics := NewMemoryMetrics(1, 0.1)
+	metrics.IncCounter("b_total", map[string]string{"z": "1", "a": `quote " and \ and` + "\n"})
+	metrics.IncCounter("b_total", nil)
+	metrics.IncCounter("a_total", nil)
+	metrics.ObserveHistogram("h_seconds", 0.05, map[string]string{"field": "Query.a"})
+	metrics.ObserveHistogram("h_seconds", 0.5, map[string]string{"field": "Query.a"})
+	metrics.ObserveHistogram("h_seconds", 2, map[string]string{"field": "Query.a"})
+	metrics.ObserveHistogram("h_seconds", 1, nil)
+
+	var b strings.Builder
+	if err := metrics.WritePrometheus(&b); err != nil {
+		T.Fatal(err)
+	}
+	// The buckets are sorted and cumulative
+	expected := `# TYPE a_total counter
+a_total 1
+# TYPE b_total counter
+b_total 1
+b_total{a="quote \" and \\ and\n",z="1"} 1
+# TYPE h_seconds histogram
+h_seconds_bucket{le="0.1"} 0
+h_seconds_bucket{le="1"} 1
+h_seconds_bucket{le="+Inf"} 1
+h_seconds_sum 1
+h_seconds_count 1
+h_seconds_bucket{field="Query.a",le="0.1"} 1
+h_seconds_bucket{field="Query.a",le="1"} 2
+h_seconds_bucket{field="Query.a",le="+Inf"} 3
+h_seconds_sum{field="Query.a"} 2.55
+h_seconds_count{field="Query.a"} 3
+`
+	if b.String() != expected {
+		T.Errorf("Expect:\n%v\nbut got:\n%v", expected, b.String())
+	}
+}
+
+func TestMetrics_Requests(T *testing.T) {
+	metrics := NewMemoryMetrics()
+	schema, err := NewSchemaWithOpts(SchemaOpts{Metrics: metrics}, middlewareQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		req, errs := NewRequest(schema, `{ user { name friends { name } } failing }`,
+			RequestOpts{RootValue: middlewareRootValue})
+		if errs != nil {
+			T.Fatal(errs)
+		}
+		req.Execute()
+	}
+	if _, errs := NewRequest(schema, `{ unknown }`); errs == nil {
+		T.Fatal("Expect a validation error")
+	}
+
+	if n := metrics.Counter(MetricOperations, map[string]string{"operation": "query"}); n != 2 {
+		T.Errorf("Expect 2 operations but got %v", n)
+	}
+	if n := metrics.Counter(MetricValidationFailures, nil); n != 1 {
+		T.Errorf("Expect 1 validation failure but got %v", n)
+	}
+	if n := metrics.Counter(MetricResolverErrors, map[string]string{"field": "Query.failing"}); n != 2 {
+		T.Errorf("Expect 2 resolver errors but got %v", n)
+	}
+	if n := metrics.Counter(MetricResolverErrors, map[string]string{"field": "User.name"}); n != 0 {
+		T.Errorf("Expect no resolver errors for User.name but got %v", n)
+	}
+	expected := map[string]uint64{"Query.user": 2, "Query.failing": 2, "User.friends": 2, "User.name": 4}
+	for field, expectedCount := range expected {
+		count, sum := metrics.Histogram(MetricResolverDuration, map[string]string{"field": field})
+		if count != expectedCount || sum < 0 {
+			T.Errorf("Expect %v durations of %v but got %v", expectedCount, field, count)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package ql
+
+var IncludeDirective = Directive{
+	Name:        "include",
+	Description: `Directs the executor to include this field or fragment only when the "if" argument is true.`,
+	Locations: []DirectiveLocation{
+		LocationField,
+		LocationFragmentSpread,
+		LocationInlineFragment,
+	},
+	Args: ArgumentMap{
+		"if": {
+			Type:        NonNull{Boolean},
+			Description: "Included when true.",
+		},
+	},
+}
+
+var SkipDirective = Directive{
+	Name:        "skip",
+	Description: `Directs the executor to skip this field or fragment when the "if" argument is true.`,
+	Locations: []DirectiveLocation{
+		LocationField,
+		LocationFragmentSpread,
+		LocationInlineFragment,
+	},
+	Args: ArgumentMap{
+		"if": {
+			Type:        NonNull{Boolean},
+			Description: "Skipped when true.",
+		},
+	},
+}
@@ -1,5 +1,7 @@
 package ql
 
+import "context"
+
 const (
 	INT     = "IntValue"
 	FLOAT   = "FloatValue"
@@ -52,6 +54,10 @@ type Object struct {
 	// IsTypeOf     func(v interface{}, info *GraphQLResolveInfo) bool
 	IsTypeOf    func(v interface{}, info interface{}) bool
 	Description string
+
+	// Visibility lists the audiences the type is visible to. The type is
+	// visible to every audience when empty. See Schema.ForAudience.
+	Visibility []string
 }
 
 type FieldMap map[string]Field
@@ -61,6 +67,32 @@ type Field struct {
 	Resolve           interface{}
 	DeprecationReason string
 	Description       string
+
+	// Cost of resolving the field once, used to compute the cost of a query.
+	// Zero means the default cost, and FreeCost makes the field free. The
+	// cost may also be given by applying the "cost" directive, with a "value"
+	// argument, as @cost(value: Int!) in SDL.
+	Cost int
+
+	// Visibility lists the audiences the field is visible to. The field is
+	// visible to every audience when empty.
+	Visibility []string
+
+	// Directives applied to the field definition. Their Resolve functions wrap
+	// the resolution of the field, in order, before the directives applied to
+	// the field in the query.
+	Directives []AppliedDirective
+}
+
+/**
+ * The cost of a field which costs nothing to resolve, as a zero Field.Cost
+ * means the default cost.
+ */
+const FreeCost = -1
+
+type AppliedDirective struct {
+	Name string
+	Args map[string]interface{}
 }
 
 type ArgumentMap map[string]Argument
@@ -68,6 +100,7 @@ type Argument struct {
 	Type         InputType
 	DefaultValue interface{}
 	Description  string
+	Visibility   []string
 }
 
 type Scalar struct {
@@ -76,16 +109,20 @@ type Scalar struct {
 	Serialize    func(v interface{}) interface{}
 	ParseValue   func(v interface{}) interface{}
 	ParseLiteral func(kind, value string) interface{}
+	Visibility   []string
 }
 
 type Interfaces []Interface
 type Interface struct {
-	Name       string
-	Fields     FieldMap
-	FieldsFunc func() FieldMap
+	Name           string
+	Interfaces     Interfaces
+	InterfacesFunc func() Interfaces
+	Fields         FieldMap
+	FieldsFunc     func() FieldMap
 	// ResolveType func(v interface{}, info *GraphQLResolveInfo) *GraphQLObjectType
 	ResolveType func(v interface{}, info interface{}) interface{}
 	Description string
+	Visibility  []string
 }
 
 type Union struct {
@@ -94,12 +131,14 @@ type Union struct {
 	// ResolveType func(v interface{}, info *GraphQLResolveInfo) *GraphQLObjectType
 	ResolveType func(v interface{}, info interface{}) interface{}
 	Description string
+	Visibility  []string
 }
 
 type Enum struct {
 	Name        string
 	Values      EnumValueMap
 	Description string
+	Visibility  []string
 }
 
 type EnumValueMap map[string]EnumValue
@@ -107,6 +146,7 @@ type EnumValue struct {
 	Value             interface{}
 	DeprecationReason string
 	Description       string
+	Visibility        []string
 }
 
 type InputObject struct {
@@ -114,6 +154,7 @@ type InputObject struct {
 	Fields      InputObjectFieldMap
 	FieldsFunc  func() InputObjectFieldMap
 	Description string
+	Visibility  []string
 }
 
 type InputObjectFieldMap map[string]InputObjectField
@@ -121,6 +162,7 @@ type InputObjectField struct {
 	Type         InputType
 	DefaultValue interface{}
 	Description  string
+	Visibility   []string
 }
 
 type List struct {
@@ -130,3 +172,89 @@ type List struct {
 type NonNull struct {
 	OfType Type
 }
+
+type DirectiveLocation string
+
+const (
+	// Operations
+	LocationQuery              DirectiveLocation = "QUERY"
+	LocationMutation           DirectiveLocation = "MUTATION"
+	LocationSubscription       DirectiveLocation = "SUBSCRIPTION"
+	LocationField              DirectiveLocation = "FIELD"
+	LocationFragmentDefinition DirectiveLocation = "FRAGMENT_DEFINITION"
+	LocationFragmentSpread     DirectiveLocation = "FRAGMENT_SPREAD"
+	LocationInlineFragment     DirectiveLocation = "INLINE_FRAGMENT"
+	LocationVariableDefinition DirectiveLocation = "VARIABLE_DEFINITION"
+
+	// Schema Definitions
+	LocationSchema               DirectiveLocation = "SCHEMA"
+	LocationScalar               DirectiveLocation = "SCALAR"
+	LocationObject               DirectiveLocation = "OBJECT"
+	LocationFieldDefinition      DirectiveLocation = "FIELD_DEFINITION"
+	LocationArgumentDefinition   DirectiveLocation = "ARGUMENT_DEFINITION"
+	LocationInterface            DirectiveLocation = "INTERFACE"
+	LocationUnion                DirectiveLocation = "UNION"
+	LocationEnum                 DirectiveLocation = "ENUM"
+	LocationEnumValue            DirectiveLocation = "ENUM_VALUE"
+	LocationInputObject          DirectiveLocation = "INPUT_OBJECT"
+	LocationInputFieldDefinition DirectiveLocation = "INPUT_FIELD_DEFINITION"
+)
+
+type Directives []Directive
+type Directive struct {
+	Name        string
+	Description string
+	Locations   []DirectiveLocation
+	Args        ArgumentMap
+
+	// Resolve, when set, wraps the resolution of the fields the directive is
+	// applied to, either in the schema or in the query.
+	Resolve DirectiveResolveFunc
+}
+
+/**
+ * Wraps the resolution of a field. args holds the arguments of the directive
+ * and next resolves the field, including the directives applied before this
+ * one. The returned value replaces the value of the field, and a returned
+ * error is reported as the error of the field.
+ */
+type DirectiveResolveFunc func(
+	ctx context.Context,
+	args map[string]interface{},
+	next func() (interface{}, error),
+) (interface{}, error)
+
+/**
+ * Describes the field being resolved to a Middleware.
+ */
+type ResolveInfo struct {
+	FieldName string
+
+	// Path is the path of the field in the response: the response names of
+	// the fields and the indexes of the list items, from the root.
+	Path []interface{}
+
+	// Args holds the argument values of the field, including the defaults.
+	Args map[string]interface{}
+
+	// ParentType is the name of the object type the field belongs to, and
+	// ReturnType is the type of the field, such as "[String!]".
+	ParentType string
+	ReturnType string
+
+	// Source is the value of the parent object given to the resolver.
+	Source interface{}
+}
+
+/**
+ * Wraps the resolution of every field. next calls the following middleware,
+ * or the directives and the resolver of the field for the last one, with the
+ * context to give them. The returned value replaces the value of the field,
+ * and a returned error is reported as the error of the field. A panic of the
+ * resolver is given to the middlewares as an error.
+ */
+type Middleware func(
+	ctx context.Context,
+	info ResolveInfo,
+	next func(ctx context.Context) (interface{}, error),
+) (interface{}, error)
@@ -0,0 +1,67 @@
+package ql
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warn"
+	case LogError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+/**
+ * Receives the logs of the library. keyvals alternates keys, which are
+ * strings, and values. Nothing is logged when no Logger is set.
+ */
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, msg string, keyvals ...interface{})
+}
+
+type LoggerFunc func(ctx context.Context, level LogLevel, msg string, keyvals ...interface{})
+
+func (f LoggerFunc) Log(ctx context.Context, level LogLevel, msg string, keyvals ...interface{}) {
+	f(ctx, level, msg, keyvals...)
+}
+
+/**
+ * Returns a Logger writing the logs of the given level and above to logger,
+ * as the level, the message and the key=value pairs.
+ */
+func NewStdLogger(logger *log.Logger, minLevel LogLevel) Logger {
+	return LoggerFunc(func(ctx context.Context, level LogLevel, msg string, keyvals ...interface{}) {
+		if level < minLevel {
+			return
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "%v %v", level, msg)
+		for i := 0; i < len(keyvals); i += 2 {
+			if i+1 < len(keyvals) {
+				fmt.Fprintf(&b, " %v=%v", keyvals[i], keyvals[i+1])
+			} else {
+				fmt.Fprintf(&b, " %v", keyvals[i])
+			}
+		}
+		logger.Println(b.String())
+	})
+}
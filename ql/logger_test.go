@@ -0,0 +1,41 @@
+package ql
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+)
+
+func TestLogLevel_String(T *testing.T) {
+	tests := map[LogLevel]string{
+		LogDebug:     "debug",
+		LogInfo:      "info",
+		LogWarn:      "warn",
+		LogError:     "error",
+		LogLevel(10): "level(10)",
+	}
+	for level, expected := range tests {
+		if s := level.String(); s != expected {
+			T.Errorf("Expect %v but got %v", expected, s)
+		}
+	}
+}
+
+func TestNewStdLogger(T *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStdLogger(log.New(&buf, "", 0), LogInfo)
+	ctx := context.Background()
+
+	logger.Log(ctx, LogDebug, "Hidden", "key", "value")
+	logger.Log(ctx, LogInfo, "Message")
+	logger.Log(ctx, LogWarn, "Slow query", "duration", 3, "path", []interface{}{"a", 0})
+	logger.Log(ctx, LogError, "Odd keyvals", "key", 1, "dangling")
+
+	expected := "info Message\n" +
+		"warn Slow query duration=3 path=[a 0]\n" +
+		"error Odd keyvals key=1 dangling\n"
+	if buf.String() != expected {
+		T.Errorf("Expect logs:\n%v\nbut got:\n%v", expected, buf.String())
+	}
+}
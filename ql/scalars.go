@@ -185,7 +185,7 @@ var ID = Scalar{
 	Serialize:  coerceString,
 	ParseValue: coerceString,
 	ParseLiteral: func(kind, value string) interface{} {
-		if kind != STRING || kind != INT {
+		if kind != STRING && kind != INT {
 			return nil
 		}
 		return value
@@ -0,0 +1,35 @@
+package ql
+
+import "testing"
+
+func TestScalars_ParseLiteral(T *testing.T) {
+	tests := []struct {
+		scalar   Scalar
+		kind     string
+		value    string
+		expected interface{}
+	}{
+		{Int, INT, "12", int64(12)},
+		{Int, FLOAT, "1.5", nil},
+		{Int, STRING, "12", nil},
+		{Float, FLOAT, "1.5", 1.5},
+		{Float, INT, "2", float64(2)},
+		{Float, STRING, "1.5", nil},
+		{String, STRING, "abc", "abc"},
+		{String, INT, "12", nil},
+		{Boolean, BOOLEAN, "true", true},
+		{Boolean, STRING, "true", nil},
+
+		// An ID may be written as a string or as an int
+		{ID, STRING, "abc", "abc"},
+		{ID, INT, "12", "12"},
+		{ID, FLOAT, "1.5", nil},
+		{ID, BOOLEAN, "true", nil},
+	}
+	for _, test := range tests {
+		if actual := test.scalar.ParseLiteral(test.kind, test.value); actual != test.expected {
+			T.Errorf("Expect %v to parse %v %q as %#v but got %#v",
+				test.scalar.Name, test.kind, test.value, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package graphql
+
+import (
+	"github.com/ng-vu/graphql-go/internal/language"
+	"github.com/ng-vu/graphql-go/internal/utilities"
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+/**
+ * A schema written in the schema definition language, and the Go functions
+ * implementing it.
+ */
+type SDL struct {
+	// Sources are the documents defining the schema. They are merged into one
+	// schema, so that a type defined in one source can be extended by the
+	// others with `extend type`, `extend interface`, `extend union`,
+	// `extend enum`, `extend input`, `extend scalar` and `extend schema`.
+	Sources []string
+
+	// Resolvers maps the name of an object type to the resolve functions of
+	// its fields, as given to ql.Field.Resolve. The fields without resolver
+	// are resolved from the source value.
+	Resolvers map[string]map[string]interface{}
+
+	// Scalars are the configs of the custom scalars, by name. The values of a
+	// custom scalar without config are given as they are.
+	Scalars map[string]ql.Scalar
+
+	// ResolveTypes are the ResolveType functions of the interfaces and the
+	// unions, by name.
+	ResolveTypes map[string]func(v interface{}, info interface{}) interface{}
+}
+
+func BuildSchema(sdl SDL) (Schema, error) {
+	return BuildSchemaWithOpts(SchemaOpts{}, sdl)
+}
+
+/**
+ * Builds a schema from SDL. The root types are given by the schema definition,
+ * or are the types named Query and Mutation.
+ *
+ * The directives of SchemaOpts.Directives replace the directive definitions of
+ * the same name in the SDL, so that their resolve functions are used. The
+ * returned error lists every syntax error, every conflict between the
+ * definitions and the extensions, and every reference to an unknown type.
+ */
+func BuildSchemaWithOpts(opts SchemaOpts, sdl SDL) (schema Schema, err error) {
+	document := &language.Document{}
+	var errors []error
+	for _, source := range sdl.Sources {
+		sourceDocument, err := language.Parse(language.NewSource(source, ""), language.ParseOptions{})
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		document.Definitions = append(document.Definitions, sourceDocument.Definitions...)
+	}
+	if len(errors) > 0 {
+		return Schema{}, _Errors{errors}
+	}
+
+	built, errors := utilities.BuildASTSchema(document, utilities.BuildASTSchemaConfig{
+		Resolvers:    sdl.Resolvers,
+		Scalars:      sdl.Scalars,
+		ResolveTypes: sdl.ResolveTypes,
+		Directives:   opts.Directives,
+	})
+	if len(errors) > 0 {
+		return Schema{}, _Errors{errors}
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			if e, ok := e.(error); ok {
+				err = e
+				return
+			}
+			panic(e)
+		}
+	}()
+	opts.Directives = built.Directives
+	if built.Mutation != nil {
+		return NewSchemaWithOpts(opts, built.Query, *built.Mutation)
+	}
+	return NewSchemaWithOpts(opts, built.Query)
+}
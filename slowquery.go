@@ -0,0 +1,118 @@
+package graphql
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ng-vu/graphql-go/internal/utilities"
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+type SlowQueryOpts struct {
+	// Threshold is the duration of a request, from the start of NewRequest to
+	// the end of Execute, above which it is reported. Zero disables the slow
+	// query log.
+	Threshold time.Duration
+
+	// MaxResolvers is the number of slowest resolvers reported. Defaults to 5.
+	MaxResolvers int
+
+	// Log receives the slow queries. Defaults to logging them to the logger of
+	// the request at the warn level.
+	Log func(ctx context.Context, query SlowQuery)
+}
+
+/**
+ * A request slower than SlowQueryOpts.Threshold. It does not contain any of
+ * the values given by the user: the literals are stripped from the signature
+ * and only the names of the variables are kept.
+ */
+type SlowQuery struct {
+	// Signature is the operation and the fragments it uses, normalized so
+	// that it is the same for all the requests of the operation. The literals
+	// are replaced by 0, "", [] or {}, the aliases are removed and the
+	// selections and arguments are sorted.
+	Signature string
+
+	OperationName string
+	VariableNames []string
+	Duration      time.Duration
+
+	// SlowestResolvers are sorted by decreasing duration.
+	SlowestResolvers []SlowResolver
+}
+
+type SlowResolver struct {
+	// Path is the path of the field in the response, such as "users.0.name".
+	Path     string
+	Duration time.Duration
+}
+
+/**
+ * Keeps the slowest resolvers of an execution.
+ */
+type _SlowResolvers struct {
+	max int
+
+	m         sync.Mutex
+	resolvers []SlowResolver
+}
+
+func (s *_SlowResolvers) middleware(
+	ctx context.Context,
+	info ql.ResolveInfo,
+	next func(ctx context.Context) (interface{}, error),
+) (interface{}, error) {
+	start := time.Now()
+	defer func() {
+		s.add(info.Path, time.Since(start))
+	}()
+	return next(ctx)
+}
+
+func (s *_SlowResolvers) add(path []interface{}, duration time.Duration) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if len(s.resolvers) == s.max && duration <= s.resolvers[s.max-1].Duration {
+		return
+	}
+	resolver := SlowResolver{Path: formatPath(path), Duration: duration}
+	i := sort.Search(len(s.resolvers), func(i int) bool {
+		return s.resolvers[i].Duration < duration
+	})
+	if len(s.resolvers) < s.max {
+		s.resolvers = append(s.resolvers, SlowResolver{})
+	}
+	copy(s.resolvers[i+1:], s.resolvers[i:])
+	s.resolvers[i] = resolver
+}
+
+func (r *Request) logSlowQuery(ctx context.Context, duration time.Duration, slowResolvers *_SlowResolvers) {
+	operation := r.plan.Operation
+	query := SlowQuery{
+		Signature:        utilities.OperationSignature(r.documentAST, operation),
+		VariableNames:    make([]string, len(operation.VariableDefinitions)),
+		Duration:         duration,
+		SlowestResolvers: slowResolvers.resolvers,
+	}
+	if operation.Name != nil {
+		query.OperationName = operation.Name.Value
+	}
+	for i, variable := range operation.VariableDefinitions {
+		query.VariableNames[i] = variable.Variable.Name.Value
+	}
+
+	if log := r.schemaOpts.SlowQuery.Log; log != nil {
+		log(ctx, query)
+		return
+	}
+	r.log(ql.LogWarn, "Slow query",
+		"signature", query.Signature,
+		"operationName", query.OperationName,
+		"variables", query.VariableNames,
+		"duration", query.Duration,
+		"slowestResolvers", query.SlowestResolvers)
+}
@@ -0,0 +1,114 @@
+package graphql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+func TestSlowResolvers_KeepsTheSlowest(T *testing.T) {
+	s := &_SlowResolvers{max: 3}
+	for i, ms := range []int{5, 1, 7, 3, 9, 2, 7} {
+		s.add([]interface{}{"f", i}, time.Duration(ms)*time.Millisecond)
+	}
+	expected := []SlowResolver{
+		{Path: "f.4", Duration: 9 * time.Millisecond},
+		{Path: "f.2", Duration: 7 * time.Millisecond},
+		{Path: "f.6", Duration: 7 * time.Millisecond},
+	}
+	if !reflect.DeepEqual(s.resolvers, expected) {
+		T.Errorf("Expect resolvers %v but got %v", expected, s.resolvers)
+	}
+}
+
+func TestSlowQuery_Log(T *testing.T) {
+	sleep := func(d time.Duration) func(args struct{}) string {
+		return func(args struct{}) string {
+			time.Sleep(d)
+			return "done"
+		}
+	}
+	var queries []SlowQuery
+	schema, err := NewSchemaWithOpts(SchemaOpts{
+		SlowQuery: SlowQueryOpts{
+			Threshold:    5 * time.Millisecond,
+			MaxResolvers: 2,
+			Log: func(ctx context.Context, query SlowQuery) {
+				queries = append(queries, query)
+			},
+		},
+	}, ql.Object{
+		Name: "Query",
+		Fields: ql.FieldMap{
+			"fast":   {Type: ql.String, Resolve: sleep(0)},
+			"slow":   {Type: ql.String, Resolve: sleep(10 * time.Millisecond)},
+			"slower": {Type: ql.String, Resolve: sleep(20 * time.Millisecond)},
+		},
+	})
+	if err != nil {
+		T.Fatal(err)
+	}
+
+	req, errs := NewRequest(schema, `{ fast }`)
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	req.Execute()
+	if len(queries) != 0 {
+		T.Errorf("Expect fast queries not to be logged but got %v", queries)
+	}
+
+	request := `query Q($a: Boolean!, $b: Boolean = false) { renamed: slow fast slower @skip(if: $b) @include(if: $a) }`
+	req, errs = NewRequest(schema, request, RequestOpts{
+		VariableValues: map[string]interface{}{"a": true},
+	})
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	req.Execute()
+	if len(queries) != 1 {
+		T.Fatalf("Expect the slow query to be logged once but got %v", queries)
+	}
+	query := queries[0]
+	expectedSignature := `query Q($a: Boolean!, $b: Boolean = false) { fast slow slower @include(if: $a) @skip(if: $b) }`
+	if query.Signature != expectedSignature || query.OperationName != "Q" ||
+		!reflect.DeepEqual(query.VariableNames, []string{"a", "b"}) {
+		T.Errorf("Expect the operation to be described but got %+v", query)
+	}
+	if query.Duration < 20*time.Millisecond {
+		T.Errorf("Expect the duration to include the resolvers but got %v", query.Duration)
+	}
+	paths := make([]string, len(query.SlowestResolvers))
+	for i, resolver := range query.SlowestResolvers {
+		paths[i] = resolver.Path
+	}
+	if !reflect.DeepEqual(paths, []string{"slower", "renamed"}) {
+		T.Errorf("Expect the 2 slowest resolvers but got %v", query.SlowestResolvers)
+	}
+}
+
+func TestSlowQuery_LogsToLogger(T *testing.T) {
+	logger := &_LogRecorder{}
+	schema, err := NewSchemaWithOpts(SchemaOpts{
+		Logger:    logger,
+		SlowQuery: SlowQueryOpts{Threshold: time.Nanosecond},
+	}, tracingQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+	req, errs := NewRequest(schema, `{ slow }`)
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	req.Execute()
+	if len(logger.entries) != 1 {
+		T.Fatalf("Expect 1 log but got %v", logger.entries)
+	}
+	entry := logger.entries[0]
+	if entry.level != ql.LogWarn || entry.msg != "Slow query" || entry.keyvals[1] != "{ slow }" {
+		T.Errorf("Expect the slow query to be logged but got %#v", entry)
+	}
+}
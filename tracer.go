@@ -0,0 +1,182 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+/**
+ * A Tracer is given the spans of a request: "graphql.parse" and
+ * "graphql.validate" when the request is created, "graphql.execute" around
+ * the execution of the operation and "graphql.resolve" around the resolution
+ * of each field, as a child of the execution span.
+ *
+ * The context returned by StartSpan is given to the child spans, and to the
+ * middlewares and directives of the field for "graphql.resolve". Tracers are
+ * called concurrently while resolving fields.
+ */
+type Tracer interface {
+	StartSpan(ctx context.Context, name string, attributes map[string]interface{}) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttributes(attributes map[string]interface{})
+	End()
+}
+
+func startSpan(
+	tracer Tracer,
+	ctx context.Context,
+	name string,
+	attributes map[string]interface{},
+) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, _NoopSpan{}
+	}
+	return tracer.StartSpan(ctx, name, attributes)
+}
+
+type _NoopSpan struct{}
+
+func (_NoopSpan) SetAttributes(attributes map[string]interface{}) {}
+func (_NoopSpan) End()                                            {}
+
+/**
+ * The middleware starting a "graphql.resolve" span for each field.
+ */
+func tracerMiddleware(tracer Tracer) ql.Middleware {
+	return func(
+		ctx context.Context,
+		info ql.ResolveInfo,
+		next func(ctx context.Context) (interface{}, error),
+	) (interface{}, error) {
+		ctx, span := tracer.StartSpan(ctx, "graphql.resolve", map[string]interface{}{
+			"graphql.field.name":       info.FieldName,
+			"graphql.field.path":       formatPath(info.Path),
+			"graphql.field.parentType": info.ParentType,
+			"graphql.field.type":       info.ReturnType,
+		})
+		defer span.End()
+
+		result, err := next(ctx)
+		if err != nil {
+			span.SetAttributes(map[string]interface{}{"graphql.error": err.Error()})
+		}
+		return result, err
+	}
+}
+
+func formatPath(path []interface{}) string {
+	parts := make([]string, len(path))
+	for i, key := range path {
+		parts[i] = fmt.Sprint(key)
+	}
+	return strings.Join(parts, ".")
+}
+
+/**
+ * A Tracer keeping the spans in memory, for tests.
+ */
+type RecordingTracer struct {
+	m     sync.Mutex
+	spans []*RecordedSpan
+}
+
+/**
+ * A span recorded by a RecordingTracer. IDs start at 1 and ParentID is 0 for
+ * a span started without a parent span in its context.
+ */
+type RecordedSpan struct {
+	ID         int
+	ParentID   int
+	Name       string
+	Attributes map[string]interface{}
+	StartTime  time.Time
+	EndTime    time.Time
+	Ended      bool
+}
+
+type _RecordingSpanKey struct{}
+
+type _RecordingSpan struct {
+	tracer *RecordingTracer
+	span   *RecordedSpan
+}
+
+func NewRecordingTracer() *RecordingTracer {
+	return &RecordingTracer{}
+}
+
+func (t *RecordingTracer) StartSpan(
+	ctx context.Context,
+	name string,
+	attributes map[string]interface{},
+) (context.Context, Span) {
+	span := &RecordedSpan{
+		Name:       name,
+		Attributes: make(map[string]interface{}, len(attributes)),
+		StartTime:  time.Now(),
+	}
+	for key, value := range attributes {
+		span.Attributes[key] = value
+	}
+	if parent, ok := ctx.Value(_RecordingSpanKey{}).(*RecordedSpan); ok {
+		span.ParentID = parent.ID
+	}
+
+	t.m.Lock()
+	t.spans = append(t.spans, span)
+	span.ID = len(t.spans)
+	t.m.Unlock()
+
+	return context.WithValue(ctx, _RecordingSpanKey{}, span), _RecordingSpan{t, span}
+}
+
+/**
+ * Returns a copy of the spans started so far, in the order they were started.
+ */
+func (t *RecordingTracer) Spans() []RecordedSpan {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	result := make([]RecordedSpan, len(t.spans))
+	for i, span := range t.spans {
+		result[i] = *span
+		result[i].Attributes = make(map[string]interface{}, len(span.Attributes))
+		for key, value := range span.Attributes {
+			result[i].Attributes[key] = value
+		}
+	}
+	return result
+}
+
+/**
+ * Forgets the spans recorded so far.
+ */
+func (t *RecordingTracer) Reset() {
+	t.m.Lock()
+	t.spans = nil
+	t.m.Unlock()
+}
+
+func (s _RecordingSpan) SetAttributes(attributes map[string]interface{}) {
+	s.tracer.m.Lock()
+	for key, value := range attributes {
+		s.span.Attributes[key] = value
+	}
+	s.tracer.m.Unlock()
+}
+
+func (s _RecordingSpan) End() {
+	s.tracer.m.Lock()
+	if !s.span.Ended {
+		s.span.EndTime = time.Now()
+		s.span.Ended = true
+	}
+	s.tracer.m.Unlock()
+}
@@ -0,0 +1,138 @@
+package graphql
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func expectSpan(T *testing.T, span RecordedSpan, parentID int, attributes map[string]interface{}) {
+	if span.ParentID != parentID {
+		T.Errorf("Expect span %v to have parent %v but got %v", span.Name, parentID, span.ParentID)
+	}
+	if !reflect.DeepEqual(span.Attributes, attributes) {
+		T.Errorf("Expect span %v to have attributes:\n%v\nbut got:\n%v", span.Name, attributes, span.Attributes)
+	}
+}
+
+func TestRecordingTracer_Spans(T *testing.T) {
+	tracer := NewRecordingTracer()
+	schema, err := NewSchemaWithOpts(SchemaOpts{Tracer: tracer}, middlewareQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+
+	// The spans of the request are children of the span of its context
+	ctx, rootSpan := tracer.StartSpan(context.Background(), "request", nil)
+	request := `query Q { user { name friends { name } } failing }`
+	req, errs := NewRequest(schema, request, RequestOpts{
+		RootValue: middlewareRootValue,
+		Context:   ctx,
+	})
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	req.Execute()
+	rootSpan.End()
+
+	spans := tracer.Spans()
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name
+		if !span.Ended || span.EndTime.Before(span.StartTime) {
+			T.Errorf("Expect span %v to be ended after it started", span.Name)
+		}
+		if span.ID != i+1 {
+			T.Errorf("Expect span %v to have ID %v but got %v", span.Name, i+1, span.ID)
+		}
+	}
+	expectedNames := []string{"request", "graphql.parse", "graphql.validate", "graphql.execute",
+		"graphql.resolve", "graphql.resolve", "graphql.resolve", "graphql.resolve", "graphql.resolve"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		T.Fatalf("Expect spans %v but got %v", expectedNames, names)
+	}
+
+	parse, validate, execute := spans[1], spans[2], spans[3]
+	expectSpan(T, parse, 1, map[string]interface{}{"graphql.document": request})
+	expectSpan(T, validate, 1, map[string]interface{}{"graphql.errors": 0})
+	expectSpan(T, execute, 1, map[string]interface{}{
+		"graphql.operation.type": "query",
+		"graphql.operation.name": "Q",
+		"graphql.errors":         1,
+	})
+	if validate.StartTime.Before(parse.EndTime) || execute.StartTime.Before(validate.EndTime) {
+		T.Errorf("Expect the parse, validate and execute spans to follow each other")
+	}
+
+	// The resolve spans are children of the execute span
+	resolves := spans[4:]
+	sort.Slice(resolves, func(i, j int) bool {
+		return resolves[i].Attributes["graphql.field.path"].(string) < resolves[j].Attributes["graphql.field.path"].(string)
+	})
+	expectSpan(T, resolves[0], execute.ID, map[string]interface{}{
+		"graphql.field.name":       "failing",
+		"graphql.field.path":       "failing",
+		"graphql.field.parentType": "Query",
+		"graphql.field.type":       "String",
+		"graphql.error":            "resolver failed",
+	})
+	expectSpan(T, resolves[1], execute.ID, map[string]interface{}{
+		"graphql.field.name":       "user",
+		"graphql.field.path":       "user",
+		"graphql.field.parentType": "Query",
+		"graphql.field.type":       "User",
+	})
+	expectSpan(T, resolves[2], execute.ID, map[string]interface{}{
+		"graphql.field.name":       "friends",
+		"graphql.field.path":       "user.friends",
+		"graphql.field.parentType": "User",
+		"graphql.field.type":       "[User]",
+	})
+	expectSpan(T, resolves[3], execute.ID, map[string]interface{}{
+		"graphql.field.name":       "name",
+		"graphql.field.path":       "user.friends.0.name",
+		"graphql.field.parentType": "User",
+		"graphql.field.type":       "String",
+	})
+	expectSpan(T, resolves[4], execute.ID, map[string]interface{}{
+		"graphql.field.name":       "name",
+		"graphql.field.path":       "user.name",
+		"graphql.field.parentType": "User",
+		"graphql.field.type":       "String",
+	})
+	for _, resolve := range resolves {
+		if resolve.StartTime.Before(execute.StartTime) || resolve.EndTime.After(execute.EndTime) {
+			T.Errorf("Expect span %v to be within the execute span", resolve.Attributes["graphql.field.path"])
+		}
+	}
+}
+
+func TestRecordingTracer_InvalidRequests(T *testing.T) {
+	tracer := NewRecordingTracer()
+	schema, err := NewSchema(middlewareQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+
+	// The tracer of the request is used instead of the one of the schema
+	_, errs := NewRequest(schema, `{ user `, RequestOpts{Tracer: tracer})
+	if errs == nil {
+		T.Fatal("Expect a syntax error")
+	}
+	spans := tracer.Spans()
+	if len(spans) != 1 || spans[0].Name != "graphql.parse" || spans[0].Attributes["graphql.error"] == nil {
+		T.Errorf("Expect a parse span with the error but got %v", spans)
+	}
+
+	tracer.Reset()
+	_, errs = NewRequest(schema, `{ unknown other }`, RequestOpts{Tracer: tracer})
+	if errs == nil {
+		T.Fatal("Expect a validation error")
+	}
+	spans = tracer.Spans()
+	if len(spans) != 2 || spans[1].Name != "graphql.validate" {
+		T.Fatalf("Expect the parse and validate spans but got %v", spans)
+	}
+	expectSpan(T, spans[1], 0, map[string]interface{}{"graphql.errors": 2})
+}
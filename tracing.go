@@ -0,0 +1,122 @@
+package graphql
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+/**
+ * Records the timings of a request in the Apollo tracing format, which is
+ * reported in the "tracing" extension of the result. The offsets are relative
+ * to the start of NewRequest and the durations are in nanoseconds.
+ */
+type _Tracing struct {
+	startTime  time.Time
+	parsing    _TracingPhase
+	validation _TracingPhase
+}
+
+type _TracingPhase struct {
+	startOffset time.Duration
+	duration    time.Duration
+}
+
+func newTracing() *_Tracing {
+	return &_Tracing{startTime: time.Now()}
+}
+
+/**
+ * Starts the parsing and validation phases, returning the function ending
+ * them. They do nothing when tracing is disabled.
+ */
+func (t *_Tracing) startParsing() func() {
+	if t == nil {
+		return func() {}
+	}
+	return t.startPhase(&t.parsing)
+}
+
+func (t *_Tracing) startValidation() func() {
+	if t == nil {
+		return func() {}
+	}
+	return t.startPhase(&t.validation)
+}
+
+func (t *_Tracing) startPhase(phase *_TracingPhase) func() {
+	start := time.Now()
+	return func() {
+		phase.startOffset = start.Sub(t.startTime)
+		phase.duration = time.Since(start)
+	}
+}
+
+func (p _TracingPhase) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"startOffset": p.startOffset.Nanoseconds(),
+		"duration":    p.duration.Nanoseconds(),
+	}
+}
+
+/**
+ * The resolver calls recorded while executing a request. A request executed
+ * several times has its resolvers recorded separately for each execution.
+ */
+type _ExecutionTracing struct {
+	*_Tracing
+
+	m         sync.Mutex
+	resolvers []map[string]interface{}
+}
+
+func (t *_Tracing) execution() *_ExecutionTracing {
+	return &_ExecutionTracing{_Tracing: t}
+}
+
+/**
+ * The middleware recording the resolver calls. It is the innermost one, so
+ * that only the time spent in the directives and the resolver is recorded.
+ */
+func (t *_ExecutionTracing) middleware(
+	ctx context.Context,
+	info ql.ResolveInfo,
+	next func(ctx context.Context) (interface{}, error),
+) (interface{}, error) {
+	start := time.Now()
+	defer func() {
+		resolver := map[string]interface{}{
+			"path":        info.Path,
+			"parentType":  info.ParentType,
+			"fieldName":   info.FieldName,
+			"returnType":  info.ReturnType,
+			"startOffset": start.Sub(t.startTime).Nanoseconds(),
+			"duration":    time.Since(start).Nanoseconds(),
+		}
+		t.m.Lock()
+		t.resolvers = append(t.resolvers, resolver)
+		t.m.Unlock()
+	}()
+	return next(ctx)
+}
+
+func (t *_ExecutionTracing) toMap() map[string]interface{} {
+	endTime := time.Now()
+	sort.SliceStable(t.resolvers, func(i, j int) bool {
+		return t.resolvers[i]["startOffset"].(int64) < t.resolvers[j]["startOffset"].(int64)
+	})
+	return map[string]interface{}{
+		"version":    1,
+		"startTime":  t.startTime.UTC().Format(time.RFC3339Nano),
+		"endTime":    endTime.UTC().Format(time.RFC3339Nano),
+		"duration":   endTime.Sub(t.startTime).Nanoseconds(),
+		"parsing":    t.parsing.toMap(),
+		"validation": t.validation.toMap(),
+		"execution": map[string]interface{}{
+			"resolvers": t.resolvers,
+		},
+	}
+}
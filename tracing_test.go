@@ -0,0 +1,138 @@
+package graphql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ng-vu/graphql-go/ql"
+)
+
+var tracingQueryConfig = ql.Object{
+	Name: "Query",
+	Fields: ql.FieldMap{
+		"fast": {Type: ql.String},
+		"slow": {
+			Type: ql.List{OfType: ql.String},
+			Resolve: func(args struct{}) []string {
+				time.Sleep(10 * time.Millisecond)
+				return []string{"a", "b"}
+			},
+		},
+	},
+}
+
+func TestTracing_Shape(T *testing.T) {
+	schema, err := NewSchemaWithOpts(SchemaOpts{Tracing: true}, tracingQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+
+	before := time.Now()
+	req, errs := NewRequest(schema, `{ fast slow }`, RequestOpts{
+		RootValue: map[string]interface{}{"fast": "f"},
+	})
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	result := req.Execute()
+	elapsed := time.Since(before).Nanoseconds()
+	expectResultData(T, result, map[string]interface{}{
+		"fast": "f",
+		"slow": []interface{}{"a", "b"},
+	})
+
+	tracing, ok := result.Extensions["tracing"].(map[string]interface{})
+	if !ok {
+		T.Fatalf("Expect the tracing extension but got %v", result.Extensions)
+	}
+	if tracing["version"] != 1 {
+		T.Errorf("Expect version 1 but got %v", tracing["version"])
+	}
+	startTime, err := time.Parse(time.RFC3339Nano, tracing["startTime"].(string))
+	if err != nil {
+		T.Errorf("Expect startTime in RFC 3339 format: %v", err)
+	}
+	endTime, err := time.Parse(time.RFC3339Nano, tracing["endTime"].(string))
+	if err != nil {
+		T.Errorf("Expect endTime in RFC 3339 format: %v", err)
+	}
+	if startTime.Before(before.Truncate(time.Microsecond)) || endTime.Before(startTime) {
+		T.Errorf("Expect startTime %v and endTime %v to be after %v", startTime, endTime, before)
+	}
+	duration := tracing["duration"].(int64)
+	if duration < (10*time.Millisecond).Nanoseconds() || duration > elapsed {
+		T.Errorf("Expect duration %v to include the slow resolver, within %v", duration, elapsed)
+	}
+
+	// The phases follow each other, with offsets relative to startTime
+	parsing := tracing["parsing"].(map[string]interface{})
+	validation := tracing["validation"].(map[string]interface{})
+	parsingEnd := parsing["startOffset"].(int64) + parsing["duration"].(int64)
+	validationEnd := validation["startOffset"].(int64) + validation["duration"].(int64)
+	if parsing["startOffset"].(int64) < 0 || validation["startOffset"].(int64) < parsingEnd {
+		T.Errorf("Expect validation %v to start after parsing %v", validation, parsing)
+	}
+
+	resolvers := tracing["execution"].(map[string]interface{})["resolvers"].([]map[string]interface{})
+	if len(resolvers) != 2 {
+		T.Fatalf("Expect 2 resolvers but got %v", resolvers)
+	}
+	byField := make(map[string]map[string]interface{})
+	for _, resolver := range resolvers {
+		byField[resolver["fieldName"].(string)] = resolver
+
+		startOffset := resolver["startOffset"].(int64)
+		if startOffset < validationEnd || startOffset+resolver["duration"].(int64) > duration {
+			T.Errorf("Expect resolver %v to run between the validation and the end of the request", resolver)
+		}
+	}
+	slow := byField["slow"]
+	expected := map[string]interface{}{
+		"path":        []interface{}{"slow"},
+		"parentType":  "Query",
+		"fieldName":   "slow",
+		"returnType":  "[String]",
+		"startOffset": slow["startOffset"],
+		"duration":    slow["duration"],
+	}
+	if !reflect.DeepEqual(slow, expected) {
+		T.Errorf("Expect resolver:\n%v\nbut got:\n%v", expected, slow)
+	}
+	if slow["duration"].(int64) < (10 * time.Millisecond).Nanoseconds() {
+		T.Errorf("Expect the duration of the slow resolver to include its sleep but got %v", slow["duration"])
+	}
+	if byField["fast"]["path"].([]interface{})[0] != "fast" {
+		T.Errorf("Expect the path of the fast resolver but got %v", byField["fast"])
+	}
+}
+
+func TestTracing_PerRequest(T *testing.T) {
+	schema, err := NewSchema(tracingQueryConfig)
+	if err != nil {
+		T.Fatal(err)
+	}
+	req, errs := NewRequest(schema, `{ fast }`)
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	if result := req.Execute(); result.Extensions["tracing"] != nil {
+		T.Errorf("Expect no tracing by default but got %v", result.Extensions["tracing"])
+	}
+
+	req, errs = NewRequest(schema, `{ fast }`, RequestOpts{Tracing: true})
+	if errs != nil {
+		T.Fatal(errs)
+	}
+	// Each execution of the request has its own resolvers
+	for i := 0; i < 2; i++ {
+		tracing, ok := req.Execute().Extensions["tracing"].(map[string]interface{})
+		if !ok {
+			T.Fatalf("Expect the tracing extension to be enabled by the request")
+		}
+		resolvers := tracing["execution"].(map[string]interface{})["resolvers"].([]map[string]interface{})
+		if len(resolvers) != 1 {
+			T.Errorf("Expect 1 resolver but got %v", resolvers)
+		}
+	}
+}